@@ -8,16 +8,11 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/cockroachdb/errors"
 	"github.com/lexfrei/go-hangar/internal/cli"
 )
 
 func main() {
-	// Setup structured logging
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-	slog.SetDefault(logger)
-
 	// Create context with cancellation for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -34,6 +29,10 @@ func main() {
 
 	// Execute CLI
 	if err := cli.Execute(ctx); err != nil {
+		var exitErr *cli.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		slog.Error("command failed", "error", err)
 		os.Exit(1)
 	}
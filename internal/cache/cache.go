@@ -0,0 +1,98 @@
+// Package cache stores small JSON values on disk for a limited time,
+// keeping repeated shell completions fast without hitting the API.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Cache is a directory of JSON entries that expire after a fixed time to live.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// New returns a cache storing entries in dir for ttl.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// DefaultDir returns the hangar directory below the user cache directory.
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get cache directory")
+	}
+
+	return filepath.Join(base, "hangar"), nil
+}
+
+// entry is the stored representation of a value.
+type entry struct {
+	Expires time.Time       `json:"expires"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// Get decodes the entry stored under key into value. It reports false if the entry
+// is missing, expired or unreadable.
+func (c *Cache) Get(key string, value any) bool {
+	raw, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil || c.now().After(e.Expires) {
+		return false
+	}
+
+	return json.Unmarshal(e.Value, value) == nil
+}
+
+// Set stores value under key. The file is written atomically so concurrent
+// completions never read a partial entry.
+func (c *Cache) Set(key string, value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode cache entry")
+	}
+
+	raw, err := json.Marshal(entry{Expires: c.now().Add(c.ttl), Value: encoded})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode cache entry")
+	}
+
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return errors.Wrap(err, "failed to create cache directory")
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "entry-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to write cache entry")
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "failed to write cache entry")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write cache entry")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), c.path(key)), "failed to write cache entry")
+}
+
+// path returns the file of an entry. Keys are hashed so they may contain any characters.
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
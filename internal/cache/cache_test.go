@@ -0,0 +1,67 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_SetGet(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New(filepath.Join(t.TempDir(), "nested"), time.Minute)
+
+	var missing []string
+	assert.False(t, c.Get("projects:ess", &missing))
+
+	require.NoError(t, c.Set("projects:ess", []string{"EssentialsX", "Essentials"}))
+	require.NoError(t, c.Set("users:ess", []string{"essentials-team"}))
+
+	var got []string
+	require.True(t, c.Get("projects:ess", &got))
+	assert.Equal(t, []string{"EssentialsX", "Essentials"}, got)
+
+	require.True(t, c.Get("users:ess", &got))
+	assert.Equal(t, []string{"essentials-team"}, got)
+}
+
+func TestCache_Expired(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New(t.TempDir(), -time.Second)
+	require.NoError(t, c.Set("key", 42))
+
+	var got int
+	assert.False(t, c.Get("key", &got))
+}
+
+func TestCache_Corrupt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	c := cache.New(dir, time.Minute)
+	require.NoError(t, c.Set("key", "value"))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, entries[0].Name()), []byte("{"), 0o600))
+
+	var got string
+	assert.False(t, c.Get("key", &got))
+}
+
+func TestCache_TypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	c := cache.New(t.TempDir(), time.Minute)
+	require.NoError(t, c.Set("key", "value"))
+
+	var got int
+	assert.False(t, c.Get("key", &got))
+}
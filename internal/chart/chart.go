@@ -0,0 +1,407 @@
+// Package chart renders simple text charts (sparklines, bar and line charts) for terminal output.
+package chart
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/term"
+)
+
+// Kind is the type of chart to render.
+type Kind string
+
+const (
+	// KindSpark renders a single-line sparkline per series.
+	KindSpark Kind = "spark"
+	// KindBar renders one horizontal bar per data point.
+	KindBar Kind = "bar"
+	// KindLine renders a multi-row plot of one or more series.
+	KindLine Kind = "line"
+)
+
+const (
+	// DefaultWidth is used when the terminal width cannot be detected.
+	DefaultWidth = 80
+	// DefaultHeight is the default number of rows of a line chart.
+	DefaultHeight = 12
+)
+
+var (
+	unicodeLevels = []rune("▁▂▃▄▅▆▇█")
+	asciiLevels   = []rune("_.-~=+*#")
+
+	unicodeMarkers = []rune("●◆▲■")
+	asciiMarkers   = []rune("*o+x")
+)
+
+// ParseKind converts a string such as "bar" into a Kind.
+func ParseKind(s string) (Kind, error) {
+	switch kind := Kind(s); kind {
+	case KindSpark, KindBar, KindLine:
+		return kind, nil
+	default:
+		return "", errors.Newf("unknown chart type %q (expected spark, bar or line)", s)
+	}
+}
+
+// Series is a named list of values sharing the labels of the chart.
+type Series struct {
+	// Name is shown in the legend.
+	Name string
+	// Values are the data points, one per label.
+	Values []float64
+}
+
+// Options controls chart rendering.
+type Options struct {
+	// Width is the total number of columns available.
+	Width int
+	// Height is the number of plot rows of a line chart.
+	Height int
+	// ASCII restricts output to ASCII characters.
+	ASCII bool
+}
+
+// Terminal returns options sized for w: the terminal width and Unicode glyphs when w is a TTY,
+// otherwise DefaultWidth (or $COLUMNS) and ASCII glyphs.
+func Terminal(w io.Writer) Options {
+	opts := Options{Width: DefaultWidth, Height: DefaultHeight, ASCII: true}
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		opts.Width = columns
+	}
+
+	file, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(file.Fd())) {
+		return opts
+	}
+
+	opts.ASCII = false
+	if width, _, err := term.GetSize(int(file.Fd())); err == nil && width > 0 {
+		opts.Width = width
+	}
+
+	return opts
+}
+
+// Summary holds the values shown in a chart legend.
+type Summary struct {
+	Min float64
+	Max float64
+	Avg float64
+}
+
+// Summarize computes the minimum, maximum and average of values.
+func Summarize(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+
+	summary := Summary{Min: math.Inf(1), Max: math.Inf(-1)}
+	var sum float64
+	for _, v := range values {
+		summary.Min = math.Min(summary.Min, v)
+		summary.Max = math.Max(summary.Max, v)
+		sum += v
+	}
+	summary.Avg = sum / float64(len(values))
+
+	return summary
+}
+
+// String formats the summary as a legend line.
+func (s Summary) String() string {
+	return fmt.Sprintf("min %s  max %s  avg %s", formatValue(s.Min), formatValue(s.Max), formatValue(s.Avg))
+}
+
+// Sparkline renders values as a single line of block characters, resampled to at most width columns.
+func Sparkline(values []float64, width int, ascii bool) string {
+	levels := unicodeLevels
+	if ascii {
+		levels = asciiLevels
+	}
+
+	values = Resample(values, width)
+	summary := Summarize(values)
+
+	var sb strings.Builder
+	for _, v := range values {
+		sb.WriteRune(levels[scale(v, summary.Min, summary.Max, len(levels)-1)])
+	}
+
+	return sb.String()
+}
+
+// Render writes a chart of the given kind.
+func Render(w io.Writer, kind Kind, labels []string, series []Series, opts Options) error {
+	switch kind {
+	case KindSpark:
+		return Spark(w, series, opts)
+	case KindBar:
+		return Bar(w, labels, series, opts)
+	case KindLine:
+		return Line(w, labels, series, opts)
+	default:
+		return errors.Newf("unknown chart type %q", kind)
+	}
+}
+
+// Spark writes one sparkline per series followed by its legend.
+func Spark(w io.Writer, series []Series, opts Options) error {
+	nameWidth := maxNameWidth(series)
+
+	for _, s := range series {
+		lineWidth := max(opts.Width-nameWidth-1, 1)
+		if _, err := fmt.Fprintf(w, "%-*s %s\n%-*s %s\n", nameWidth, s.Name,
+			Sparkline(s.Values, lineWidth, opts.ASCII), nameWidth, "", Summarize(s.Values)); err != nil {
+			return errors.Wrap(err, "failed to write chart")
+		}
+	}
+
+	return nil
+}
+
+// Bar writes a horizontal bar chart with one row per label for each series.
+func Bar(w io.Writer, labels []string, series []Series, opts Options) error {
+	fill, partial := "█", []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+	if opts.ASCII {
+		fill, partial = "#", []string{""}
+	}
+
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = max(labelWidth, len(label))
+	}
+
+	for idx, s := range series {
+		if idx > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return errors.Wrap(err, "failed to write chart")
+			}
+		}
+
+		summary := Summarize(s.Values)
+		valueWidth := len(formatValue(summary.Max))
+		barWidth := max(opts.Width-labelWidth-valueWidth-2, 1)
+
+		if _, err := fmt.Fprintln(w, s.Name); err != nil {
+			return errors.Wrap(err, "failed to write chart")
+		}
+
+		for i, v := range s.Values {
+			label := ""
+			if i < len(labels) {
+				label = labels[i]
+			}
+
+			// Bars are measured in sub-cell steps when partial glyphs are available
+			steps := len(partial)
+			length := 0
+			if summary.Max > 0 {
+				length = int(math.Round(math.Max(v, 0) / summary.Max * float64(barWidth*steps)))
+			}
+			bar := strings.Repeat(fill, length/steps) + partial[length%steps]
+
+			if _, err := fmt.Fprintf(w, "%-*s %*s %s\n", labelWidth, label, valueWidth, formatValue(v), bar); err != nil {
+				return errors.Wrap(err, "failed to write chart")
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%s\n", summary); err != nil {
+			return errors.Wrap(err, "failed to write chart")
+		}
+	}
+
+	return nil
+}
+
+// Line writes a plot of all series on a shared vertical scale, resampled to the available width.
+func Line(w io.Writer, labels []string, series []Series, opts Options) error {
+	if len(series) == 0 {
+		return nil
+	}
+
+	height := opts.Height
+	if height < 2 {
+		height = DefaultHeight
+	}
+
+	markers, vertical := unicodeMarkers, '│'
+	if opts.ASCII {
+		markers, vertical = asciiMarkers, '|'
+	}
+
+	var all []float64
+	for _, s := range series {
+		all = append(all, s.Values...)
+	}
+	summary := Summarize(all)
+
+	axisWidth := max(len(formatValue(summary.Max)), len(formatValue(summary.Min)))
+	plotWidth := max(opts.Width-axisWidth-2, 1)
+
+	points := 0
+	for _, s := range series {
+		points = max(points, len(s.Values))
+	}
+
+	// Short series are stretched so that each point spans an equal number of columns
+	stretch := 1
+	if points > 0 && points < plotWidth {
+		stretch = plotWidth / points
+	}
+	plotWidth = min(plotWidth, points*stretch)
+
+	grid := make([][]rune, height)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", plotWidth))
+	}
+
+	for idx, s := range series {
+		marker := markers[idx%len(markers)]
+		prev := -1
+
+		for col, v := range Resample(Stretch(s.Values, stretch), plotWidth) {
+			row := height - 1 - scale(v, summary.Min, summary.Max, height-1)
+
+			// Connect consecutive points so the series reads as a line
+			if prev >= 0 && prev != row {
+				from, to := min(prev, row), max(prev, row)
+				for r := from + 1; r < to; r++ {
+					if grid[r][col] == ' ' {
+						grid[r][col] = vertical
+					}
+				}
+			}
+
+			grid[row][col] = marker
+			prev = row
+		}
+	}
+
+	for row, cells := range grid {
+		axis := ""
+		switch row {
+		case 0:
+			axis = formatValue(summary.Max)
+		case height - 1:
+			axis = formatValue(summary.Min)
+		}
+
+		if _, err := fmt.Fprintf(w, "%*s %c%s\n", axisWidth, axis, vertical, strings.TrimRight(string(cells), " ")); err != nil {
+			return errors.Wrap(err, "failed to write chart")
+		}
+	}
+
+	if err := writeAxis(w, labels, axisWidth, plotWidth, opts.ASCII); err != nil {
+		return err
+	}
+
+	for idx, s := range series {
+		if _, err := fmt.Fprintf(w, "%c %s: %s\n", markers[idx%len(markers)], s.Name, Summarize(s.Values)); err != nil {
+			return errors.Wrap(err, "failed to write chart")
+		}
+	}
+
+	return nil
+}
+
+// writeAxis writes the horizontal axis with the first and last labels.
+func writeAxis(w io.Writer, labels []string, axisWidth, plotWidth int, ascii bool) error {
+	corner, horizontal := "└", "─"
+	if ascii {
+		corner, horizontal = "+", "-"
+	}
+
+	if _, err := fmt.Fprintf(w, "%*s %s%s\n", axisWidth, "", corner, strings.Repeat(horizontal, plotWidth)); err != nil {
+		return errors.Wrap(err, "failed to write chart")
+	}
+
+	if len(labels) == 0 {
+		return nil
+	}
+
+	first, last := labels[0], labels[len(labels)-1]
+	gap := plotWidth - len(first) - len(last)
+	line := first
+	if gap > 0 {
+		line += strings.Repeat(" ", gap) + last
+	}
+
+	if _, err := fmt.Fprintf(w, "%*s  %s\n", axisWidth, "", line); err != nil {
+		return errors.Wrap(err, "failed to write chart")
+	}
+
+	return nil
+}
+
+// Resample reduces values to at most width points by averaging consecutive buckets.
+func Resample(values []float64, width int) []float64 {
+	if width <= 0 || len(values) <= width {
+		return values
+	}
+
+	resampled := make([]float64, width)
+	for i := range resampled {
+		start := i * len(values) / width
+		end := (i + 1) * len(values) / width
+
+		var sum float64
+		for _, v := range values[start:end] {
+			sum += v
+		}
+		resampled[i] = sum / float64(end-start)
+	}
+
+	return resampled
+}
+
+// Stretch repeats every value factor times.
+func Stretch(values []float64, factor int) []float64 {
+	if factor <= 1 {
+		return values
+	}
+
+	stretched := make([]float64, 0, len(values)*factor)
+	for _, v := range values {
+		for range factor {
+			stretched = append(stretched, v)
+		}
+	}
+
+	return stretched
+}
+
+// scale maps v from [low, high] onto [0, steps].
+func scale(v, low, high float64, steps int) int {
+	if high <= low {
+		return 0
+	}
+
+	return int(math.Round((v - low) / (high - low) * float64(steps)))
+}
+
+// maxNameWidth returns the length of the longest series name.
+func maxNameWidth(series []Series) int {
+	width := 0
+	for _, s := range series {
+		width = max(width, len(s.Name))
+	}
+
+	return width
+}
+
+// formatValue formats a value without a fractional part when it is an integer.
+func formatValue(v float64) string {
+	if v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
@@ -0,0 +1,125 @@
+package chart_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lexfrei/go-hangar/internal/chart"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparkline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		ascii  bool
+		want   string
+	}{
+		{name: "unicode", values: []float64{0, 1, 2, 3, 4, 5, 6, 7}, width: 80, want: "▁▂▃▄▅▆▇█"},
+		{name: "ascii", values: []float64{0, 7, 0}, width: 80, ascii: true, want: "_#_"},
+		{name: "flat", values: []float64{5, 5, 5}, width: 80, want: "▁▁▁"},
+		{name: "resampled", values: []float64{0, 0, 10, 10}, width: 2, ascii: true, want: "_#"},
+		{name: "empty", values: nil, width: 80, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, chart.Sparkline(tt.values, tt.width, tt.ascii))
+		})
+	}
+}
+
+func TestResample(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []float64{1.5, 3.5}, chart.Resample([]float64{1, 2, 3, 4}, 2))
+	assert.Equal(t, []float64{1, 2}, chart.Resample([]float64{1, 2}, 10))
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	summary := chart.Summarize([]float64{2, 4, 9})
+
+	assert.Equal(t, chart.Summary{Min: 2, Max: 9, Avg: 5}, summary)
+	assert.Equal(t, "min 2  max 9  avg 5", summary.String())
+}
+
+func TestParseKind(t *testing.T) {
+	t.Parallel()
+
+	kind, err := chart.ParseKind("bar")
+	require.NoError(t, err)
+	assert.Equal(t, chart.KindBar, kind)
+
+	_, err = chart.ParseKind("pie")
+	assert.Error(t, err)
+}
+
+func TestBar_ASCII(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := chart.Bar(&buf, []string{"mon", "tue"}, []chart.Series{
+		{Name: "Downloads", Values: []float64{5, 10}},
+	}, chart.Options{Width: 20, ASCII: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Downloads\nmon  5 #######\ntue 10 #############\nmin 5  max 10  avg 7.5\n", buf.String())
+}
+
+func TestLine_FitsWidth(t *testing.T) {
+	t.Parallel()
+
+	values := make([]float64, 200)
+	for i := range values {
+		values[i] = float64(i % 17)
+	}
+
+	var buf bytes.Buffer
+	err := chart.Line(&buf, []string{"first", "last"}, []chart.Series{
+		{Name: "Downloads", Values: values},
+		{Name: "Views", Values: values},
+	}, chart.Options{Width: 60, Height: 6, ASCII: true})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 6+2+2)
+
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 60, line)
+	}
+
+	assert.True(t, strings.HasPrefix(lines[0], "16 |"))
+	assert.Contains(t, lines[7], "first")
+	assert.Contains(t, lines[7], "last")
+	assert.Equal(t, "* Downloads: min 0  max 16  avg 7.9", lines[8])
+	assert.True(t, strings.HasPrefix(lines[9], "o Views"))
+}
+
+func TestRender_UnknownKind(t *testing.T) {
+	t.Parallel()
+
+	err := chart.Render(&bytes.Buffer{}, chart.Kind("pie"), nil, nil, chart.Options{})
+
+	assert.Error(t, err)
+}
+
+func TestLine_StretchesShortSeries(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := chart.Line(&buf, []string{"a", "b"}, []chart.Series{
+		{Name: "Downloads", Values: []float64{1, 2}},
+	}, chart.Options{Width: 13, Height: 2, ASCII: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2 |     *****\n1 |*****\n  +----------\n   a        b\n* Downloads: min 1  max 2  avg 1.5\n", buf.String())
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/cockroachdb/errors"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+var projectAdoptionCmd = &cobra.Command{
+	Use:   "adoption <slug>",
+	Short: "Show version adoption and platform share",
+	Long: `Fetch per-version statistics of a project and show how quickly new versions were adopted.
+
+The report contains each version's share of the project's downloads over time, the number
+of days from release until a version first reached the adoption threshold (50% of daily
+downloads by default) and the all-time download split across platforms.
+
+Shares are relative to the downloads of all versions of the project, including versions
+left out by --versions.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSlugs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		fromDate, _ := cmd.Flags().GetString("from")
+		toDate, _ := cmd.Flags().GetString("to")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		limit, _ := cmd.Flags().GetInt("versions")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+		period, err := hangar.ParseStatsPeriod(groupBy)
+		if err != nil {
+			return errors.Wrap(err, "invalid --group-by value")
+		}
+
+		client := createClient()
+
+		project, err := client.GetProject(ctx, args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to get project")
+		}
+		slug := project.Namespace.Slug
+
+		versions, err := client.ListAllVersions(ctx, project.Namespace.Owner, slug)
+		if err != nil {
+			return errors.Wrap(err, "failed to list versions")
+		}
+
+		// Keep only the newest versions
+		sort.SliceStable(versions, func(i, j int) bool {
+			return versions[i].CreatedAt.After(versions[j].CreatedAt)
+		})
+		if limit > 0 && len(versions) > limit {
+			versions = versions[:limit]
+		}
+
+		// Fetch the project stats, used as the total for shares, and per-version stats concurrently
+		var projectStats hangar.ProjectStats
+		stats := make([]hangar.VersionStatsData, len(versions))
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(max(concurrency, 1))
+		group.Go(func() error {
+			var err error
+			projectStats, err = client.GetProjectStats(groupCtx, slug, fromDate, toDate)
+			return errors.Wrap(err, "failed to get project stats")
+		})
+		for i, version := range versions {
+			group.Go(func() error {
+				versionStats, err := client.GetVersionStats(groupCtx, slug, version.Name, fromDate, toDate)
+				if err != nil {
+					return errors.Wrapf(err, "failed to get stats for version %s", version.Name)
+				}
+				stats[i] = versionStats
+				return nil
+			})
+		}
+		if err := group.Wait(); err != nil {
+			return err
+		}
+
+		byName := make(map[string]hangar.VersionStatsData, len(versions))
+		for i, version := range versions {
+			byName[version.Name] = stats[i]
+		}
+
+		report, err := hangar.BuildAdoption(versions, byName, projectStats, period, threshold)
+		if err != nil {
+			return errors.Wrap(err, "failed to build adoption report")
+		}
+
+		columns := adoptionColumns(report.Threshold)
+
+		return render(cmd, output.View[hangar.VersionAdoption]{
+			Data:    report,
+			Items:   report.Versions,
+			Columns: columns,
+			Text: func(w io.Writer) error {
+				err := output.Render(w, output.Options{Format: output.FormatTable}, output.View[hangar.VersionAdoption]{
+					Items:   report.Versions,
+					Columns: columns,
+				})
+				if err != nil {
+					return err
+				}
+				renderAdoptionShares(w, report)
+				renderPlatformSplit(w, report)
+				return nil
+			},
+		})
+	},
+}
+
+// adoptionColumns returns the columns of the per-version adoption summary.
+func adoptionColumns(threshold float64) []output.Column[hangar.VersionAdoption] {
+	return []output.Column[hangar.VersionAdoption]{
+		{Name: "version", Header: "Version", Value: func(v hangar.VersionAdoption) any { return v.Version }},
+		{Name: "channel", Header: "Channel", Value: func(v hangar.VersionAdoption) any { return v.Channel }},
+		{Name: "releasedAt", Header: "Released", Value: func(v hangar.VersionAdoption) any { return v.ReleasedAt }},
+		{Name: "downloads", Header: "Downloads", Value: func(v hangar.VersionAdoption) any { return v.Downloads }},
+		{
+			Name:   "totalDownloads",
+			Header: "Total Downloads",
+			Value:  func(v hangar.VersionAdoption) any { return v.TotalDownloads },
+		},
+		{
+			Name:   "peakShare",
+			Header: "Peak Share",
+			Value:  func(v hangar.VersionAdoption) any { return v.PeakShare },
+			Format: func(v hangar.VersionAdoption) string { return fmt.Sprintf("%.1f%%", v.PeakShare) },
+		},
+		{
+			Name:   "daysToThreshold",
+			Header: fmt.Sprintf("Days to %g%%", threshold),
+			Value:  func(v hangar.VersionAdoption) any { return v.DaysToThreshold },
+		},
+	}
+}
+
+// renderAdoptionShares prints the share of every version per period.
+func renderAdoptionShares(w io.Writer, report *hangar.AdoptionReport) {
+	if len(report.Periods) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(w)
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	header := table.Row{"Period", "Downloads"}
+	for _, version := range report.Versions {
+		header = append(header, version.Version)
+	}
+	t.AppendHeader(header)
+
+	for _, period := range report.Periods {
+		row := table.Row{period.Period, period.Downloads}
+		for _, version := range report.Versions {
+			share, ok := period.Shares[version.Version]
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, fmt.Sprintf("%.1f%%", share))
+		}
+		t.AppendRow(row)
+	}
+
+	t.Render()
+
+	if !report.ProjectTotals {
+		_, _ = fmt.Fprintln(w, "\nNo project statistics in range: shares only cover the analyzed versions.")
+	}
+}
+
+// renderPlatformSplit prints the all-time downloads per platform.
+func renderPlatformSplit(w io.Writer, report *hangar.AdoptionReport) {
+	if len(report.PlatformDownloads) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(w)
+
+	var total int64
+	for _, downloads := range report.PlatformDownloads {
+		total += downloads
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"Platform", "Downloads", "Share"})
+	for _, platform := range hangar.SortPlatforms(report.PlatformDownloads) {
+		downloads := report.PlatformDownloads[platform]
+		share := 0.0
+		if total > 0 {
+			share = float64(downloads) / float64(total) * 100
+		}
+		t.AppendRow(table.Row{platform, downloads, fmt.Sprintf("%.1f%%", share)})
+	}
+	t.AppendFooter(table.Row{"Total", total, ""})
+
+	t.Render()
+}
+
+func init() {
+	projectCmd.AddCommand(projectAdoptionCmd)
+
+	// Adoption command flags
+	projectAdoptionCmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
+	projectAdoptionCmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+	projectAdoptionCmd.Flags().String("group-by", "week", "Aggregate shares by period (day, week, month, year)")
+	_ = projectAdoptionCmd.RegisterFlagCompletionFunc("group-by", completeGroupBy)
+	projectAdoptionCmd.Flags().Float64("threshold", 50, "Share of daily downloads in percent at which a version counts as adopted")
+	projectAdoptionCmd.Flags().Int("versions", 10, "Number of most recent versions to analyze (0 for all)")
+	projectAdoptionCmd.Flags().Int("concurrency", 4, "Maximum number of concurrent requests")
+}
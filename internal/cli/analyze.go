@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/lexfrei/go-hangar/internal/forecast"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/spf13/cobra"
+)
+
+// analyzeAnomaly is an anomaly tagged with its project and metric for alerting pipelines.
+type analyzeAnomaly struct {
+	Slug   string `json:"slug"`
+	Metric string `json:"metric"`
+	forecast.Anomaly
+}
+
+// analyzeReport is the full result of a stats analysis.
+type analyzeReport struct {
+	Slug       string                `json:"slug"`
+	Metric     string                `json:"metric"`
+	Days       int                   `json:"days"`
+	Threshold  float64               `json:"threshold"`
+	Confidence float64               `json:"confidence"`
+	Model      *forecast.Model       `json:"model"`
+	Anomalies  []analyzeAnomaly      `json:"anomalies"`
+	Forecast   []forecast.Prediction `json:"forecast"`
+}
+
+var statsAnalyzeCmd = &cobra.Command{
+	Use:   "analyze <slug>",
+	Short: "Forecast project statistics and detect anomalies",
+	Long: `Fit a linear trend with weekly seasonality to a project's daily statistics,
+forecast the next days with confidence bands and flag days whose deviation from the
+model exceeds a z-score threshold (spikes from bots or mirrors, drops from broken releases).
+
+Use --anomalies-only -o json to feed alerting, and --fail-on-anomaly to exit non-zero
+when anomalies are found.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSlugs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		slug := args[0]
+
+		fromDate, _ := cmd.Flags().GetString("from")
+		toDate, _ := cmd.Flags().GetString("to")
+		metric, _ := cmd.Flags().GetString("metric")
+		horizon, _ := cmd.Flags().GetInt("forecast")
+		confidence, _ := cmd.Flags().GetFloat64("confidence")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		anomaliesOnly, _ := cmd.Flags().GetBool("anomalies-only")
+		failOnAnomaly, _ := cmd.Flags().GetBool("fail-on-anomaly")
+
+		if metric != "downloads" && metric != "views" {
+			return errors.Newf("unsupported metric %q (expected downloads or views)", metric)
+		}
+		if threshold <= 0 {
+			return errors.Newf("--threshold must be positive, got %g", threshold)
+		}
+
+		client := createClient()
+		stats, err := client.GetProjectStats(ctx, slug, fromDate, toDate)
+		if err != nil {
+			return errors.Wrap(err, "failed to get project stats")
+		}
+
+		series, err := stats.Series()
+		if err != nil {
+			return errors.Wrap(err, "failed to parse stats")
+		}
+
+		points := statsPoints(series.Fill(), metric)
+		model, err := forecast.Fit(points)
+		if err != nil {
+			return errors.Wrap(err, "failed to fit model")
+		}
+
+		predictions, err := model.Forecast(horizon, confidence)
+		if err != nil {
+			return errors.Wrap(err, "failed to forecast")
+		}
+
+		report := analyzeReport{
+			Slug:       slug,
+			Metric:     metric,
+			Days:       len(points),
+			Threshold:  threshold,
+			Confidence: confidence,
+			Model:      model,
+			Anomalies:  []analyzeAnomaly{},
+			Forecast:   predictions,
+		}
+		for _, anomaly := range model.Anomalies(points, threshold) {
+			report.Anomalies = append(report.Anomalies, analyzeAnomaly{Slug: slug, Metric: metric, Anomaly: anomaly})
+		}
+
+		view := output.View[analyzeAnomaly]{
+			Data:    report,
+			Items:   report.Anomalies,
+			Columns: anomalyColumns,
+			Text: func(w io.Writer) error {
+				if !anomaliesOnly {
+					renderModelSummary(w, &report)
+				}
+				renderAnomalies(w, report.Anomalies)
+				if !anomaliesOnly {
+					renderForecast(w, &report)
+				}
+				return nil
+			},
+		}
+		if anomaliesOnly {
+			view.Data = report.Anomalies
+		}
+
+		if err := render(cmd, view); err != nil {
+			return err
+		}
+
+		if failOnAnomaly && len(report.Anomalies) > 0 {
+			return errors.Newf("%d anomalies detected in %s of %s", len(report.Anomalies), metric, slug)
+		}
+
+		return nil
+	},
+}
+
+// anomalyColumns are the columns of flagged anomalies.
+var anomalyColumns = []output.Column[analyzeAnomaly]{
+	{Name: "slug", Header: "Slug", Value: func(a analyzeAnomaly) any { return a.Slug }},
+	{Name: "metric", Header: "Metric", Value: func(a analyzeAnomaly) any { return a.Metric }},
+	{Name: "date", Header: "Date", Value: func(a analyzeAnomaly) any { return a.Date }},
+	{Name: "kind", Header: "Kind", Value: func(a analyzeAnomaly) any { return a.Kind }},
+	{Name: "value", Header: "Value", Value: func(a analyzeAnomaly) any { return a.Value }},
+	{
+		Name:   "expected",
+		Header: "Expected",
+		Value:  func(a analyzeAnomaly) any { return a.Expected },
+		Format: func(a analyzeAnomaly) string { return fmt.Sprintf("%.1f", a.Expected) },
+	},
+	{
+		Name:   "zScore",
+		Header: "Z-Score",
+		Value:  func(a analyzeAnomaly) any { return a.ZScore },
+		Format: func(a analyzeAnomaly) string { return fmt.Sprintf("%+.2f", a.ZScore) },
+	},
+}
+
+// statsPoints extracts one metric of a daily series as forecast input.
+func statsPoints(series hangar.StatsSeries, metric string) []forecast.Point {
+	points := make([]forecast.Point, len(series))
+	for i, point := range series {
+		value := point.Downloads
+		if metric == "views" {
+			value = point.Views
+		}
+		points[i] = forecast.Point{Date: point.Start, Value: float64(value)}
+	}
+
+	return points
+}
+
+// renderModelSummary prints the fitted trend and weekly pattern.
+func renderModelSummary(out io.Writer, report *analyzeReport) {
+	model := report.Model
+
+	_, _ = fmt.Fprintf(out, "Model for %s of %s (%d days, %d outliers excluded)\n",
+		report.Metric, report.Slug, report.Days, model.Outliers)
+	_, _ = fmt.Fprintf(out, "Trend: %+.2f per day, residual sigma %.2f\n\n", model.Slope, model.Sigma)
+
+	t := table.NewWriter()
+	t.SetOutputMirror(out)
+	header := table.Row{}
+	row := table.Row{}
+	// Start the week on Monday
+	for i := range 7 {
+		day := time.Weekday((i + 1) % 7)
+		header = append(header, day.String()[:3])
+		row = append(row, fmt.Sprintf("%+.1f", model.Seasonal[day]))
+	}
+	t.AppendHeader(header)
+	t.AppendRow(row)
+	t.Render()
+	_, _ = fmt.Fprintln(out)
+}
+
+// renderAnomalies prints the flagged days.
+func renderAnomalies(out io.Writer, anomalies []analyzeAnomaly) {
+	if len(anomalies) == 0 {
+		_, _ = fmt.Fprintln(out, "No anomalies detected")
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(out)
+	t.AppendHeader(table.Row{"Date", "Kind", "Value", "Expected", "Z-Score"})
+	for _, anomaly := range anomalies {
+		t.AppendRow(table.Row{
+			anomaly.Date.Format("2006-01-02"),
+			anomaly.Kind,
+			anomaly.Value,
+			fmt.Sprintf("%.1f", anomaly.Expected),
+			fmt.Sprintf("%+.2f", anomaly.ZScore),
+		})
+	}
+	t.Render()
+	_, _ = fmt.Fprintf(out, "\nAnomalies: %d\n", len(anomalies))
+}
+
+// renderForecast prints the predicted values with their confidence bands.
+func renderForecast(out io.Writer, report *analyzeReport) {
+	_, _ = fmt.Fprintln(out)
+
+	level := fmt.Sprintf("%g%%", report.Confidence*100)
+	t := table.NewWriter()
+	t.SetOutputMirror(out)
+	t.AppendHeader(table.Row{"Date", "Forecast", "Lower (" + level + ")", "Upper (" + level + ")"})
+	for _, prediction := range report.Forecast {
+		t.AppendRow(table.Row{
+			prediction.Date.Format("2006-01-02"),
+			fmt.Sprintf("%.1f", prediction.Value),
+			fmt.Sprintf("%.1f", prediction.Lower),
+			fmt.Sprintf("%.1f", prediction.Upper),
+		})
+	}
+	t.Render()
+}
+
+func init() {
+	statsCmd.AddCommand(statsAnalyzeCmd)
+
+	// Analyze command flags
+	statsAnalyzeCmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
+	statsAnalyzeCmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+	statsAnalyzeCmd.Flags().String("metric", "downloads", "Metric to analyze (downloads, views)")
+	statsAnalyzeCmd.Flags().Int("forecast", 14, "Number of days to forecast")
+	statsAnalyzeCmd.Flags().Float64("confidence", 0.95, "Confidence level of the forecast bands")
+	statsAnalyzeCmd.Flags().Float64("threshold", 3, "Z-score above which a day is flagged as anomaly")
+	statsAnalyzeCmd.Flags().Bool("anomalies-only", false, "Only output the flagged anomalies")
+	statsAnalyzeCmd.Flags().Bool("fail-on-anomaly", false, "Exit with an error when anomalies are detected")
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/spf13/cobra"
+)
+
+// apiMethods are the HTTP methods accepted by `hangar api`.
+var apiMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead,
+}
+
+// pagedResponse is the shape of paginated API responses.
+type pagedResponse struct {
+	Pagination *hangar.Pagination `json:"pagination"`
+	Result     []json.RawMessage  `json:"result"`
+}
+
+var apiCmd = &cobra.Command{
+	Use:   "api <method> <path>",
+	Short: "Make an authenticated request to any API endpoint",
+	Long: `Make a request to a Hangar API endpoint and print the response.
+
+The path is relative to the API base URL and the request uses the configured
+token. JSON responses are indented; other responses are printed as is.
+
+Fields given with -f are sent as query parameters for GET, DELETE and HEAD
+requests and as a JSON object body otherwise. With --input the body is read
+from a file ("-" for standard input) and fields become query parameters.
+
+With --paginate, every page of an endpoint returning a pagination object is
+fetched and the results are combined into one response.`,
+	Example: `  hangar api GET /projects -f q=essentials -f limit=5
+  hangar api GET /projects/EssentialsX/stargazers --paginate
+  hangar api GET /projects/EssentialsX/latestrelease
+  hangar api POST /pages/edit/EssentialsX --input page.json`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: positional(cobra.FixedCompletions(apiMethods, cobra.ShellCompDirectiveNoFileComp)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method := strings.ToUpper(args[0])
+		if !isAPIMethod(method) {
+			return errors.Newf("unsupported method %s (expected %s)", args[0], strings.Join(apiMethods, ", "))
+		}
+		path := args[1]
+
+		fields, _ := cmd.Flags().GetStringArray("field")
+		input, _ := cmd.Flags().GetString("input")
+		paginate, _ := cmd.Flags().GetBool("paginate")
+
+		values, err := parseFields(fields)
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		var body io.Reader
+		switch {
+		case input != "":
+			if body, err = readInput(cmd, input); err != nil {
+				return err
+			}
+			query = values
+		case method == http.MethodGet || method == http.MethodDelete || method == http.MethodHead:
+			query = values
+		case len(values) > 0:
+			object := make(map[string]string, len(values))
+			for key := range values {
+				object[key] = values.Get(key)
+			}
+			encoded, err := json.Marshal(object)
+			if err != nil {
+				return errors.Wrap(err, "failed to encode fields")
+			}
+			body = bytes.NewReader(encoded)
+		}
+
+		client := createClient()
+
+		var response bytes.Buffer
+		if paginate {
+			if method != http.MethodGet {
+				return errors.New("--paginate requires the GET method")
+			}
+			if err := fetchAllPages(cmd.Context(), client, path, query, &response); err != nil {
+				return errors.Wrapf(err, "%s %s failed", method, path)
+			}
+		} else if err := client.Do(cmd.Context(), method, path, query, body, &response); err != nil {
+			return errors.Wrapf(err, "%s %s failed", method, path)
+		}
+
+		return writeResponse(cmd.OutOrStdout(), response.Bytes())
+	},
+}
+
+// fetchAllPages requests every page of a paginated endpoint, starting at the offset
+// in query, and writes the combined response to w.
+func fetchAllPages(ctx context.Context, client *hangar.Client, path string, query url.Values, w io.Writer) error {
+	// Parameters in the path count as well, so an offset given there is where paging starts
+	ref, err := url.Parse(path)
+	if err != nil {
+		return errors.Wrapf(err, "invalid path %q", path)
+	}
+	merged := ref.Query()
+	for key, values := range query {
+		merged[key] = values
+	}
+	path, query = ref.Path, merged
+
+	var opts hangar.ListOptions
+	if offset := query.Get("offset"); offset != "" {
+		if opts.Offset, err = strconv.Atoi(offset); err != nil {
+			return errors.Newf("invalid offset %q", offset)
+		}
+	}
+
+	items, pagination, err := hangar.CollectAll(ctx, opts,
+		func(ctx context.Context, opts hangar.ListOptions) ([]json.RawMessage, hangar.Pagination, error) {
+			pageQuery := url.Values{}
+			for key, values := range query {
+				pageQuery[key] = values
+			}
+			pageQuery.Set("limit", strconv.Itoa(opts.Limit))
+			pageQuery.Set("offset", strconv.Itoa(opts.Offset))
+
+			var page pagedResponse
+			if err := client.Do(ctx, http.MethodGet, path, pageQuery, nil, &page); err != nil {
+				return nil, hangar.Pagination{}, err
+			}
+			if page.Pagination == nil {
+				return nil, hangar.Pagination{}, errors.New("--paginate requires an endpoint returning a pagination object")
+			}
+
+			return page.Result, *page.Pagination, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+
+	return errors.Wrap(json.NewEncoder(w).Encode(pagedResponse{Pagination: &pagination, Result: items}),
+		"failed to encode response")
+}
+
+// writeResponse writes a response body, indenting JSON.
+func writeResponse(w io.Writer, body []byte) error {
+	if !json.Valid(body) {
+		_, err := w.Write(body)
+		return errors.Wrap(err, "failed to write response")
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		return errors.Wrap(err, "failed to format response")
+	}
+	indented.WriteString("\n")
+
+	_, err := indented.WriteTo(w)
+
+	return errors.Wrap(err, "failed to write response")
+}
+
+// parseFields parses key=value pairs.
+func parseFields(fields []string) (url.Values, error) {
+	values := url.Values{}
+
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return nil, errors.Newf("invalid field %q (expected key=value)", field)
+		}
+		values.Add(key, value)
+	}
+
+	return values, nil
+}
+
+// readInput reads a request body from a file, or from standard input if name is "-".
+func readInput(cmd *cobra.Command, name string) (io.Reader, error) {
+	if name == "-" {
+		return cmd.InOrStdin(), nil
+	}
+
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read input")
+	}
+
+	return bytes.NewReader(raw), nil
+}
+
+// isAPIMethod reports whether method is accepted by `hangar api`.
+func isAPIMethod(method string) bool {
+	for _, m := range apiMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+
+	apiCmd.Flags().StringArrayP("field", "f", nil, "Add a key=value parameter (query for GET, DELETE and HEAD; JSON body otherwise)")
+	apiCmd.Flags().String("input", "", `Read the request body from a file ("-" for standard input)`)
+	apiCmd.Flags().Bool("paginate", false, "Fetch all pages of a paginated endpoint and combine the results")
+	_ = apiCmd.MarkFlagFilename("input", "json")
+}
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/config"
+	"github.com/lexfrei/go-hangar/internal/credentials"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// authStatus is the result of `hangar auth status`.
+type authStatus struct {
+	Profile        string    `json:"profile"`
+	BaseURL        string    `json:"baseUrl"`
+	User           string    `json:"user"`
+	Source         string    `json:"source"`
+	StoredAt       time.Time `json:"storedAt,omitzero"`
+	SessionExpires time.Time `json:"sessionExpires"`
+}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Log in to Hangar and manage stored API keys",
+	Long: `Log in to Hangar with an API key and manage the stored keys.
+
+Keys are stored per profile in credentials.yaml next to the config file, readable only
+by the current user. A token given with --token, $HANGAR_API_TOKEN or api_token in the
+config file takes precedence over the stored key.
+
+API keys are created in the Hangar user settings under "API Keys".`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Store an API key for the active profile",
+	Long: `Read an API key, validate it against the API and store it for the active profile.
+
+The key is read from the terminal without echo, or from standard input with --with-token
+or when standard input is not a terminal:
+
+  hangar auth login
+  hangar --profile staging auth login --with-token < staging.key`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		withToken, _ := cmd.Flags().GetBool("with-token")
+
+		apiKey, err := readAPIKey(cmd, withToken)
+		if err != nil {
+			return err
+		}
+
+		session, err := newClient("").Authenticate(cmd.Context(), apiKey)
+		if err != nil {
+			return errors.Wrap(err, "failed to validate API key")
+		}
+
+		user := ""
+		if claims, err := session.Claims(); err == nil {
+			user = claims.Subject
+		}
+
+		store, err := credentialStore()
+		if err != nil {
+			return err
+		}
+
+		credential := credentials.Credential{APIKey: apiKey, User: user, CreatedAt: time.Now().UTC()}
+		if err := store.Set(activeProfile, credential); err != nil {
+			return err
+		}
+
+		if user == "" {
+			user = "unknown user"
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Logged in to %s as %s (profile %s)\n",
+			viper.GetString("base_url"), user, profileName())
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "API key stored in %s\n", store.Name())
+
+		return nil
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored API key of the active profile",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		store, err := credentialStore()
+		if err != nil {
+			return err
+		}
+
+		if err := store.Delete(activeProfile); err != nil {
+			if errors.Is(err, credentials.ErrNotFound) {
+				return errors.Newf("not logged in (profile %s)", profileName())
+			}
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Logged out (profile %s)\n", profileName())
+
+		return nil
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the authenticated user and session expiry",
+	Long: `Validate the API key of the active profile and show the user it belongs to,
+where the key comes from and when the session created from it expires.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		status := authStatus{Profile: profileName(), BaseURL: viper.GetString("base_url")}
+
+		apiKey := viper.GetString("api_token")
+		status.Source = "config"
+		if flag := cmd.Root().PersistentFlags().Lookup("token"); flag != nil && flag.Changed {
+			status.Source = string(config.SourceFlag)
+		} else if _, ok := os.LookupEnv(config.EnvPrefix + "_API_TOKEN"); ok {
+			status.Source = string(config.SourceEnv)
+		}
+
+		if apiKey == "" {
+			store, err := credentialStore()
+			if err != nil {
+				return err
+			}
+
+			credential, err := store.Get(activeProfile)
+			if errors.Is(err, credentials.ErrNotFound) {
+				return errors.Newf("not logged in (profile %s), run 'hangar auth login'", status.Profile)
+			}
+			if err != nil {
+				return err
+			}
+
+			apiKey = credential.APIKey
+			status.Source = store.Name()
+			status.StoredAt = credential.CreatedAt
+		}
+
+		session, err := newClient("").Authenticate(cmd.Context(), apiKey)
+		if err != nil {
+			return errors.Wrapf(err, "API key from %s is not valid", status.Source)
+		}
+
+		status.SessionExpires = time.Now().Add(session.Lifetime()).Truncate(time.Second)
+		if claims, err := session.Claims(); err == nil {
+			status.User = claims.Subject
+			if claims.ExpiresAt > 0 {
+				status.SessionExpires = time.Unix(claims.ExpiresAt, 0)
+			}
+		}
+
+		return render(cmd, output.View[authStatus]{
+			Data:    status,
+			Items:   []authStatus{status},
+			Columns: authStatusColumns,
+			Detail:  true,
+		})
+	},
+}
+
+// readAPIKey reads an API key from the terminal without echo, or from standard input.
+func readAPIKey(cmd *cobra.Command, fromStdin bool) (string, error) {
+	in := cmd.InOrStdin()
+	file, isFile := in.(*os.File)
+	interactive := !fromStdin && isFile && term.IsTerminal(int(file.Fd()))
+
+	var apiKey string
+	if interactive {
+		_, _ = fmt.Fprint(cmd.ErrOrStderr(), "Paste your Hangar API key: ")
+		raw, err := term.ReadPassword(int(file.Fd()))
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read API key")
+		}
+		apiKey = string(raw)
+	} else {
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", errors.Wrap(err, "failed to read API key")
+		}
+		apiKey = line
+	}
+
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return "", errors.New("no API key given")
+	}
+
+	return apiKey, nil
+}
+
+// profileName returns the active profile for display.
+func profileName() string {
+	if activeProfile == "" {
+		return credentials.DefaultProfile
+	}
+
+	return activeProfile
+}
+
+// authStatusColumns are the fields of `hangar auth status`.
+var authStatusColumns = []output.Column[authStatus]{
+	{Name: "user", Header: "User", Value: func(s authStatus) any { return s.User }},
+	{Name: "profile", Header: "Profile", Value: func(s authStatus) any { return s.Profile }},
+	{Name: "baseUrl", Header: "Base URL", Value: func(s authStatus) any { return s.BaseURL }},
+	{Name: "source", Header: "Key Source", Value: func(s authStatus) any { return s.Source }},
+	{
+		Name: "storedAt", Header: "Key Stored",
+		Value: func(s authStatus) any { return s.StoredAt },
+		Format: func(s authStatus) string {
+			if s.StoredAt.IsZero() {
+				return "-"
+			}
+			return s.StoredAt.Local().Format(time.DateTime)
+		},
+	},
+	{
+		Name: "sessionExpires", Header: "Session Expires",
+		Value: func(s authStatus) any { return s.SessionExpires },
+		Format: func(s authStatus) string {
+			return fmt.Sprintf("%s (in %s)", s.SessionExpires.Local().Format(time.DateTime),
+				time.Until(s.SessionExpires).Round(time.Minute))
+		},
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authLoginCmd, authLogoutCmd, authStatusCmd)
+
+	// Login command flags
+	authLoginCmd.Flags().Bool("with-token", false, "Read the API key from standard input")
+}
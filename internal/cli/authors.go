@@ -1,11 +1,11 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/cockroachdb/errors"
-	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/lexfrei/go-hangar/internal/output"
 	"github.com/lexfrei/go-hangar/pkg/hangar"
 	"github.com/spf13/cobra"
 )
@@ -21,55 +21,26 @@ var authorsListCmd = &cobra.Command{
 	Short: "List authors",
 	Long:  "Retrieve a paginated list of authors (users who have published projects).",
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		ctx := cmd.Context()
-
-		limit, _ := cmd.Flags().GetInt("limit")
-		offset, _ := cmd.Flags().GetInt("offset")
-
 		client := createClient()
-		list, err := client.ListAuthors(ctx, hangar.ListOptions{
-			Limit:  limit,
-			Offset: offset,
-		})
+		items, pagination, err := listPages(cmd, hangar.ListOptions{},
+			func(ctx context.Context, opts hangar.ListOptions) ([]hangar.Author, hangar.Pagination, error) {
+				page, err := client.ListAuthors(ctx, opts)
+				if err != nil {
+					return nil, hangar.Pagination{}, err
+				}
+				return page.Result, page.Pagination, nil
+			})
 		if err != nil {
 			return errors.Wrap(err, "failed to list authors")
 		}
+		list := &hangar.AuthorList{Pagination: pagination, Result: items}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(list); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Username", "Projects", "Joined", "Roles"})
-			for _, author := range list.Result {
-				roles := ""
-				for i, role := range author.Roles {
-					if i > 0 {
-						roles += ", "
-					}
-					roles += role.Name
-				}
-				t.AppendRow(table.Row{
-					author.Name,
-					author.ProjectCount,
-					author.JoinDate.Format("2006-01-02"),
-					roles,
-				})
-			}
-			t.Render()
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nTotal: %d authors\n", list.Pagination.Count)
-		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.Author]{
+			Data:    list,
+			Items:   list.Result,
+			Columns: authorColumns,
+			Footer:  fmt.Sprintf("Total: %d authors", list.Pagination.Count),
+		})
 	},
 }
 
@@ -92,40 +63,12 @@ var staffListCmd = &cobra.Command{
 			return errors.Wrap(err, "failed to list staff")
 		}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(staff); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Username", "Roles", "Joined"})
-			for _, member := range staff {
-				roles := ""
-				for i, role := range member.Roles {
-					if i > 0 {
-						roles += ", "
-					}
-					roles += role.Name
-				}
-				t.AppendRow(table.Row{
-					member.Name,
-					roles,
-					member.JoinDate.Format("2006-01-02"),
-				})
-			}
-			t.Render()
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nTotal: %d staff members\n", len(staff))
-		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.StaffMember]{
+			Data:    staff,
+			Items:   staff,
+			Columns: staffColumns,
+			Footer:  fmt.Sprintf("Total: %d staff members", len(staff)),
+		})
 	},
 }
 
@@ -137,6 +80,5 @@ func init() {
 	staffCmd.AddCommand(staffListCmd)
 
 	// Authors list command flags
-	authorsListCmd.Flags().Int("limit", 25, "Maximum number of results")
-	authorsListCmd.Flags().Int("offset", 0, "Offset for pagination")
+	addListFlags(authorsListCmd)
 }
@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/cockroachdb/errors"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/lexfrei/go-hangar/internal/chart"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// compareProject is the per-project summary of a stats comparison.
+type compareProject struct {
+	Slug            string   `json:"slug"`
+	Downloads       int64    `json:"downloads"`
+	Views           int64    `json:"views"`
+	Share           float64  `json:"share"`
+	ViewsShare      float64  `json:"viewsShare"`
+	DownloadsGrowth *float64 `json:"downloadsGrowth"`
+	ViewsGrowth     *float64 `json:"viewsGrowth"`
+}
+
+// comparePeriod holds the statistics of all compared projects for a single period.
+type comparePeriod struct {
+	Period    string           `json:"period"`
+	Downloads map[string]int64 `json:"downloads"`
+	Views     map[string]int64 `json:"views"`
+}
+
+// compareRow is a row of the delimited output: a period, or one of the trailing summary rows
+// "total", "share" and "growth" (the latter two in percent).
+type compareRow struct {
+	Period    string         `json:"period"`
+	Downloads map[string]any `json:"downloads"`
+	Views     map[string]any `json:"views"`
+}
+
+// Labels of the summary rows that follow the periods in the delimited output.
+const (
+	compareRowTotal  = "total"
+	compareRowShare  = "share"
+	compareRowGrowth = "growth"
+)
+
+// compareReport is the result of comparing several projects.
+type compareReport struct {
+	From     string           `json:"from,omitempty"`
+	To       string           `json:"to,omitempty"`
+	GroupBy  string           `json:"groupBy"`
+	Projects []compareProject `json:"projects"`
+	Periods  []comparePeriod  `json:"periods"`
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Commands for analyzing statistics",
+	Long:  "Commands for comparing and analyzing project statistics across projects.",
+}
+
+var statsCompareCmd = &cobra.Command{
+	Use:   "compare <slug>...",
+	Short: "Compare statistics of several projects",
+	Long: `Fetch daily statistics for several projects over the same date range and show them side by side.
+
+The report contains aligned per-period downloads, totals, each project's share of the
+combined downloads and its growth rate (second half of the range compared to the first half).
+
+CSV, TSV and Markdown output list the periods followed by three summary rows: "total",
+"share" (share of the combined downloads and views in percent) and "growth" (in percent,
+empty when there is nothing to compare with).`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeSlugs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		fromDate, _ := cmd.Flags().GetString("from")
+		toDate, _ := cmd.Flags().GetString("to")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+		period, err := hangar.ParseStatsPeriod(groupBy)
+		if err != nil {
+			return errors.Wrap(err, "invalid --group-by value")
+		}
+
+		chartKind, err := chartKindFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool, len(args))
+		for _, slug := range args {
+			if seen[slug] {
+				return errors.Newf("project %s specified more than once", slug)
+			}
+			seen[slug] = true
+		}
+
+		client := createClient()
+
+		// Fetch all projects concurrently; results keep the argument order
+		stats := make([]hangar.ProjectStats, len(args))
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(max(concurrency, 1))
+		for i, slug := range args {
+			group.Go(func() error {
+				projectStats, err := client.GetProjectStats(groupCtx, slug, fromDate, toDate)
+				if err != nil {
+					return errors.Wrapf(err, "failed to get stats for %s", slug)
+				}
+				stats[i] = projectStats
+				return nil
+			})
+		}
+		if err := group.Wait(); err != nil {
+			return err
+		}
+
+		report, err := buildCompareReport(args, stats, period)
+		if err != nil {
+			return err
+		}
+		report.From = fromDate
+		report.To = toDate
+
+		return render(cmd, output.View[compareRow]{
+			Data:    report,
+			Items:   compareRows(report),
+			Columns: compareColumns(report),
+			Text: func(w io.Writer) error {
+				renderCompareTable(w, report)
+				if chartKind != "" {
+					_, _ = fmt.Fprintln(w)
+					return renderCompareChart(w, report, chartKind)
+				}
+				return nil
+			},
+		})
+	},
+}
+
+// buildCompareReport aligns the statistics of all projects on a common set of periods.
+func buildCompareReport(slugs []string, stats []hangar.ProjectStats, period hangar.StatsPeriod) (*compareReport, error) {
+	report := &compareReport{GroupBy: string(period)}
+
+	// Merge all dates into one filled range so every project shares the same periods
+	combined := make(map[string]hangar.DailyStats)
+	perProject := make([]map[string]hangar.StatsPoint, len(slugs))
+	var combinedTotal, combinedViews int64
+
+	seriesList := make([]hangar.StatsSeries, len(slugs))
+	for i, projectStats := range stats {
+		series, err := projectStats.Series()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse stats for %s", slugs[i])
+		}
+		seriesList[i] = series
+
+		for date := range projectStats {
+			combined[date] = hangar.DailyStats{}
+		}
+		combinedTotal += series.Totals().Downloads
+		combinedViews += series.Totals().Views
+	}
+
+	timeline, err := hangar.NewStatsSeries(combined)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse stats")
+	}
+	timeline = timeline.Fill().GroupBy(period)
+
+	for i, series := range seriesList {
+		grouped := series.Fill().GroupBy(period)
+		perProject[i] = make(map[string]hangar.StatsPoint, len(grouped))
+		for _, point := range grouped {
+			perProject[i][point.Period] = point
+		}
+
+		// Growth is computed over the common timeline so late starters are not favored
+		aligned := make(hangar.StatsSeries, len(timeline))
+		for j, point := range timeline {
+			aligned[j] = perProject[i][point.Period]
+		}
+
+		totals := series.Totals()
+		trend := aligned.Trend()
+		project := compareProject{
+			Slug:            slugs[i],
+			Downloads:       totals.Downloads,
+			Views:           totals.Views,
+			DownloadsGrowth: trend.Downloads,
+			ViewsGrowth:     trend.Views,
+		}
+		if combinedTotal > 0 {
+			project.Share = float64(totals.Downloads) / float64(combinedTotal) * 100
+		}
+		if combinedViews > 0 {
+			project.ViewsShare = float64(totals.Views) / float64(combinedViews) * 100
+		}
+		report.Projects = append(report.Projects, project)
+	}
+
+	for _, point := range timeline {
+		row := comparePeriod{
+			Period:    point.Period,
+			Downloads: make(map[string]int64, len(slugs)),
+			Views:     make(map[string]int64, len(slugs)),
+		}
+		for i, slug := range slugs {
+			row.Downloads[slug] = perProject[i][point.Period].Downloads
+			row.Views[slug] = perProject[i][point.Period].Views
+		}
+		report.Periods = append(report.Periods, row)
+	}
+
+	return report, nil
+}
+
+// renderCompareTable prints per-period downloads and the per-project summary.
+func renderCompareTable(w io.Writer, report *compareReport) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+
+	header := table.Row{"Period"}
+	for _, project := range report.Projects {
+		header = append(header, project.Slug)
+	}
+	header = append(header, "Combined")
+	t.AppendHeader(header)
+
+	for _, period := range report.Periods {
+		row := table.Row{period.Period}
+		var combined int64
+		for _, project := range report.Projects {
+			row = append(row, period.Downloads[project.Slug])
+			combined += period.Downloads[project.Slug]
+		}
+		row = append(row, combined)
+		t.AppendRow(row)
+	}
+	t.Render()
+
+	_, _ = fmt.Fprintln(w)
+
+	summary := table.NewWriter()
+	summary.SetOutputMirror(w)
+	summary.AppendHeader(table.Row{"Project", "Downloads", "Views", "Share", "Downloads Growth", "Views Growth"})
+	for _, project := range report.Projects {
+		summary.AppendRow(table.Row{
+			project.Slug,
+			project.Downloads,
+			project.Views,
+			fmt.Sprintf("%.1f%%", project.Share),
+			formatGrowth(project.DownloadsGrowth),
+			formatGrowth(project.ViewsGrowth),
+		})
+	}
+	summary.Render()
+}
+
+// renderCompareChart draws the downloads of all projects; line charts overlay them.
+func renderCompareChart(w io.Writer, report *compareReport, kind chart.Kind) error {
+	labels := make([]string, len(report.Periods))
+	series := make([]chart.Series, len(report.Projects))
+	for i, project := range report.Projects {
+		series[i] = chart.Series{Name: project.Slug, Values: make([]float64, len(report.Periods))}
+	}
+
+	for j, period := range report.Periods {
+		labels[j] = period.Period
+		for i, project := range report.Projects {
+			series[i].Values[j] = float64(period.Downloads[project.Slug])
+		}
+	}
+
+	if err := chart.Render(w, kind, labels, series, chart.Terminal(w)); err != nil {
+		return errors.Wrap(err, "failed to render chart")
+	}
+
+	return nil
+}
+
+// compareRows returns the periods followed by the total, share and growth summary rows.
+func compareRows(report *compareReport) []compareRow {
+	rows := make([]compareRow, 0, len(report.Periods)+3)
+	for _, period := range report.Periods {
+		row := compareRow{
+			Period:    period.Period,
+			Downloads: make(map[string]any, len(period.Downloads)),
+			Views:     make(map[string]any, len(period.Views)),
+		}
+		for slug, downloads := range period.Downloads {
+			row.Downloads[slug] = downloads
+		}
+		for slug, views := range period.Views {
+			row.Views[slug] = views
+		}
+		rows = append(rows, row)
+	}
+
+	total := compareRow{Period: compareRowTotal, Downloads: make(map[string]any), Views: make(map[string]any)}
+	share := compareRow{Period: compareRowShare, Downloads: make(map[string]any), Views: make(map[string]any)}
+	growth := compareRow{Period: compareRowGrowth, Downloads: make(map[string]any), Views: make(map[string]any)}
+	for _, project := range report.Projects {
+		total.Downloads[project.Slug] = project.Downloads
+		total.Views[project.Slug] = project.Views
+		share.Downloads[project.Slug] = roundPercent(project.Share)
+		share.Views[project.Slug] = roundPercent(project.ViewsShare)
+		if project.DownloadsGrowth != nil {
+			growth.Downloads[project.Slug] = roundPercent(*project.DownloadsGrowth)
+		}
+		if project.ViewsGrowth != nil {
+			growth.Views[project.Slug] = roundPercent(*project.ViewsGrowth)
+		}
+	}
+
+	return append(rows, total, share, growth)
+}
+
+// roundPercent rounds a percentage to one decimal place like the table output.
+func roundPercent(percent float64) float64 {
+	return math.Round(percent*10) / 10
+}
+
+// compareColumns returns the period column plus downloads and views columns for each project.
+func compareColumns(report *compareReport) []output.Column[compareRow] {
+	columns := []output.Column[compareRow]{
+		{Name: "period", Header: "Period", Value: func(r compareRow) any { return r.Period }},
+	}
+
+	for _, project := range report.Projects {
+		slug := project.Slug
+		columns = append(columns,
+			output.Column[compareRow]{
+				Name:   slug + "_downloads",
+				Header: slug + " Downloads",
+				Value:  func(r compareRow) any { return r.Downloads[slug] },
+			},
+			output.Column[compareRow]{
+				Name:   slug + "_views",
+				Header: slug + " Views",
+				Value:  func(r compareRow) any { return r.Views[slug] },
+			})
+	}
+
+	return columns
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.AddCommand(statsCompareCmd)
+
+	// Compare command flags
+	statsCompareCmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
+	statsCompareCmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+	statsCompareCmd.Flags().String("group-by", "day", "Aggregation period (day, week, month, year)")
+	_ = statsCompareCmd.RegisterFlagCompletionFunc("group-by", completeGroupBy)
+	addChartFlag(statsCompareCmd, "Show a downloads chart below the table")
+	statsCompareCmd.Flags().Int("concurrency", 4, "Maximum number of concurrent API requests")
+}
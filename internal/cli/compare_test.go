@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareCSV(t *testing.T) {
+	t.Parallel()
+
+	slugs := []string{"alpha", "beta"}
+	stats := []hangar.ProjectStats{
+		{
+			"2024-01-01": {Downloads: 10, Views: 100},
+			"2024-01-02": {Downloads: 30, Views: 100},
+		},
+		{
+			"2024-01-02": {Downloads: 60, Views: 200},
+		},
+	}
+
+	report, err := buildCompareReport(slugs, stats, hangar.PeriodDay)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = output.Render(&buf, output.Options{Format: output.FormatCSV}, output.View[compareRow]{
+		Data:    report,
+		Items:   compareRows(report),
+		Columns: compareColumns(report),
+	})
+	require.NoError(t, err)
+
+	want := `period,alpha_downloads,alpha_views,beta_downloads,beta_views
+2024-01-01,10,100,0,0
+2024-01-02,30,100,60,200
+total,40,200,60,200
+share,40,50,60,50
+growth,200,0,,
+`
+	assert.Equal(t, want, buf.String())
+}
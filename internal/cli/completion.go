@@ -0,0 +1,373 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/cache"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// completionTTL is how long completion results are cached on disk.
+	completionTTL = 5 * time.Minute
+	// completionTimeout bounds API requests made while completing.
+	completionTimeout = 5 * time.Second
+	// completionLimit is the number of projects and users requested per completion.
+	completionLimit = 25
+)
+
+var (
+	// defaultChannels are offered for --channel when a project's channels cannot be listed.
+	defaultChannels = []string{"Release", "Snapshot", "Beta", "Alpha"}
+
+	// groupByPeriods are the values of --group-by.
+	groupByPeriods = []string{"day", "week", "month", "year"}
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a completion script for the given shell.
+
+Completions include project slugs, usernames, version names, channels and platforms
+fetched from the API. Results are cached for a few minutes to keep completion fast.
+
+Bash (requires bash-completion):
+  source <(hangar completion bash)
+  hangar completion bash > /etc/bash_completion.d/hangar
+
+Zsh:
+  hangar completion zsh > "${fpath[1]}/_hangar"
+
+Fish:
+  hangar completion fish > ~/.config/fish/completions/hangar.fish
+
+PowerShell:
+  hangar completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noDescriptions, _ := cmd.Flags().GetBool("no-descriptions")
+		out := cmd.OutOrStdout()
+
+		var err error
+		switch args[0] {
+		case "bash":
+			err = cmd.Root().GenBashCompletionV2(out, !noDescriptions)
+		case "zsh":
+			if noDescriptions {
+				err = cmd.Root().GenZshCompletionNoDesc(out)
+			} else {
+				err = cmd.Root().GenZshCompletion(out)
+			}
+		case "fish":
+			err = cmd.Root().GenFishCompletion(out, !noDescriptions)
+		case "powershell":
+			if noDescriptions {
+				err = cmd.Root().GenPowerShellCompletion(out)
+			} else {
+				err = cmd.Root().GenPowerShellCompletionWithDesc(out)
+			}
+		}
+
+		return errors.Wrapf(err, "failed to generate %s completion", args[0])
+	},
+}
+
+// positional completes each positional argument with the completer at its index.
+func positional(completers ...cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) >= len(completers) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return completers[len(args)](cmd, args, toComplete)
+	}
+}
+
+// completeSlugs completes project slugs starting with the typed prefix.
+func completeSlugs(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	completions, err := cachedCompletions(cmd, "projects:"+strings.ToLower(toComplete),
+		func(ctx context.Context, client *hangar.Client) ([]cobra.Completion, error) {
+			list, err := client.ListProjects(ctx, hangar.ListOptions{Limit: completionLimit, Query: toComplete})
+			if err != nil {
+				return nil, err
+			}
+
+			completions := make([]cobra.Completion, 0, len(list.Result))
+			for _, project := range list.Result {
+				completions = append(completions, cobra.CompletionWithDesc(project.Namespace.Slug, project.Description))
+			}
+			return completions, nil
+		})
+	if err != nil {
+		return completionError(err)
+	}
+
+	return withPrefix(completions, toComplete, args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeUsernames completes usernames starting with the typed prefix.
+func completeUsernames(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	completions, err := cachedCompletions(cmd, "users:"+strings.ToLower(toComplete),
+		func(ctx context.Context, client *hangar.Client) ([]cobra.Completion, error) {
+			list, err := client.ListUsers(ctx, toComplete, hangar.ListOptions{Limit: completionLimit})
+			if err != nil {
+				return nil, err
+			}
+
+			completions := make([]cobra.Completion, 0, len(list.Result))
+			for _, user := range list.Result {
+				completions = append(completions, cobra.CompletionWithDesc(user.Name, fmt.Sprintf("%d projects", user.ProjectCount)))
+			}
+			return completions, nil
+		})
+	if err != nil {
+		return completionError(err)
+	}
+
+	return withPrefix(completions, toComplete, args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeVersions completes version names of the project given as first argument, newest first.
+func completeVersions(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	versions, err := projectVersions(cmd, args[0])
+	if err != nil {
+		return completionError(err)
+	}
+
+	completions := make([]cobra.Completion, 0, len(versions))
+	for _, version := range versions {
+		completions = append(completions, cobra.CompletionWithDesc(version.Name,
+			fmt.Sprintf("%s, %s", version.Channel, version.CreatedAt.Format("2006-01-02"))))
+	}
+
+	return withPrefix(completions, toComplete, nil), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeChannels completes the channels used by the project given as first argument,
+// falling back to the default channels.
+func completeChannels(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	completions := defaultChannels
+
+	if len(args) > 0 {
+		versions, err := projectVersions(cmd, args[0])
+		if err != nil {
+			cobra.CompDebugln(err.Error(), false)
+		}
+
+		var channels []cobra.Completion
+		seen := make(map[string]bool)
+		for _, version := range versions {
+			if version.Channel != "" && !seen[version.Channel] {
+				seen[version.Channel] = true
+				channels = append(channels, version.Channel)
+			}
+		}
+		if len(channels) > 0 {
+			completions = channels
+		}
+	}
+
+	return withPrefix(completions, toComplete, nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeDir completes the directory argument of commands that take exactly one.
+func completeDir(_ *cobra.Command, args []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
+// completePlatforms completes the Hangar platforms.
+var completePlatforms = cobra.FixedCompletions(hangar.KnownPlatforms, cobra.ShellCompDirectiveNoFileComp)
+
+// completeCategories completes the project categories.
+var completeCategories = cobra.FixedCompletions(hangar.ProjectCategories, cobra.ShellCompDirectiveNoFileComp)
+
+// completeGroupBy completes the aggregation periods of --group-by.
+var completeGroupBy = cobra.FixedCompletions(groupByPeriods, cobra.ShellCompDirectiveNoFileComp)
+
+// completeChannelList completes comma-separated release channels, e.g. for --channels.
+func completeChannelList(_ *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	done, current := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		done, current = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	completions := withPrefix(defaultChannels, current, strings.Split(done, ","))
+	for i, completion := range completions {
+		completions[i] = done + completion
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeOutput completes the values of --output.
+func completeOutput(_ *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	completions := make([]cobra.Completion, 0, len(output.Formats)+2)
+	for _, format := range output.Formats {
+		completions = append(completions, string(format))
+	}
+	completions = append(completions, string(output.FormatGoTemplate)+"=", string(output.FormatJSONPath)+"=")
+
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completionVersion is the cached part of a version used for completion.
+type completionVersion struct {
+	Name      string    `json:"name"`
+	Channel   string    `json:"channel"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// projectVersions returns the most recent versions of a project.
+func projectVersions(cmd *cobra.Command, slug string) ([]completionVersion, error) {
+	var versions []completionVersion
+
+	err := cached(cmd, "versions:"+slug, &versions, func(ctx context.Context, client *hangar.Client) error {
+		project, err := client.GetProject(ctx, slug)
+		if err != nil {
+			return err
+		}
+
+		list, err := client.ListVersions(ctx, project.Namespace.Owner, project.Namespace.Slug, hangar.ListOptions{Limit: hangar.MaxLimit})
+		if err != nil {
+			return err
+		}
+
+		versions = make([]completionVersion, len(list.Result))
+		for i, version := range list.Result {
+			versions[i] = completionVersion{Name: version.Name, Channel: version.Channel.Name, CreatedAt: version.CreatedAt}
+		}
+		return nil
+	})
+
+	return versions, err
+}
+
+// cachedCompletions returns cached completions for key or fetches and caches them.
+func cachedCompletions(
+	cmd *cobra.Command,
+	key string,
+	fetch func(ctx context.Context, client *hangar.Client) ([]cobra.Completion, error),
+) ([]cobra.Completion, error) {
+	var completions []cobra.Completion
+
+	err := cached(cmd, key, &completions, func(ctx context.Context, client *hangar.Client) error {
+		var err error
+		completions, err = fetch(ctx, client)
+		return err
+	})
+
+	return completions, err
+}
+
+// cached decodes the entry for key into value, or calls fetch to fill value and stores it.
+// Keys are scoped to the API base URL, the profile and the API token, so results visible to
+// one account are not offered to another; caching is skipped when no cache directory is available.
+func cached(cmd *cobra.Command, key string, value any, fetch func(ctx context.Context, client *hangar.Client) error) error {
+	reloadCompletionConfig(cmd)
+
+	token := viper.GetString("api_token")
+	if token == "" {
+		token = storedToken()
+	}
+	key = strings.Join([]string{viper.GetString("base_url"), profileName(), tokenFingerprint(token), key}, "\n")
+
+	var store *cache.Cache
+	if dir, err := cache.DefaultDir(); err == nil {
+		store = cache.New(filepath.Join(dir, "completion"), completionTTL)
+		if store.Get(key, value) {
+			return nil
+		}
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	if err := fetch(ctx, newClient(token)); err != nil {
+		return err
+	}
+
+	if store != nil {
+		if err := store.Set(key, value); err != nil {
+			cobra.CompDebugln(err.Error(), false)
+		}
+	}
+
+	return nil
+}
+
+// reloadCompletionConfig applies the --config and --profile flags of the command being completed.
+// While completing, initConfig runs before cobra parses the flags of that command, so it
+// resolved the default file and profile. Flags bound to viper, such as --token, need no reload.
+func reloadCompletionConfig(cmd *cobra.Command) {
+	if cmd.Flags().Changed("config") || cmd.Flags().Changed("profile") {
+		initConfig()
+	}
+}
+
+// tokenFingerprint identifies an API token in cache keys without storing the token itself.
+func tokenFingerprint(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// withPrefix keeps the completions starting with prefix, ignoring case, and drops values in exclude.
+func withPrefix(completions []cobra.Completion, prefix string, exclude []string) []cobra.Completion {
+	filtered := make([]cobra.Completion, 0, len(completions))
+
+	for _, completion := range completions {
+		value, _, _ := strings.Cut(completion, "\t")
+		if !strings.HasPrefix(strings.ToLower(value), strings.ToLower(prefix)) {
+			continue
+		}
+
+		excluded := false
+		for _, arg := range exclude {
+			excluded = excluded || strings.EqualFold(arg, value)
+		}
+		if !excluded {
+			filtered = append(filtered, completion)
+		}
+	}
+
+	return filtered
+}
+
+// completionError reports a failed completion; the details are visible with `hangar __complete`.
+func completionError(err error) ([]cobra.Completion, cobra.ShellCompDirective) {
+	cobra.CompDebugln(err.Error(), false)
+	return nil, cobra.ShellCompDirectiveError
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	completionCmd.Flags().Bool("no-descriptions", false, "Disable completion descriptions")
+}
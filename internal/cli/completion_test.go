@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// projectServer serves a single project named slug and records the API tokens it receives.
+func projectServer(t *testing.T, slug string, tokens *[]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*tokens = append(*tokens, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"pagination":{"count":1,"limit":25,"offset":0},"result":[{"name":%[1]q,"namespace":{"owner":"owner","slug":%[1]q}}]}`, slug)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// complete runs `hangar __complete args...` and returns its output.
+func complete(t *testing.T, args ...string) string {
+	t.Helper()
+
+	resetFlags()
+	t.Cleanup(resetFlags)
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(append([]string{"__complete"}, args...))
+	require.NoError(t, rootCmd.Execute())
+
+	return out.String()
+}
+
+// resetFlags clears the global flags set by an earlier run of the root command.
+func resetFlags() {
+	cfgFile, profile, apiToken = "", "", ""
+	for _, name := range []string{"config", "profile", "token"} {
+		rootCmd.PersistentFlags().Lookup(name).Changed = false
+	}
+}
+
+func TestCompletion_ProfileFlag(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	var prodTokens, stagingTokens []string
+	prod := projectServer(t, "ProdPlugin", &prodTokens)
+	staging := projectServer(t, "StagingPlugin", &stagingTokens)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(`base_url: %s
+profiles:
+  staging:
+    base_url: %s
+    api_token: staging-token
+`, prod.URL, staging.URL)), 0o600))
+
+	out := complete(t, "--config", path, "project", "get", "")
+	assert.Contains(t, out, "ProdPlugin")
+
+	out = complete(t, "--config", path, "--profile", "staging", "project", "get", "")
+	assert.Contains(t, out, "StagingPlugin")
+	assert.NotContains(t, out, "ProdPlugin")
+	require.Len(t, stagingTokens, 1, "the default profile's cached completions are not reused")
+	assert.Equal(t, "Bearer staging-token", stagingTokens[0])
+
+	out = complete(t, "--config", path, "project", "get", "")
+	assert.Contains(t, out, "ProdPlugin")
+	assert.Len(t, prodTokens, 1, "completions of the default profile stay cached")
+}
@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/config"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// profileSummary is a row of `hangar config list`.
+type profileSummary struct {
+	Name     string `json:"name"`
+	Current  bool   `json:"current"`
+	BaseURL  string `json:"baseUrl,omitempty"`
+	TokenSet bool   `json:"tokenSet"`
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the configuration file and profiles",
+	Long: `Manage the configuration file and its named profiles.
+
+Profiles hold settings for different Hangar instances, for example:
+
+  current_profile: prod
+  profiles:
+    prod:
+      api_token: ...
+    staging:
+      base_url: https://hangar.staging.example/api/v1
+      api_token: ...
+
+The profile is selected with --profile, then $` + config.ProfileEnv + `, then current_profile.
+Settings are resolved in the order flag, environment variable, profile, top-level file value, default.
+
+Supported keys: ` + config.KeyNames(),
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Long:  "List the profiles of the configuration file, marking the active one.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		file, err := loadedConfig()
+		if err != nil {
+			return err
+		}
+
+		profiles := make([]profileSummary, 0, len(file.Profiles()))
+		for _, name := range file.Profiles() {
+			values := file.Values(name)
+			profiles = append(profiles, profileSummary{
+				Name:     name,
+				Current:  name == activeProfile,
+				BaseURL:  values["base_url"],
+				TokenSet: values["api_token"] != "",
+			})
+		}
+
+		return render(cmd, output.View[profileSummary]{
+			Data:    profiles,
+			Items:   profiles,
+			Columns: profileColumns,
+			Footer:  fmt.Sprintf("Total: %d profiles (%s)", len(profiles), file.Path()),
+		})
+	},
+}
+
+var configUseCmd = &cobra.Command{
+	Use:               "use <profile>",
+	Short:             "Select the default profile",
+	Long:              "Store the profile as current_profile, used when neither --profile nor $" + config.ProfileEnv + " is set.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeProfiles),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := loadedConfig()
+		if err != nil {
+			return err
+		}
+
+		if err := file.Use(args[0]); err != nil {
+			return err
+		}
+		if err := file.Save(); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Switched to profile %q\n", args[0])
+
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show effective settings and their source",
+	Long: `Show the effective value of every setting and where it comes from:
+a flag, an environment variable, the active profile, the file, the credential
+store written by 'hangar auth login' or the default.
+Tokens are masked.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		file, err := loadedConfig()
+		if err != nil {
+			return err
+		}
+
+		settings := effectiveSettings(cmd, file)
+		for i := range settings {
+			settings[i].Value = displayValue(settings[i].Key, settings[i].Value, false)
+			if settings[i].Source == config.SourceProfile {
+				settings[i].Source = config.Source(fmt.Sprintf("%s %s", config.SourceProfile, activeProfile))
+			}
+		}
+
+		profileName := activeProfile
+		if profileName == "" {
+			profileName = "none"
+		}
+
+		return render(cmd, output.View[config.Setting]{
+			Data:    settings,
+			Items:   settings,
+			Columns: settingColumns,
+			Footer:  fmt.Sprintf("Profile: %s\nConfig file: %s", profileName, file.Path()),
+		})
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Store a setting",
+	Long: `Store a setting in the profile selected with --profile or $` + config.ProfileEnv + `,
+or in current_profile, or at the top level of the file if no profile is selected.
+A profile named with --profile is created if it does not exist.
+
+Supported keys: ` + config.KeyNames(),
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: positional(completeConfigKeys),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := loadedConfig()
+		if err != nil {
+			return err
+		}
+
+		target := activeProfile
+		if profile != "" {
+			target = profile
+		}
+
+		if err := file.Set(target, args[0], args[1]); err != nil {
+			return err
+		}
+		if err := file.Save(); err != nil {
+			return err
+		}
+
+		location := "top level"
+		if target != "" {
+			location = "profile " + target
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Set %s in %s of %s\n", args[0], location, file.Path())
+
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Short:             "Print the effective value of a setting",
+	Long:              "Print the effective value of a setting. Tokens are masked unless --reveal is given.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeConfigKeys),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := config.LookupKey(args[0]); err != nil {
+			return err
+		}
+
+		file, err := loadedConfig()
+		if err != nil {
+			return err
+		}
+
+		reveal, _ := cmd.Flags().GetBool("reveal")
+		for _, setting := range effectiveSettings(cmd, file) {
+			if setting.Key == args[0] {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), displayValue(setting.Key, setting.Value, reveal))
+			}
+		}
+
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file",
+	Long:  "Check the configuration file for syntax errors, unknown keys, invalid values and missing profiles.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		file, err := loadedConfig()
+		if err != nil {
+			return err
+		}
+
+		problems := file.Validate()
+
+		// A missing current_profile is reported by Validate, only check explicit selections
+		if selected := os.Getenv(config.ProfileEnv); profile != "" || selected != "" {
+			if _, err := file.ActiveProfile(profile, selected); err != nil {
+				problems = append(problems, err)
+			}
+		}
+
+		if len(problems) == 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", file.Path())
+			return nil
+		}
+
+		for _, problem := range problems {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "- %s\n", problem)
+		}
+
+		return errors.Newf("%s has %d problems", file.Path(), len(problems))
+	},
+}
+
+// loadedConfig returns the configuration file read by initConfig.
+func loadedConfig() (*config.File, error) {
+	if configFile != nil {
+		return configFile, nil
+	}
+	if configErr != nil {
+		return nil, configErr
+	}
+
+	return nil, errors.New("no configuration file location available, use --config")
+}
+
+// effectiveSettings resolves every setting from the flags, the environment and the file.
+func effectiveSettings(cmd *cobra.Command, file *config.File) []config.Setting {
+	overrides := config.Overrides{
+		Flags:     make(map[string]string),
+		LookupEnv: os.LookupEnv,
+		Defaults:  make(map[string]string),
+	}
+
+	for _, key := range config.Keys {
+		flag := cmd.Root().PersistentFlags().Lookup(key.Flag)
+		if flag == nil {
+			continue
+		}
+		overrides.Defaults[key.Name] = flag.DefValue
+		if flag.Changed {
+			overrides.Flags[key.Name] = flag.Value.String()
+		}
+	}
+
+	settings := file.Resolve(activeProfile, overrides)
+	for i, setting := range settings {
+		if setting.Key == "api_token" && setting.Source == config.SourceDefault {
+			if token := storedToken(); token != "" {
+				settings[i].Value, settings[i].Source = token, config.SourceCredentials
+			}
+		}
+	}
+
+	return settings
+}
+
+// displayValue masks secret values and redacts credentials in other values unless reveal is set.
+func displayValue(name, value string, reveal bool) string {
+	key, err := config.LookupKey(name)
+	switch {
+	case err != nil || reveal:
+		return value
+	case key.Secret:
+		return config.Mask(value)
+	case key.Redact != nil:
+		return key.Redact(value)
+	default:
+		return value
+	}
+}
+
+// completeProfiles completes the profile names of the configuration file.
+func completeProfiles(_ *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	path, err := configPath()
+	if err != nil {
+		return completionError(err)
+	}
+
+	file, err := config.Load(path)
+	if err != nil {
+		return completionError(err)
+	}
+
+	return withPrefix(file.Profiles(), toComplete, nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigKeys completes the supported setting names.
+func completeConfigKeys(_ *cobra.Command, _ []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	completions := make([]cobra.Completion, len(config.Keys))
+	for i, key := range config.Keys {
+		completions[i] = cobra.CompletionWithDesc(key.Name, key.Description)
+	}
+
+	return withPrefix(completions, toComplete, nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// profileColumns are the columns of `hangar config list`.
+var profileColumns = []output.Column[profileSummary]{
+	{
+		Name: "current", Header: " ",
+		Value: func(p profileSummary) any { return p.Current },
+		Format: func(p profileSummary) string {
+			if p.Current {
+				return "*"
+			}
+			return ""
+		},
+	},
+	{Name: "name", Header: "Profile", Value: func(p profileSummary) any { return p.Name }},
+	{Name: "baseUrl", Header: "Base URL", Value: func(p profileSummary) any { return p.BaseURL }},
+	{
+		Name: "tokenSet", Header: "Token",
+		Value: func(p profileSummary) any { return p.TokenSet },
+		Format: func(p profileSummary) string {
+			if p.TokenSet {
+				return "set"
+			}
+			return "-"
+		},
+	},
+}
+
+// settingColumns are the columns of `hangar config show`.
+var settingColumns = []output.Column[config.Setting]{
+	{Name: "key", Header: "Key", Value: func(s config.Setting) any { return s.Key }},
+	{Name: "value", Header: "Value", Value: func(s config.Setting) any { return s.Value }},
+	{Name: "source", Header: "Source", Value: func(s config.Setting) any { return string(s.Source) }},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd, configUseCmd, configShowCmd, configSetCmd, configGetCmd, configValidateCmd)
+
+	// Get command flags
+	configGetCmd.Flags().Bool("reveal", false, "Print secret values such as tokens unmasked")
+}
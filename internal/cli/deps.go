@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/deps"
+	"github.com/lexfrei/go-hangar/internal/manifest"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps <slug>[@version]...",
+	Short: "Resolve the plugin dependencies of projects",
+	Long: `Resolve the transitive plugin dependencies of one or more projects for a platform and
+Minecraft version.
+
+Each project and dependency is resolved to the newest version in the channel (Release by
+default) that has a download for the platform and supports the Minecraft version. Append
+@version to a slug to pin its version instead.
+
+Dependencies that need attention are reported separately:
+  missing   the dependency is not on Hangar or has no compatible version
+  external  the dependency is only available outside Hangar
+  conflict  a pinned version does not support the platform, or one dependency name
+            refers to different plugins
+
+With --install the requested projects and their required dependencies (plus optional ones
+with --optional) are downloaded into --dir. External dependencies have to be installed by
+hand; missing required dependencies and conflicts abort the installation.`,
+	Example: `  hangar deps CoolPlugin --minecraft-version 1.21.1
+  hangar deps CoolPlugin OtherPlugin@2.1.0 --platform VELOCITY
+  hangar deps CoolPlugin --minecraft-version 1.21.1 --install --dir ./plugins`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSlugs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		install, _ := cmd.Flags().GetBool("install")
+		optional, _ := cmd.Flags().GetBool("optional")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		graph, err := resolveDeps(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		err = render(cmd, output.View[*deps.Node]{
+			Data:    graph,
+			Items:   graph.Nodes,
+			Columns: depsColumns(graph),
+			Text: func(w io.Writer) error {
+				return renderDeps(w, graph)
+			},
+		})
+		if err != nil || !install {
+			return err
+		}
+
+		if unresolved := graph.Unresolved(); len(unresolved) > 0 {
+			problems := make([]string, 0, len(unresolved))
+			for _, problem := range unresolved {
+				problems = append(problems, problem.Dependency+": "+problem.Message)
+			}
+			return errors.Newf("cannot install: %s", strings.Join(problems, "; "))
+		}
+
+		return installDeps(cmd, graph, dir, optional)
+	},
+}
+
+// resolveDeps resolves the dependency graph of the plugins with the resolution flags of cmd.
+func resolveDeps(cmd *cobra.Command, plugins []string) (*deps.Graph, error) {
+	platform, _ := cmd.Flags().GetString("platform")
+	minecraftVersion, _ := cmd.Flags().GetString("minecraft-version")
+	channel, _ := cmd.Flags().GetString("channel")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+	return deps.Resolve(cmd.Context(), createClient(), plugins, deps.Options{
+		Platform:         platform,
+		MinecraftVersion: minecraftVersion,
+		Channel:          channel,
+		Concurrency:      concurrency,
+	})
+}
+
+// installDeps downloads the resolved plugins into dir, keeping files that are already installed.
+func installDeps(cmd *cobra.Command, graph *deps.Graph, dir string, optional bool) error {
+	client := createClient()
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+	lock := &manifest.Lock{Version: manifest.LockVersion, MinecraftVersion: graph.MinecraftVersion}
+	for _, node := range graph.Installable(optional) {
+		plugin, err := manifest.Pin(cmd.Context(), client, node.Project, node.Resolved, graph.Platform)
+		if err != nil {
+			return errors.Wrapf(err, "failed to install %s", node.ID)
+		}
+		lock.Plugins = append(lock.Plugins, *plugin)
+	}
+
+	results, err := manifest.Install(cmd.Context(), client, lock, dir, concurrency)
+	if err != nil {
+		return err
+	}
+
+	installed := 0
+	for _, result := range results {
+		slog.Info("installed plugin", "slug", result.Slug, "version", result.Version, "file", result.File, "action", result.Action)
+		if result.Action == manifest.ActionInstalled {
+			installed++
+		}
+	}
+
+	// The summary goes to stderr to keep the structured output of the graph intact
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Installed %d of %d plugins into %s\n", installed, len(results), dir)
+
+	return nil
+}
+
+// depsColumns are the columns of the resolved plugins.
+func depsColumns(graph *deps.Graph) []output.Column[*deps.Node] {
+	dependents := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		dependents[edge.To] = append(dependents[edge.To], edge.From)
+	}
+
+	return []output.Column[*deps.Node]{
+		{Name: "id", Header: "Plugin", Value: func(n *deps.Node) any { return n.ID }},
+		{
+			Name:   "version",
+			Header: "Version",
+			Value:  func(n *deps.Node) any { return n.Version },
+			Format: func(n *deps.Node) string {
+				if n.Version == "" {
+					return "-"
+				}
+				return n.Version
+			},
+		},
+		{Name: "channel", Header: "Channel", Value: func(n *deps.Node) any { return n.Channel }},
+		{
+			Name:   "required",
+			Header: "Needed",
+			Value:  func(n *deps.Node) any { return n.Required },
+			Format: func(n *deps.Node) string {
+				switch {
+				case n.Root:
+					return "requested"
+				case n.Required:
+					return "required"
+				default:
+					return "optional"
+				}
+			},
+		},
+		{
+			Name:   "source",
+			Header: "Source",
+			Value:  func(n *deps.Node) any { return n.External },
+			Format: func(n *deps.Node) string {
+				switch {
+				case n.Missing:
+					return "missing"
+				case n.External:
+					return "external"
+				default:
+					return "hangar"
+				}
+			},
+		},
+		{
+			Name:   "requiredBy",
+			Header: "Required By",
+			Value:  func(n *deps.Node) any { return dependents[n.ID] },
+			Format: func(n *deps.Node) string { return strings.Join(dependents[n.ID], ", ") },
+		},
+	}
+}
+
+// renderDeps prints the resolved plugins and the problems as separate tables.
+func renderDeps(w io.Writer, graph *deps.Graph) error {
+	footer := fmt.Sprintf("%d plugins for %s", len(graph.Nodes), graph.Platform)
+	if graph.MinecraftVersion != "" {
+		footer += " on Minecraft " + graph.MinecraftVersion
+	}
+
+	err := output.Render(w, output.Options{Format: output.FormatTable}, output.View[*deps.Node]{
+		Items:   graph.Nodes,
+		Columns: depsColumns(graph),
+		Footer:  footer,
+	})
+	if err != nil || len(graph.Problems) == 0 {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "\nProblems (%d):\n", len(graph.Problems))
+
+	return output.Render(w, output.Options{Format: output.FormatTable}, output.View[deps.Problem]{
+		Items: graph.Problems,
+		Columns: []output.Column[deps.Problem]{
+			{Name: "kind", Header: "Kind", Value: func(p deps.Problem) any { return p.Kind }},
+			{Name: "dependency", Header: "Dependency", Value: func(p deps.Problem) any { return p.Dependency }},
+			{
+				Name:   "required",
+				Header: "Needed",
+				Value:  func(p deps.Problem) any { return p.Required },
+				Format: func(p deps.Problem) string {
+					if p.Required {
+						return "required"
+					}
+					return "optional"
+				},
+			},
+			{
+				Name:   "requiredBy",
+				Header: "Required By",
+				Value:  func(p deps.Problem) any { return p.RequiredBy },
+				Format: func(p deps.Problem) string { return strings.Join(p.RequiredBy, ", ") },
+			},
+			{Name: "message", Header: "Problem", Value: func(p deps.Problem) any { return p.Message }},
+		},
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(depsCmd)
+
+	// Deps command flags, shared with its subcommands
+	depsCmd.PersistentFlags().String("platform", manifest.DefaultPlatform, "Server platform (PAPER, WATERFALL, VELOCITY)")
+	depsCmd.PersistentFlags().String("minecraft-version", "", "Minecraft version of the server (e.g., 1.21.1)")
+	depsCmd.PersistentFlags().String("channel", manifest.DefaultChannel, "Release channel versions are picked from")
+	depsCmd.PersistentFlags().Int("concurrency", deps.DefaultConcurrency, "Number of projects fetched at the same time")
+	_ = depsCmd.RegisterFlagCompletionFunc("platform", completePlatforms)
+	_ = depsCmd.RegisterFlagCompletionFunc("channel", completeChannels)
+
+	depsCmd.Flags().Bool("install", false, "Download the plugins and their required dependencies into --dir")
+	depsCmd.Flags().Bool("optional", false, "Also install optional dependencies")
+	depsCmd.Flags().String("dir", "plugins", "Plugin directory to install into")
+	_ = depsCmd.RegisterFlagCompletionFunc("dir", completeDir)
+}
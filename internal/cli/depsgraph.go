@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/deps"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Graph formats accepted by deps graph --format.
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+	graphFormatJSON    = "json"
+)
+
+var depsGraphCmd = &cobra.Command{
+	Use:   "graph <slug>[@version]...",
+	Short: "Export the plugin dependency graph",
+	Long: `Resolve the plugin dependencies of projects like "hangar deps" does and print the
+dependency graph as Graphviz DOT, a Mermaid flowchart or JSON.
+
+Requested plugins are drawn bold, dependencies outside Hangar dashed and missing
+dependencies red. Required dependencies are solid edges, optional ones dashed edges
+labeled "optional". Edges between plugins that depend on each other, directly or
+indirectly, are highlighted in red; the JSON output lists these cycles.
+
+With --format json the global output options apply like for "hangar deps": -o yaml,
+-o go-template=... and -o jsonpath=... render the graph instead of JSON.`,
+	Example: `  hangar deps graph CoolPlugin --minecraft-version 1.21.1 | dot -Tsvg -o deps.svg
+  hangar deps graph CoolPlugin OtherPlugin --format mermaid
+  hangar deps graph CoolPlugin --format json`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSlugs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+
+		switch format {
+		case graphFormatDOT, graphFormatMermaid, graphFormatJSON:
+		default:
+			return errors.Newf("unsupported graph format: %s (expected %s, %s or %s)",
+				format, graphFormatDOT, graphFormatMermaid, graphFormatJSON)
+		}
+
+		graph, err := resolveDeps(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case graphFormatMermaid:
+			return graph.WriteMermaid(cmd.OutOrStdout())
+		case graphFormatJSON:
+			// The graph goes through the global output options; the default table format prints it as JSON
+			return render(cmd, output.View[*deps.Node]{
+				Data:    graph,
+				Items:   graph.Nodes,
+				Columns: depsColumns(graph),
+				Text: func(w io.Writer) error {
+					return output.Render(w, output.Options{Format: output.FormatJSON}, output.View[*deps.Node]{Data: graph})
+				},
+			})
+		default:
+			return graph.WriteDOT(cmd.OutOrStdout())
+		}
+	},
+}
+
+func init() {
+	depsCmd.AddCommand(depsGraphCmd)
+
+	// Deps graph command flags
+	depsGraphCmd.Flags().String("format", graphFormatDOT, "Graph format (dot, mermaid, json)")
+	_ = depsGraphCmd.RegisterFlagCompletionFunc("format",
+		cobra.FixedCompletions([]string{graphFormatDOT, graphFormatMermaid, graphFormatJSON}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of commands whose exit status reports a result. Errors exit with status 1.
+const (
+	// exitOutdated reports that updates are available.
+	exitOutdated = 2
+	// exitChanged reports that files were changed, or would be changed by a dry run.
+	exitChanged = 2
+	// exitBlocked reports that plugins block a Minecraft upgrade.
+	exitBlocked = 2
+)
+
+// ExitError ends a command with a specific exit status without printing an error.
+type ExitError struct {
+	// Code is the exit status.
+	Code int
+}
+
+// Error implements the error interface.
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// exitWith returns an ExitError for code and silences the error and usage output of cmd,
+// as the command already printed its result.
+func exitWith(cmd *cobra.Command, code int) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	return &ExitError{Code: code}
+}
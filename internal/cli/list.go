@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/spf13/cobra"
+)
+
+// addListFlags registers the pagination flags of list commands.
+func addListFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("limit", 25, "Maximum number of results")
+	cmd.Flags().Int("offset", 0, "Offset for pagination")
+	cmd.Flags().Bool("all", false, "Fetch all results starting at --offset, ignoring --limit")
+}
+
+// listPages fetches the page selected by --limit and --offset, or every page with --all,
+// so that --filter and --sort-by apply to the complete result.
+func listPages[T any](cmd *cobra.Command, opts hangar.ListOptions, fetch hangar.PageFunc[T]) ([]T, hangar.Pagination, error) {
+	opts.Limit, _ = cmd.Flags().GetInt("limit")
+	opts.Offset, _ = cmd.Flags().GetInt("offset")
+
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return hangar.CollectAll(cmd.Context(), opts, fetch)
+	}
+
+	return fetch(cmd.Context(), opts)
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/manifest"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Resolve the plugin manifest into a lockfile",
+	Long: `Resolve every plugin declared in the manifest (hangar.yaml) to an exact version and
+write the download URLs and SHA-256 checksums to the lockfile (hangar.lock).
+
+Each plugin is locked to the highest version that satisfies its version constraint, is
+published in its channel (Release by default), has a download for its platform and
+supports the Minecraft version of the manifest. Artifacts hosted outside Hangar are
+downloaded once to compute their checksums.
+
+Manifest example:
+
+  platform: PAPER
+  minecraft: 1.21.1
+  plugins:
+    - slug: Maintenance
+      version: ~4.2
+    - slug: CoolPlugin
+      version: ">=2.0, <3"
+      channel: Beta
+
+Version constraints are exact versions (2.20.1), comparisons joined by commas
+(">=2.20, <3", "!=2.21"), tilde ranges (~2.20 allows 2.20.x) and caret ranges
+(^2.20 allows 2.x). An empty constraint or * allows any version.`,
+	Example: `  hangar lock
+  hangar lock --manifest servers/lobby/hangar.yaml --lock servers/lobby/hangar.lock`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		manifestPath, _ := cmd.Flags().GetString("manifest")
+		lockPath, _ := cmd.Flags().GetString("lock")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+		m, err := manifest.LoadManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+
+		lock, err := manifest.Resolve(cmd.Context(), createClient(), m, concurrency)
+		if err != nil {
+			return err
+		}
+
+		if err := lock.Save(lockPath); err != nil {
+			return err
+		}
+
+		return render(cmd, output.View[manifest.LockedPlugin]{
+			Data:    lock,
+			Items:   lock.Plugins,
+			Columns: lockColumns,
+			Footer:  fmt.Sprintf("Locked %d plugins in %s", len(lock.Plugins), lockPath),
+		})
+	},
+}
+
+var installCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the plugins pinned in the lockfile",
+	Long: `Download the exact artifacts pinned in the lockfile (hangar.lock) into the plugin
+directory. Every download is verified against its locked SHA-256 checksum before it
+replaces the installed file, so a server installed from the same lockfile always gets
+the same jars.
+
+Files that already have the locked checksum are kept. Files not listed in the lockfile
+are left alone. A warning is logged when the manifest changed since the lockfile was
+written; run "hangar lock" to update it.`,
+	Example: `  hangar install
+  hangar install --lock servers/lobby/hangar.lock --dir servers/lobby/plugins`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		lockPath, _ := cmd.Flags().GetString("lock")
+		manifestPath, _ := cmd.Flags().GetString("manifest")
+		dir, _ := cmd.Flags().GetString("dir")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+		lock, err := manifest.LoadLock(lockPath)
+		if err != nil {
+			return err
+		}
+		warnStaleLock(lock, manifestPath)
+
+		results, err := manifest.Install(cmd.Context(), createClient(), lock, dir, concurrency)
+		if err != nil {
+			return err
+		}
+
+		installed := 0
+		for _, result := range results {
+			if result.Action == manifest.ActionInstalled {
+				installed++
+			}
+		}
+
+		return render(cmd, output.View[manifest.InstallResult]{
+			Items:   results,
+			Columns: installColumns,
+			Footer:  fmt.Sprintf("Installed %d of %d plugins into %s", installed, len(results), dir),
+		})
+	},
+}
+
+// lockColumns are the columns of the locked plugins.
+var lockColumns = []output.Column[manifest.LockedPlugin]{
+	{Name: "slug", Header: "Slug", Value: func(p manifest.LockedPlugin) any { return p.Slug }},
+	{Name: "version", Header: "Version", Value: func(p manifest.LockedPlugin) any { return p.Version }},
+	{Name: "channel", Header: "Channel", Value: func(p manifest.LockedPlugin) any { return p.Channel }},
+	{Name: "platform", Header: "Platform", Value: func(p manifest.LockedPlugin) any { return p.Platform }},
+	{Name: "file", Header: "File", Value: func(p manifest.LockedPlugin) any { return p.File }},
+	{
+		Name:   "sha256",
+		Header: "SHA-256",
+		Value:  func(p manifest.LockedPlugin) any { return p.SHA256 },
+		Format: func(p manifest.LockedPlugin) string { return p.SHA256[:min(len(p.SHA256), 12)] },
+	},
+}
+
+// installColumns are the columns of the install results.
+var installColumns = []output.Column[manifest.InstallResult]{
+	{Name: "slug", Header: "Slug", Value: func(r manifest.InstallResult) any { return r.Slug }},
+	{Name: "version", Header: "Version", Value: func(r manifest.InstallResult) any { return r.Version }},
+	{Name: "file", Header: "File", Value: func(r manifest.InstallResult) any { return r.File }},
+	{Name: "action", Header: "Action", Value: func(r manifest.InstallResult) any { return r.Action }},
+}
+
+// warnStaleLock logs a warning when the manifest exists and changed since the lock was resolved.
+func warnStaleLock(lock *manifest.Lock, manifestPath string) {
+	m, err := manifest.LoadManifest(manifestPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("failed to check whether the lockfile is up to date", "error", err)
+		}
+		return
+	}
+
+	if lock.Stale(m) {
+		slog.Warn("the manifest changed since the lockfile was written; run hangar lock to update it",
+			"manifest", manifestPath)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(installCmd)
+
+	// Lock command flags
+	lockCmd.Flags().String("manifest", manifest.DefaultManifestFile, "Path of the plugin manifest")
+	lockCmd.Flags().String("lock", manifest.DefaultLockFile, "Path of the lockfile to write")
+	lockCmd.Flags().Int("concurrency", manifest.DefaultConcurrency, "Number of plugins resolved at the same time")
+
+	// Install command flags
+	installCmd.Flags().String("lock", manifest.DefaultLockFile, "Path of the lockfile")
+	installCmd.Flags().String("manifest", manifest.DefaultManifestFile, "Path of the plugin manifest, used to detect an outdated lockfile")
+	installCmd.Flags().String("dir", "plugins", "Plugin directory to install into")
+	installCmd.Flags().Int("concurrency", manifest.DefaultConcurrency, "Number of plugins downloaded at the same time")
+	_ = installCmd.RegisterFlagCompletionFunc("dir", completeDir)
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Log formats accepted by --log-format.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// newLogger creates the CLI logger. It logs warnings and errors by default, informational
+// messages with -v and debug messages, including API requests, with -vv.
+// quiet limits it to errors.
+func newLogger(w io.Writer, verbosity int, quiet bool, format string) (*slog.Logger, error) {
+	level := slog.LevelWarn
+	switch {
+	case quiet:
+		level = slog.LevelError
+	case verbosity == 1:
+		level = slog.LevelInfo
+	case verbosity >= 2:
+		level = slog.LevelDebug
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch format {
+	case logFormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case logFormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, errors.Newf("unsupported log format: %s (expected %s or %s)", format, logFormatText, logFormatJSON)
+	}
+}
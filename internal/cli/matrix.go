@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/spf13/cobra"
+)
+
+var projectMatrixCmd = &cobra.Command{
+	Use:   "matrix <slug>",
+	Short: "Show which versions support which platforms and game versions",
+	Long: `Fetch every version of a project and show a compatibility grid of version, platform
+and supported game versions, built from the platform dependencies and downloads of each
+version.
+
+Consecutive patch versions of a Minecraft release are collapsed into ranges like
+"1.20-1.20.4"; gaps are listed separately, e.g. "1.20-1.20.2, 1.20.4". Versions that do
+not declare any game version for a platform are shown as "unspecified". Platforms that are only
+available as an external download are marked "(external)", platforms without a download
+"(no download)".
+
+Use -o markdown to paste the grid into a README or release notes, or -o json for the full
+list of game versions per cell.`,
+	Example: `  hangar project matrix CoolPlugin
+  hangar project matrix CoolPlugin -o markdown > COMPATIBILITY.md
+  hangar project matrix CoolPlugin -o json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSlugs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := createClient()
+
+		project, err := client.GetProject(ctx, args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to get project")
+		}
+
+		versions, err := client.ListAllVersions(ctx, project.Namespace.Owner, project.Namespace.Slug)
+		if err != nil {
+			return errors.Wrap(err, "failed to list versions")
+		}
+
+		matrix := hangar.BuildCompatibilityMatrix(versions)
+
+		return render(cmd, output.View[hangar.MatrixRow]{
+			Data:    matrix,
+			Items:   matrix.Rows,
+			Columns: matrixColumns(matrix),
+			Footer:  fmt.Sprintf("%d versions on %d platforms", len(matrix.Rows), len(matrix.Platforms)),
+		})
+	},
+}
+
+// matrixColumns are the version columns followed by one column per platform of the matrix.
+func matrixColumns(matrix *hangar.CompatibilityMatrix) []output.Column[hangar.MatrixRow] {
+	columns := []output.Column[hangar.MatrixRow]{
+		{Name: "version", Header: "Version", Value: func(r hangar.MatrixRow) any { return r.Version }},
+		{Name: "channel", Header: "Channel", Value: func(r hangar.MatrixRow) any { return r.Channel }},
+	}
+
+	for _, platform := range matrix.Platforms {
+		columns = append(columns, output.Column[hangar.MatrixRow]{
+			Name:   strings.ToLower(platform),
+			Header: platform,
+			Value:  func(r hangar.MatrixRow) any { return strings.Join(r.Platforms[platform].Ranges, ", ") },
+			Format: func(r hangar.MatrixRow) string { return matrixCell(r.Platforms, platform) },
+		})
+	}
+
+	return columns
+}
+
+// matrixCell formats the support of a version for a platform.
+func matrixCell(platforms map[string]hangar.MatrixCell, platform string) string {
+	cell, ok := platforms[platform]
+	if !ok {
+		return "-"
+	}
+
+	text := strings.Join(cell.Ranges, ", ")
+	if text == "" {
+		text = "unspecified"
+	}
+
+	switch cell.Download {
+	case hangar.DownloadExternal:
+		text += " (external)"
+	case hangar.DownloadNone:
+		text += " (no download)"
+	}
+
+	return text
+}
+
+func init() {
+	projectCmd.AddCommand(projectMatrixCmd)
+}
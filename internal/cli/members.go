@@ -1,176 +1,108 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/cockroachdb/errors"
-	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/lexfrei/go-hangar/internal/output"
 	"github.com/lexfrei/go-hangar/pkg/hangar"
 	"github.com/spf13/cobra"
 )
 
 var projectMembersCmd = &cobra.Command{
-	Use:   "members <slug>",
-	Short: "Get project team members",
-	Long:  "Retrieve a list of team members for a project.",
-	Args:  cobra.ExactArgs(1),
+	Use:               "members <slug>",
+	Short:             "Get project team members",
+	Long:              "Retrieve a list of team members for a project.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSlugs),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := cmd.Context()
 		slug := args[0]
 
-		limit, _ := cmd.Flags().GetInt("limit")
-		offset, _ := cmd.Flags().GetInt("offset")
-
 		client := createClient()
-		list, err := client.GetProjectMembers(ctx, slug, hangar.ListOptions{
-			Limit:  limit,
-			Offset: offset,
-		})
+		items, pagination, err := listPages(cmd, hangar.ListOptions{},
+			func(ctx context.Context, opts hangar.ListOptions) ([]hangar.ProjectMember, hangar.Pagination, error) {
+				page, err := client.GetProjectMembers(ctx, slug, opts)
+				if err != nil {
+					return nil, hangar.Pagination{}, err
+				}
+				return page.Result, page.Pagination, nil
+			})
 		if err != nil {
 			return errors.Wrap(err, "failed to get project members")
 		}
+		list := &hangar.MemberList{Pagination: pagination, Result: items}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(list); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Username", "Roles", "Accepted"})
-			for _, member := range list.Result {
-				roles := ""
-				for i, role := range member.Roles {
-					if i > 0 {
-						roles += ", "
-					}
-					roles += role.Name
-				}
-				accepted := "Yes"
-				if !member.Accepted {
-					accepted = "No (Pending)"
-				}
-				t.AppendRow(table.Row{
-					member.User,
-					roles,
-					accepted,
-				})
-			}
-			t.Render()
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nTotal: %d members\n", list.Pagination.Count)
-		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.ProjectMember]{
+			Data:    list,
+			Items:   list.Result,
+			Columns: memberColumns,
+			Footer:  fmt.Sprintf("Total: %d members", list.Pagination.Count),
+		})
 	},
 }
 
 var projectStargazersCmd = &cobra.Command{
-	Use:   "stargazers <slug>",
-	Short: "Get users who starred the project",
-	Long:  "Retrieve a list of users who have starred a project.",
-	Args:  cobra.ExactArgs(1),
+	Use:               "stargazers <slug>",
+	Short:             "Get users who starred the project",
+	Long:              "Retrieve a list of users who have starred a project.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSlugs),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := cmd.Context()
 		slug := args[0]
 
-		limit, _ := cmd.Flags().GetInt("limit")
-		offset, _ := cmd.Flags().GetInt("offset")
-
 		client := createClient()
-		list, err := client.GetProjectStargazers(ctx, slug, hangar.ListOptions{
-			Limit:  limit,
-			Offset: offset,
-		})
+		items, pagination, err := listPages(cmd, hangar.ListOptions{},
+			func(ctx context.Context, opts hangar.ListOptions) ([]hangar.User, hangar.Pagination, error) {
+				page, err := client.GetProjectStargazers(ctx, slug, opts)
+				if err != nil {
+					return nil, hangar.Pagination{}, err
+				}
+				return page.Result, page.Pagination, nil
+			})
 		if err != nil {
 			return errors.Wrap(err, "failed to get project stargazers")
 		}
+		list := &hangar.UserList{Pagination: pagination, Result: items}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(list); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Username", "Projects", "Joined"})
-			for _, user := range list.Result {
-				t.AppendRow(table.Row{
-					user.Name,
-					user.ProjectCount,
-					user.JoinDate.Format("2006-01-02"),
-				})
-			}
-			t.Render()
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nTotal: %d stargazers\n", list.Pagination.Count)
-		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.User]{
+			Data:    list,
+			Items:   list.Result,
+			Columns: userColumns,
+			Footer:  fmt.Sprintf("Total: %d stargazers", list.Pagination.Count),
+		})
 	},
 }
 
 var projectWatchersCmd = &cobra.Command{
-	Use:   "watchers <slug>",
-	Short: "Get users watching the project",
-	Long:  "Retrieve a list of users who are watching a project.",
-	Args:  cobra.ExactArgs(1),
+	Use:               "watchers <slug>",
+	Short:             "Get users watching the project",
+	Long:              "Retrieve a list of users who are watching a project.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSlugs),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := cmd.Context()
 		slug := args[0]
 
-		limit, _ := cmd.Flags().GetInt("limit")
-		offset, _ := cmd.Flags().GetInt("offset")
-
 		client := createClient()
-		list, err := client.GetProjectWatchers(ctx, slug, hangar.ListOptions{
-			Limit:  limit,
-			Offset: offset,
-		})
+		items, pagination, err := listPages(cmd, hangar.ListOptions{},
+			func(ctx context.Context, opts hangar.ListOptions) ([]hangar.User, hangar.Pagination, error) {
+				page, err := client.GetProjectWatchers(ctx, slug, opts)
+				if err != nil {
+					return nil, hangar.Pagination{}, err
+				}
+				return page.Result, page.Pagination, nil
+			})
 		if err != nil {
 			return errors.Wrap(err, "failed to get project watchers")
 		}
+		list := &hangar.UserList{Pagination: pagination, Result: items}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(list); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Username", "Projects", "Joined"})
-			for _, user := range list.Result {
-				t.AppendRow(table.Row{
-					user.Name,
-					user.ProjectCount,
-					user.JoinDate.Format("2006-01-02"),
-				})
-			}
-			t.Render()
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nTotal: %d watchers\n", list.Pagination.Count)
-		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.User]{
+			Data:    list,
+			Items:   list.Result,
+			Columns: userColumns,
+			Footer:  fmt.Sprintf("Total: %d watchers", list.Pagination.Count),
+		})
 	},
 }
 
@@ -180,14 +112,11 @@ func init() {
 	projectCmd.AddCommand(projectWatchersCmd)
 
 	// Members command flags
-	projectMembersCmd.Flags().Int("limit", 25, "Maximum number of results")
-	projectMembersCmd.Flags().Int("offset", 0, "Offset for pagination")
+	addListFlags(projectMembersCmd)
 
 	// Stargazers command flags
-	projectStargazersCmd.Flags().Int("limit", 25, "Maximum number of results")
-	projectStargazersCmd.Flags().Int("offset", 0, "Offset for pagination")
+	addListFlags(projectStargazersCmd)
 
 	// Watchers command flags
-	projectWatchersCmd.Flags().Int("limit", 25, "Maximum number of results")
-	projectWatchersCmd.Flags().Int("offset", 0, "Offset for pagination")
+	addListFlags(projectWatchersCmd)
 }
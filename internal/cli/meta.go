@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+)
+
+// printResponseMeta returns a response hook printing the metadata of every response to w.
+func printResponseMeta(w io.Writer) func(hangar.ResponseInfo) {
+	var mu sync.Mutex
+
+	return func(info hangar.ResponseInfo) {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "> %s %s\n", info.Method, hangar.RedactURL(info.URL))
+		fmt.Fprintf(&sb, "< %s in %s\n", info.Status, info.Elapsed.Round(time.Millisecond))
+		if info.Redirected() {
+			fmt.Fprintf(&sb, "< Redirected to %s\n", hangar.RedactURL(info.EffectiveURL))
+		}
+
+		header := hangar.RedactHeader(info.Header)
+		names := make([]string, 0, len(header))
+		for name := range header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, value := range header[name] {
+				fmt.Fprintf(&sb, "< %s: %s\n", name, value)
+			}
+		}
+
+		// Responses of concurrent requests are printed one at a time
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintln(w, sb.String())
+	}
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/internal/scan"
+	"github.com/spf13/cobra"
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated <dir>",
+	Short: "Check installed plugins for updates",
+	Long: `Identify the plugin jars in a directory like "hangar scan" does and compare each installed
+version with the latest version published for the server's platform and Minecraft version.
+
+Only versions in the accepted channels are considered, Release by default. Pass
+--channels Release,Beta to also accept beta versions. For every outdated plugin the
+beginning of the changelog of the latest version is shown.
+
+The command exits with status 2 when updates are available, so it can gate CI pipelines.
+Jars that cannot be identified are ignored.`,
+	Example: `  hangar outdated ./plugins --minecraft-version 1.21.1
+  hangar outdated ./plugins --platform PAPER --channels Release,Beta
+  hangar outdated ./plugins -o json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDir,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		platform, _ := cmd.Flags().GetString("platform")
+		minecraftVersion, _ := cmd.Flags().GetString("minecraft-version")
+		channels, _ := cmd.Flags().GetStringSlice("channels")
+		changelogLines, _ := cmd.Flags().GetInt("changelog-lines")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+		if len(channels) == 0 {
+			return errors.New("--channels must name at least one channel")
+		}
+
+		report, err := scanDir(cmd, args[0])
+		if err != nil {
+			return err
+		}
+
+		updates, err := scan.CheckUpdates(cmd.Context(), createClient(), report.Plugins, scan.UpdateOptions{
+			Platform:         platform,
+			MinecraftVersion: minecraftVersion,
+			Channels:         channels,
+			ChangelogLines:   changelogLines,
+			Concurrency:      concurrency,
+		})
+		if err != nil {
+			return err
+		}
+
+		outdated := 0
+		for _, update := range updates {
+			if update.Outdated {
+				outdated++
+			}
+		}
+
+		footer := "All plugins are up to date"
+		if outdated > 0 {
+			footer = fmt.Sprintf("%d of %d plugins have updates available", outdated, len(updates))
+		}
+
+		err = render(cmd, output.View[scan.Update]{
+			Items:   updates,
+			Columns: outdatedColumns,
+			Footer:  footer,
+		})
+		if err != nil {
+			return err
+		}
+
+		if outdated > 0 {
+			return exitWith(cmd, exitOutdated)
+		}
+
+		return nil
+	},
+}
+
+// outdatedColumns are the columns of the update check.
+var outdatedColumns = []output.Column[scan.Update]{
+	{Name: "file", Header: "File", Value: func(u scan.Update) any { return u.File }},
+	{Name: "slug", Header: "Slug", Value: func(u scan.Update) any { return u.Slug }},
+	{Name: "current", Header: "Current", Value: func(u scan.Update) any { return u.Current }},
+	{
+		Name:   "latest",
+		Header: "Latest",
+		Value:  func(u scan.Update) any { return u.Latest },
+		Format: func(u scan.Update) string {
+			if u.Latest == "" {
+				return "-"
+			}
+			return u.Latest
+		},
+	},
+	{Name: "channel", Header: "Channel", Value: func(u scan.Update) any { return u.Channel }},
+	{Name: "releasedAt", Header: "Released", Value: func(u scan.Update) any { return u.ReleasedAt }},
+	{
+		Name:   "outdated",
+		Header: "Status",
+		Value:  func(u scan.Update) any { return u.Outdated },
+		Format: func(u scan.Update) string {
+			switch {
+			case u.Outdated:
+				return "outdated"
+			case u.Latest == "":
+				return "no matching version"
+			default:
+				return "up to date"
+			}
+		},
+	},
+	{Name: "changelog", Header: "Changelog", Value: func(u scan.Update) any { return u.Changelog }},
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+
+	// Outdated command flags
+	addScanFlags(outdatedCmd)
+	outdatedCmd.Flags().String("platform", "", "Server platform (PAPER, WATERFALL, VELOCITY; default: the platform of each jar)")
+	outdatedCmd.Flags().String("minecraft-version", "", "Minecraft version of the server (e.g., 1.21.1)")
+	outdatedCmd.Flags().StringSlice("channels", scan.DefaultChannels, "Accepted release channels, e.g. Release,Beta")
+	outdatedCmd.Flags().Int("changelog-lines", 3, "Number of changelog lines shown for outdated plugins (0 to hide)")
+	_ = outdatedCmd.RegisterFlagCompletionFunc("platform", completePlatforms)
+	_ = outdatedCmd.RegisterFlagCompletionFunc("channels", completeChannelList)
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// render writes a command result in the format selected by --output and --template-file,
+// after applying --filter, --sort-by, --reverse and --columns.
+func render[T any](cmd *cobra.Command, view output.View[T]) error {
+	opts, err := output.ParseOptions(viper.GetString("output"), cmd.Flag("template-file").Value.String())
+	if err != nil {
+		return err
+	}
+	opts.Columns = columns
+	opts.SortBy = sortBy
+	opts.Reverse = reverse
+	opts.Filter = filter
+
+	return output.Render(cmd.OutOrStdout(), opts, view)
+}
+
+// roleNames joins role names for display.
+func roleNames(roles []hangar.Role) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// projectColumns are the columns of project lists.
+var projectColumns = []output.Column[hangar.Project]{
+	{Name: "name", Header: "Name", Value: func(p hangar.Project) any { return p.Name }},
+	{Name: "slug", Header: "Slug", Value: func(p hangar.Project) any { return p.Namespace.Slug }},
+	{Name: "category", Header: "Category", Value: func(p hangar.Project) any { return p.Category }},
+	{Name: "downloads", Header: "Downloads", Value: func(p hangar.Project) any { return p.Stats.Downloads }},
+	{Name: "views", Header: "Views", Value: func(p hangar.Project) any { return p.Stats.Views }},
+	{Name: "stars", Header: "Stars", Value: func(p hangar.Project) any { return p.Stats.Stars }},
+	{Name: "watchers", Header: "Watchers", Value: func(p hangar.Project) any { return p.Stats.Watchers }},
+}
+
+// projectDetailColumns are the fields of a single project.
+var projectDetailColumns = []output.Column[hangar.Project]{
+	{Name: "id", Header: "ID", Value: func(p hangar.Project) any { return p.ID }},
+	{Name: "name", Header: "Name", Value: func(p hangar.Project) any { return p.Name }},
+	{Name: "slug", Header: "Slug", Value: func(p hangar.Project) any { return p.Namespace.Slug }},
+	{Name: "owner", Header: "Owner", Value: func(p hangar.Project) any { return p.Namespace.Owner }},
+	{Name: "category", Header: "Category", Value: func(p hangar.Project) any { return p.Category }},
+	{Name: "description", Header: "Description", Value: func(p hangar.Project) any { return p.Description }},
+	{Name: "views", Header: "Views", Value: func(p hangar.Project) any { return p.Stats.Views }},
+	{Name: "downloads", Header: "Downloads", Value: func(p hangar.Project) any { return p.Stats.Downloads }},
+	{Name: "stars", Header: "Stars", Value: func(p hangar.Project) any { return p.Stats.Stars }},
+	{Name: "createdAt", Header: "Created", Value: func(p hangar.Project) any { return p.CreatedAt }},
+	{Name: "lastUpdated", Header: "Last Updated", Value: func(p hangar.Project) any { return p.LastUpdated }},
+}
+
+// versionDetailColumns are the fields of a single version.
+var versionDetailColumns = []output.Column[hangar.Version]{
+	{Name: "id", Header: "ID", Value: func(v hangar.Version) any { return v.ID }},
+	{Name: "name", Header: "Name", Value: func(v hangar.Version) any { return v.Name }},
+	{Name: "author", Header: "Author", Value: func(v hangar.Version) any { return v.Author }},
+	{
+		Name:   "createdAt",
+		Header: "Created",
+		Value:  func(v hangar.Version) any { return v.CreatedAt },
+		Format: func(v hangar.Version) string { return v.CreatedAt.Format("2006-01-02 15:04:05") },
+	},
+	{Name: "visibility", Header: "Visibility", Value: func(v hangar.Version) any { return v.Visibility }},
+	{Name: "reviewState", Header: "Review State", Value: func(v hangar.Version) any { return v.ReviewState }},
+	{Name: "downloads", Header: "Downloads", Value: func(v hangar.Version) any { return v.Stats.TotalDownloads }},
+}
+
+// userColumns are the columns of user lists.
+var userColumns = []output.Column[hangar.User]{
+	{Name: "name", Header: "Username", Value: func(u hangar.User) any { return u.Name }},
+	{Name: "projectCount", Header: "Projects", Value: func(u hangar.User) any { return u.ProjectCount }},
+	{Name: "joinDate", Header: "Joined", Value: func(u hangar.User) any { return u.JoinDate }},
+	{Name: "roles", Header: "Roles", Value: func(u hangar.User) any { return roleNames(u.Roles) }},
+}
+
+// userDetailColumns are the fields of a single user.
+var userDetailColumns = []output.Column[hangar.User]{
+	{Name: "name", Header: "Username", Value: func(u hangar.User) any { return u.Name }},
+	{Name: "tagline", Header: "Tagline", Value: func(u hangar.User) any { return u.TagLine }},
+	{Name: "joinDate", Header: "Joined", Value: func(u hangar.User) any { return u.JoinDate }},
+	{Name: "projectCount", Header: "Projects", Value: func(u hangar.User) any { return u.ProjectCount }},
+	{Name: "locked", Header: "Locked", Value: func(u hangar.User) any { return u.Locked }},
+	{Name: "roles", Header: "Roles", Value: func(u hangar.User) any { return roleNames(u.Roles) }},
+}
+
+// authorColumns are the columns of author lists.
+var authorColumns = []output.Column[hangar.Author]{
+	{Name: "name", Header: "Username", Value: func(a hangar.Author) any { return a.Name }},
+	{Name: "projectCount", Header: "Projects", Value: func(a hangar.Author) any { return a.ProjectCount }},
+	{Name: "joinDate", Header: "Joined", Value: func(a hangar.Author) any { return a.JoinDate }},
+	{Name: "roles", Header: "Roles", Value: func(a hangar.Author) any { return roleNames(a.Roles) }},
+}
+
+// staffColumns are the columns of the staff list.
+var staffColumns = []output.Column[hangar.StaffMember]{
+	{Name: "name", Header: "Username", Value: func(s hangar.StaffMember) any { return s.Name }},
+	{Name: "roles", Header: "Roles", Value: func(s hangar.StaffMember) any { return roleNames(s.Roles) }},
+	{Name: "joinDate", Header: "Joined", Value: func(s hangar.StaffMember) any { return s.JoinDate }},
+}
+
+// memberColumns are the columns of project member lists.
+var memberColumns = []output.Column[hangar.ProjectMember]{
+	{Name: "user", Header: "Username", Value: func(m hangar.ProjectMember) any { return m.User }},
+	{Name: "roles", Header: "Roles", Value: func(m hangar.ProjectMember) any { return roleNames(m.Roles) }},
+	{
+		Name:   "accepted",
+		Header: "Accepted",
+		Value:  func(m hangar.ProjectMember) any { return m.Accepted },
+		Format: func(m hangar.ProjectMember) string {
+			if m.Accepted {
+				return "Yes"
+			}
+			return "No (Pending)"
+		},
+	},
+}
+
+// pageColumns are the fields of a project page.
+var pageColumns = []output.Column[hangar.Page]{
+	{Name: "id", Header: "ID", Value: func(p hangar.Page) any { return p.ID }},
+	{Name: "name", Header: "Name", Value: func(p hangar.Page) any { return p.Name }},
+	{Name: "slug", Header: "Slug", Value: func(p hangar.Page) any { return p.Slug }},
+	{Name: "contents", Header: "Contents", Value: func(p hangar.Page) any { return p.Contents }},
+}
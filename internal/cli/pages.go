@@ -1,18 +1,21 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
 	"github.com/spf13/cobra"
 )
 
 var projectPageCmd = &cobra.Command{
-	Use:   "page <slug> [path]",
-	Short: "Get project page content",
-	Long:  "Retrieve the Markdown content of a project page. Defaults to 'home' page if path not specified.",
-	Args:  cobra.RangeArgs(1, 2),
+	Use:               "page <slug> [path]",
+	Short:             "Get project page content",
+	Long:              "Retrieve the Markdown content of a project page. Defaults to 'home' page if path not specified.",
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: positional(completeSlugs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		slug := args[0]
@@ -28,30 +31,27 @@ var projectPageCmd = &cobra.Command{
 			return errors.Wrap(err, "failed to get project page")
 		}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(page); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		default:
-			// For table and other formats, print Markdown content
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "# %s (%s)\n\n", page.Name, page.Slug)
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), page.Contents)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.Page]{
+			Data:    page,
+			Items:   []hangar.Page{*page},
+			Columns: pageColumns,
+			Detail:  true,
+			// For table output, print the Markdown content
+			Text: func(w io.Writer) error {
+				_, _ = fmt.Fprintf(w, "# %s (%s)\n\n", page.Name, page.Slug)
+				_, err := fmt.Fprintln(w, page.Contents)
+				return err
+			},
+		})
 	},
 }
 
 var projectReadmeCmd = &cobra.Command{
-	Use:   "readme <slug>",
-	Short: "Get project README (main page)",
-	Long:  "Retrieve the main README page content of a project.",
-	Args:  cobra.ExactArgs(1),
+	Use:               "readme <slug>",
+	Short:             "Get project README (main page)",
+	Long:              "Retrieve the main README page content of a project.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSlugs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		slug := args[0]
@@ -62,21 +62,17 @@ var projectReadmeCmd = &cobra.Command{
 			return errors.Wrap(err, "failed to get project README")
 		}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(page); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		default:
-			// For table and other formats, print Markdown content
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), page.Contents)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.Page]{
+			Data:    page,
+			Items:   []hangar.Page{*page},
+			Columns: pageColumns,
+			Detail:  true,
+			// For table output, print the Markdown content
+			Text: func(w io.Writer) error {
+				_, err := fmt.Fprintln(w, page.Contents)
+				return err
+			},
+		})
 	},
 }
 
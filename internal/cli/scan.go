@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/jarinfo"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/internal/scan"
+	"github.com/spf13/cobra"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan <dir>",
+	Short: "Identify the plugin jars of a server",
+	Long: `Compute the SHA-256 checksum of every jar in a directory, such as a server's plugins
+folder, and look it up on Hangar to find the project and version it belongs to.
+
+For every identified jar the report shows the project slug, the installed version and
+channel, and whether a newer version was published in the same channel for the same
+platform.
+
+Jars with an unknown checksum, e.g. rebuilt or patched ones, are identified from their
+plugin.yml, paper-plugin.yml, bungee.yml or velocity-plugin.json instead: the plugin name
+is searched on Hangar and candidates are scored by name, owner and published versions.
+Matches below --min-confidence and jars without a descriptor are listed separately.`,
+	Example: `  hangar scan ./plugins
+  hangar scan ./plugins -o csv
+  hangar scan ./plugins -o json
+  hangar scan ./plugins --hash-only`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeDir,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report, err := scanDir(cmd, args[0])
+		if err != nil {
+			return err
+		}
+
+		// Row-based formats list unknown jars as rows without a project
+		rows := append([]scan.Plugin{}, report.Plugins...)
+		for _, jar := range report.Unknown {
+			rows = append(rows, scan.Plugin{Jar: jar})
+		}
+
+		return render(cmd, output.View[scan.Plugin]{
+			Data:    report,
+			Items:   rows,
+			Columns: scanColumns,
+			Text: func(w io.Writer) error {
+				return renderScanReport(w, report)
+			},
+		})
+	},
+}
+
+// scanDir scans a plugin directory with the options of the scan flags.
+func scanDir(cmd *cobra.Command, dir string) (*scan.Report, error) {
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	hashOnly, _ := cmd.Flags().GetBool("hash-only")
+	minConfidence, _ := cmd.Flags().GetFloat64("min-confidence")
+
+	if minConfidence <= 0 || minConfidence > 1 {
+		return nil, errors.New("--min-confidence must be greater than 0 and at most 1")
+	}
+
+	report, err := scan.Scan(cmd.Context(), createClient(), dir, scan.Options{
+		Concurrency:   concurrency,
+		HashOnly:      hashOnly,
+		MinConfidence: minConfidence,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scan plugins")
+	}
+
+	return report, nil
+}
+
+// addScanFlags adds the flags of commands that scan a plugin directory.
+func addScanFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("concurrency", scan.DefaultConcurrency, "Maximum number of jars resolved concurrently")
+	cmd.Flags().Bool("hash-only", false, "Identify jars by checksum only, without reading their plugin descriptors")
+	cmd.Flags().Float64("min-confidence", jarinfo.DefaultMinConfidence,
+		"Minimum confidence (0-1] of a plugin descriptor match")
+}
+
+// scanColumns are the columns of scanned jars.
+var scanColumns = []output.Column[scan.Plugin]{
+	{Name: "file", Header: "File", Value: func(p scan.Plugin) any { return p.File }},
+	{Name: "slug", Header: "Slug", Value: func(p scan.Plugin) any { return p.Slug }},
+	{Name: "version", Header: "Version", Value: func(p scan.Plugin) any { return p.Version }},
+	{Name: "channel", Header: "Channel", Value: func(p scan.Plugin) any { return p.Channel }},
+	{Name: "platform", Header: "Platform", Value: func(p scan.Plugin) any { return p.Platform }},
+	{Name: "latest", Header: "Latest", Value: func(p scan.Plugin) any { return p.Latest }},
+	{Name: "status", Header: "Status", Value: func(p scan.Plugin) any { return scanStatus(p) }},
+	{
+		Name:   "matchedBy",
+		Header: "Match",
+		Value:  func(p scan.Plugin) any { return p.MatchedBy },
+		Format: func(p scan.Plugin) string {
+			if p.MatchedBy == scan.MatchedByMetadata {
+				return fmt.Sprintf("%s (%.0f%%)", p.MatchedBy, p.Confidence*100)
+			}
+			return p.MatchedBy
+		},
+	},
+	{Name: "confidence", Header: "Confidence", Value: func(p scan.Plugin) any { return p.Confidence }},
+	{Name: "sha256", Header: "SHA-256", Value: func(p scan.Plugin) any { return p.SHA256 }},
+}
+
+// scanStatus describes whether a scanned jar is known and up to date.
+func scanStatus(p scan.Plugin) string {
+	switch {
+	case p.Slug == "":
+		return "unknown"
+	case p.UpdateAvailable:
+		return "update available"
+	case p.Latest == "":
+		return "no longer listed"
+	default:
+		return "up to date"
+	}
+}
+
+// renderScanReport prints the identified plugins and the unknown jars as separate tables.
+func renderScanReport(w io.Writer, report *scan.Report) error {
+	// The table merges the confidence into the match column and leaves out checksums
+	pluginColumns := scanColumns[:len(scanColumns)-2]
+	err := output.Render(w, output.Options{Format: output.FormatTable}, output.View[scan.Plugin]{
+		Items:   report.Plugins,
+		Columns: pluginColumns,
+		Footer: fmt.Sprintf("%d plugins identified, %d with updates available",
+			len(report.Plugins), report.Updates()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(report.Unknown) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "\nUnknown jars (%d):\n", len(report.Unknown))
+
+	return output.Render(w, output.Options{Format: output.FormatTable}, output.View[scan.Jar]{
+		Items: report.Unknown,
+		Columns: []output.Column[scan.Jar]{
+			{Name: "file", Header: "File", Value: func(j scan.Jar) any { return j.File }},
+			{Name: "sha256", Header: "SHA-256", Value: func(j scan.Jar) any { return j.SHA256 }},
+		},
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+
+	// Scan command flags
+	addScanFlags(scanCmd)
+}
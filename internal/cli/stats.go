@@ -1,19 +1,55 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/cockroachdb/errors"
-	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/lexfrei/go-hangar/internal/chart"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// statsRow is a single row of an aggregated statistics report.
+type statsRow struct {
+	Period              string   `json:"period"`
+	Downloads           int64    `json:"downloads"`
+	Views               int64    `json:"views"`
+	DownloadsGrowth     *float64 `json:"downloadsGrowth,omitempty"`
+	ViewsGrowth         *float64 `json:"viewsGrowth,omitempty"`
+	RollingDownloads    *int64   `json:"rollingDownloads,omitempty"`
+	RollingViews        *int64   `json:"rollingViews,omitempty"`
+	RollingAvgDownloads *float64 `json:"rollingAvgDownloads,omitempty"`
+	RollingAvgViews     *float64 `json:"rollingAvgViews,omitempty"`
+	CumulativeDownloads *int64   `json:"cumulativeDownloads,omitempty"`
+	CumulativeViews     *int64   `json:"cumulativeViews,omitempty"`
+}
+
+// statsReportOptions controls how daily statistics are aggregated.
+type statsReportOptions struct {
+	groupBy    hangar.StatsPeriod
+	rolling    int
+	cumulative bool
+	chart      chart.Kind
+}
+
+// aggregated reports whether any aggregation beyond plain daily rows was requested.
+func (o statsReportOptions) aggregated() bool {
+	return o.groupBy != hangar.PeriodDay || o.rolling > 0 || o.cumulative
+}
+
 var projectStatsCmd = &cobra.Command{
 	Use:   "stats <slug>",
 	Short: "Get project statistics",
-	Long:  "Retrieve daily statistics for a project, optionally filtered by date range.",
-	Args:  cobra.ExactArgs(1),
+	Long: `Retrieve daily statistics for a project, optionally filtered by date range.
+
+Statistics can be grouped by ISO week, month or year (--group-by), extended with
+rolling window sums and averages (--rolling) and running totals (--cumulative),
+or drawn as a terminal chart (--chart, --chart=bar, --chart=spark).`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSlugs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		slug := args[0]
@@ -21,47 +57,31 @@ var projectStatsCmd = &cobra.Command{
 		fromDate, _ := cmd.Flags().GetString("from")
 		toDate, _ := cmd.Flags().GetString("to")
 
+		opts, err := statsReportOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
 		client := createClient()
 		stats, err := client.GetProjectStats(ctx, slug, fromDate, toDate)
 		if err != nil {
 			return errors.Wrap(err, "failed to get project stats")
 		}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(stats); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Date", "Downloads", "Views"})
-			for date, dailyStats := range stats {
-				t.AppendRow(table.Row{
-					date,
-					dailyStats.Downloads,
-					dailyStats.Views,
-				})
-			}
-			t.Render()
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nTotal days: %d\n", len(stats))
-		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
-		}
-
-		return nil
+		return renderStats(cmd, stats, opts)
 	},
 }
 
 var versionStatsCmd = &cobra.Command{
 	Use:   "stats <slug> <version>",
 	Short: "Get version statistics",
-	Long:  "Retrieve daily statistics for a specific version, optionally filtered by date range.",
-	Args:  cobra.ExactArgs(2),
+	Long: `Retrieve daily statistics for a specific version, optionally filtered by date range.
+
+Statistics can be grouped by ISO week, month or year (--group-by), extended with
+rolling window sums and averages (--rolling) and running totals (--cumulative),
+or drawn as a terminal chart (--chart, --chart=bar, --chart=spark).`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: positional(completeSlugs, completeVersions),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		slug := args[0]
@@ -70,40 +90,302 @@ var versionStatsCmd = &cobra.Command{
 		fromDate, _ := cmd.Flags().GetString("from")
 		toDate, _ := cmd.Flags().GetString("to")
 
+		opts, err := statsReportOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
 		client := createClient()
 		stats, err := client.GetVersionStats(ctx, slug, version, fromDate, toDate)
 		if err != nil {
 			return errors.Wrap(err, "failed to get version stats")
 		}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(stats); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Date", "Downloads", "Views"})
-			for date, dailyStats := range stats {
-				t.AppendRow(table.Row{
-					date,
-					dailyStats.Downloads,
-					dailyStats.Views,
-				})
-			}
-			t.Render()
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nTotal days: %d\n", len(stats))
+		return renderStats(cmd, stats, opts)
+	},
+}
+
+// statsReportOptionsFromFlags reads and validates the aggregation flags.
+func statsReportOptionsFromFlags(cmd *cobra.Command) (statsReportOptions, error) {
+	groupBy, _ := cmd.Flags().GetString("group-by")
+	rolling, _ := cmd.Flags().GetInt("rolling")
+	cumulative, _ := cmd.Flags().GetBool("cumulative")
+
+	period, err := hangar.ParseStatsPeriod(groupBy)
+	if err != nil {
+		return statsReportOptions{}, errors.Wrap(err, "invalid --group-by value")
+	}
+
+	if rolling < 0 {
+		return statsReportOptions{}, errors.Newf("--rolling must not be negative, got %d", rolling)
+	}
+
+	opts := statsReportOptions{
+		groupBy:    period,
+		rolling:    rolling,
+		cumulative: cumulative,
+	}
+
+	if opts.chart, err = chartKindFromFlags(cmd); err != nil {
+		return statsReportOptions{}, err
+	}
+
+	return opts, nil
+}
+
+// chartKindFromFlags reads the --chart flag registered by addChartFlag. It returns an empty
+// kind when no chart was requested.
+func chartKindFromFlags(cmd *cobra.Command) (chart.Kind, error) {
+	chartType, _ := cmd.Flags().GetString("chart")
+	if chartType == "" {
+		return "", nil
+	}
+
+	kind, err := chart.ParseKind(chartType)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid --chart value")
+	}
+
+	return kind, nil
+}
+
+// addChartFlag registers the --chart flag shared by stats commands. A bare --chart draws
+// a line chart; other kinds need the --chart=KIND form because the value is optional.
+func addChartFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().String("chart", "", usage+" (--chart for a line chart, --chart=bar, --chart=spark)")
+	cmd.Flags().Lookup("chart").NoOptDefVal = string(chart.KindLine)
+	_ = cmd.RegisterFlagCompletionFunc("chart", cobra.FixedCompletions(
+		[]string{string(chart.KindLine), string(chart.KindBar), string(chart.KindSpark)}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// buildStatsRows aggregates daily statistics into report rows.
+func buildStatsRows(stats map[string]hangar.DailyStats, opts statsReportOptions) ([]statsRow, error) {
+	series, err := hangar.NewStatsSeries(stats)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse stats")
+	}
+
+	// Missing days count as zero so that rolling windows span calendar days
+	daily := series.Fill()
+	series = daily.GroupBy(opts.groupBy)
+
+	rows := make([]statsRow, len(series))
+	for i, point := range series {
+		rows[i] = statsRow{
+			Period:    point.Period,
+			Downloads: point.Downloads,
+			Views:     point.Views,
+		}
+	}
+
+	if opts.groupBy != hangar.PeriodDay {
+		for i, growth := range series.Growth() {
+			rows[i].DownloadsGrowth = growth.Downloads
+			rows[i].ViewsGrowth = growth.Views
+		}
+	}
+
+	if opts.rolling > 0 {
+		// The window always spans days; grouped rows show the window ending on their last day
+		rolling, err := daily.Rolling(opts.rolling)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to compute rolling stats")
+		}
+
+		lastDay := make(map[string]int, len(series))
+		for i, day := range daily {
+			lastDay[opts.groupBy.Label(day.Start)] = i
+		}
+		for i := range rows {
+			window := &rolling[lastDay[rows[i].Period]]
+			rows[i].RollingDownloads = &window.Downloads
+			rows[i].RollingViews = &window.Views
+			rows[i].RollingAvgDownloads = &window.AvgDownloads
+			rows[i].RollingAvgViews = &window.AvgViews
+		}
+	}
+
+	if opts.cumulative {
+		cumulative := series.Cumulative()
+		for i := range cumulative {
+			rows[i].CumulativeDownloads = &cumulative[i].Downloads
+			rows[i].CumulativeViews = &cumulative[i].Views
+		}
+	}
+
+	return rows, nil
+}
+
+// renderStats prints daily statistics according to the output format and aggregation options.
+func renderStats(cmd *cobra.Command, stats map[string]hangar.DailyStats, opts statsReportOptions) error {
+	rows, err := buildStatsRows(stats, opts)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat := viper.GetString("output"); opts.chart != "" && outputFormat != "table" {
+		return errors.Newf("--chart requires table output, got %s", outputFormat)
+	}
+
+	totals := hangar.DailyStats{}
+	for _, row := range rows {
+		totals.Downloads += row.Downloads
+		totals.Views += row.Views
+	}
+
+	view := output.View[statsRow]{
+		// Keep the raw API response unless an aggregation was requested
+		Data:    stats,
+		Items:   rows,
+		Columns: statsColumns(opts),
+		Footer: fmt.Sprintf("Total days: %d, downloads: %d, views: %d",
+			len(stats), totals.Downloads, totals.Views),
+	}
+	if opts.aggregated() {
+		view.Data = rows
+	}
+	if opts.chart != "" {
+		view.Text = func(w io.Writer) error {
+			return renderStatsChart(w, rows, opts)
+		}
+	}
+
+	return render(cmd, view)
+}
+
+// statsColumns returns the report columns enabled by the aggregation options.
+func statsColumns(opts statsReportOptions) []output.Column[statsRow] {
+	periodHeader := "Date"
+	if opts.groupBy != hangar.PeriodDay {
+		periodHeader = "Period"
+	}
+
+	columns := []output.Column[statsRow]{
+		{Name: "period", Header: periodHeader, Value: func(r statsRow) any { return r.Period }},
+		{Name: "downloads", Header: "Downloads", Value: func(r statsRow) any { return r.Downloads }},
+		{Name: "views", Header: "Views", Value: func(r statsRow) any { return r.Views }},
+	}
+
+	if opts.groupBy != hangar.PeriodDay {
+		columns = append(columns,
+			output.Column[statsRow]{
+				Name:   "downloadsGrowth",
+				Header: "Downloads Δ",
+				Value:  func(r statsRow) any { return r.DownloadsGrowth },
+				Format: func(r statsRow) string { return formatGrowth(r.DownloadsGrowth) },
+			},
+			output.Column[statsRow]{
+				Name:   "viewsGrowth",
+				Header: "Views Δ",
+				Value:  func(r statsRow) any { return r.ViewsGrowth },
+				Format: func(r statsRow) string { return formatGrowth(r.ViewsGrowth) },
+			})
+	}
+
+	if opts.rolling > 0 {
+		window := fmt.Sprintf("%dd", opts.rolling)
+		columns = append(columns,
+			output.Column[statsRow]{
+				Name:   "rollingDownloads",
+				Header: "Downloads (" + window + ")",
+				Value:  func(r statsRow) any { return r.RollingDownloads },
+			},
+			output.Column[statsRow]{
+				Name:   "rollingAvgDownloads",
+				Header: "Avg Downloads (" + window + ")",
+				Value:  func(r statsRow) any { return r.RollingAvgDownloads },
+				Format: func(r statsRow) string { return fmt.Sprintf("%.1f", *r.RollingAvgDownloads) },
+			},
+			output.Column[statsRow]{
+				Name:   "rollingViews",
+				Header: "Views (" + window + ")",
+				Value:  func(r statsRow) any { return r.RollingViews },
+			},
+			output.Column[statsRow]{
+				Name:   "rollingAvgViews",
+				Header: "Avg Views (" + window + ")",
+				Value:  func(r statsRow) any { return r.RollingAvgViews },
+				Format: func(r statsRow) string { return fmt.Sprintf("%.1f", *r.RollingAvgViews) },
+			})
+	}
+
+	if opts.cumulative {
+		columns = append(columns,
+			output.Column[statsRow]{
+				Name:   "cumulativeDownloads",
+				Header: "Total Downloads",
+				Value:  func(r statsRow) any { return r.CumulativeDownloads },
+			},
+			output.Column[statsRow]{
+				Name:   "cumulativeViews",
+				Header: "Total Views",
+				Value:  func(r statsRow) any { return r.CumulativeViews },
+			})
+	}
+
+	return columns
+}
+
+// renderStatsChart draws downloads and views of the report rows as terminal charts.
+// Rolling averages replace the raw values when --rolling is set, cumulative totals when --cumulative is set.
+func renderStatsChart(out io.Writer, rows []statsRow, opts statsReportOptions) error {
+	labels := make([]string, len(rows))
+	downloads := make([]float64, len(rows))
+	views := make([]float64, len(rows))
+
+	for i, row := range rows {
+		labels[i] = row.Period
+		switch {
+		case opts.cumulative:
+			downloads[i], views[i] = float64(*row.CumulativeDownloads), float64(*row.CumulativeViews)
+		case opts.rolling > 0:
+			downloads[i], views[i] = *row.RollingAvgDownloads, *row.RollingAvgViews
 		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
+			downloads[i], views[i] = float64(row.Downloads), float64(row.Views)
 		}
+	}
 
-		return nil
-	},
+	chartOpts := chart.Terminal(out)
+
+	// Views usually dwarf downloads, so each metric gets its own scale
+	for i, series := range []chart.Series{
+		{Name: "Downloads", Values: downloads},
+		{Name: "Views", Values: views},
+	} {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+
+		if opts.chart == chart.KindLine {
+			_, _ = fmt.Fprintln(out, series.Name)
+		}
+
+		if err := chart.Render(out, opts.chart, labels, []chart.Series{series}, chartOpts); err != nil {
+			return errors.Wrap(err, "failed to render chart")
+		}
+	}
+
+	return nil
+}
+
+// formatGrowth formats a growth percentage, using "-" when there is nothing to compare with.
+func formatGrowth(growth *float64) string {
+	if growth == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%+.1f%%", *growth)
+}
+
+// addStatsFlags registers the date range and aggregation flags shared by stats commands.
+func addStatsFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
+	cmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+	cmd.Flags().String("group-by", "day", "Aggregation period (day, week, month, year)")
+	_ = cmd.RegisterFlagCompletionFunc("group-by", completeGroupBy)
+	cmd.Flags().Int("rolling", 0, "Add rolling sums and daily averages over the last N days (ending on the last day of each period when grouped)")
+	cmd.Flags().Bool("cumulative", false, "Add cumulative totals")
+	addChartFlag(cmd, "Render a chart instead of a table")
 }
 
 func init() {
@@ -111,10 +393,8 @@ func init() {
 	versionCmd.AddCommand(versionStatsCmd)
 
 	// Project stats flags
-	projectStatsCmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
-	projectStatsCmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+	addStatsFlags(projectStatsCmd)
 
 	// Version stats flags
-	versionStatsCmd.Flags().String("from", "", "Start date (YYYY-MM-DD)")
-	versionStatsCmd.Flags().String("to", "", "End date (YYYY-MM-DD)")
+	addStatsFlags(versionStatsCmd)
 }
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStatsRows_RollingDaysWhenGrouped(t *testing.T) {
+	t.Parallel()
+
+	// Monday 2024-01-01 to Wednesday 2024-01-10: ISO weeks 1 and 2
+	stats := map[string]hangar.DailyStats{}
+	for day, downloads := range map[string]int64{
+		"2024-01-01": 1, "2024-01-02": 2, "2024-01-03": 3, "2024-01-04": 4, "2024-01-05": 5,
+		"2024-01-06": 6, "2024-01-07": 7, "2024-01-08": 8, "2024-01-09": 9, "2024-01-10": 10,
+	} {
+		stats[day] = hangar.DailyStats{Downloads: downloads}
+	}
+
+	rows, err := buildStatsRows(stats, statsReportOptions{groupBy: hangar.PeriodWeek, rolling: 3})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, "2024-W01", rows[0].Period)
+	assert.Equal(t, int64(28), rows[0].Downloads)
+	assert.Equal(t, int64(5+6+7), *rows[0].RollingDownloads, "window of the last 3 days of the week")
+	assert.InDelta(t, 6.0, *rows[0].RollingAvgDownloads, 0.001)
+
+	assert.Equal(t, "2024-W02", rows[1].Period)
+	assert.Equal(t, int64(8+9+10), *rows[1].RollingDownloads)
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/lexfrei/go-hangar/internal/manifest"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Make the plugin directory match the lockfile",
+	Long: `Reconcile the plugin directory with the lockfile (hangar.lock): download missing
+plugins, replace jars whose checksum differs from the lock and remove every jar that is
+not in the lock. Files other than jars, such as plugin configuration folders, are left
+alone.
+
+Every download is verified against its locked SHA-256 checksum and renamed into place
+only afterwards. Undeclared jars are removed only once all downloads succeeded, so a
+failed sync leaves the installed plugins untouched. Pass --quarantine DIR to move
+undeclared jars to DIR instead of deleting them; DIR may be on another filesystem, and
+a jar quarantined earlier under the same name is kept by adding a timestamp to the new name.
+
+Syncing an up-to-date directory changes nothing, so the command can run as a container
+init step. --dry-run prints the plan without changing anything. With --exit-code the
+command exits with status 2 when it changed files (or would change them with --dry-run)
+and 0 when the directory already matched the lockfile.`,
+	Example: `  hangar sync --dir ./plugins
+  hangar sync --dry-run
+  hangar sync --quarantine ./plugins-removed --exit-code`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		lockPath, _ := cmd.Flags().GetString("lock")
+		manifestPath, _ := cmd.Flags().GetString("manifest")
+		dir, _ := cmd.Flags().GetString("dir")
+		quarantine, _ := cmd.Flags().GetString("quarantine")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		exitCode, _ := cmd.Flags().GetBool("exit-code")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+		lock, err := manifest.LoadLock(lockPath)
+		if err != nil {
+			return err
+		}
+		warnStaleLock(lock, manifestPath)
+
+		plan, err := manifest.PlanSync(lock, dir, quarantine)
+		if err != nil {
+			return err
+		}
+
+		changes := plan.Changes()
+		if !dryRun && changes > 0 {
+			if err := manifest.ApplySync(cmd.Context(), createClient(), lock, plan, concurrency); err != nil {
+				return err
+			}
+		}
+
+		err = render(cmd, output.View[manifest.SyncStep]{
+			Data:    plan,
+			Items:   plan.Steps,
+			Columns: syncColumns,
+			Footer:  syncFooter(plan, changes, dryRun),
+		})
+		if err != nil {
+			return err
+		}
+
+		if exitCode && changes > 0 {
+			return exitWith(cmd, exitChanged)
+		}
+
+		return nil
+	},
+}
+
+// syncColumns are the columns of the sync plan.
+var syncColumns = []output.Column[manifest.SyncStep]{
+	{Name: "action", Header: "Action", Value: func(s manifest.SyncStep) any { return s.Action }},
+	{Name: "file", Header: "File", Value: func(s manifest.SyncStep) any { return s.File }},
+	{Name: "slug", Header: "Slug", Value: func(s manifest.SyncStep) any { return s.Slug }},
+	{Name: "version", Header: "Version", Value: func(s manifest.SyncStep) any { return s.Version }},
+}
+
+// syncFooter summarizes the sync.
+func syncFooter(plan *manifest.SyncPlan, changes int, dryRun bool) string {
+	switch {
+	case changes == 0:
+		return plan.Dir + " already matches the lockfile"
+	case dryRun:
+		return fmt.Sprintf("Dry run: %d changes needed in %s", changes, plan.Dir)
+	default:
+		return fmt.Sprintf("Applied %d changes to %s", changes, plan.Dir)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	// Sync command flags
+	syncCmd.Flags().String("lock", manifest.DefaultLockFile, "Path of the lockfile")
+	syncCmd.Flags().String("manifest", manifest.DefaultManifestFile, "Path of the plugin manifest, used to detect an outdated lockfile")
+	syncCmd.Flags().String("dir", "plugins", "Plugin directory to sync")
+	syncCmd.Flags().String("quarantine", "", "Move jars not in the lockfile to this directory instead of deleting them")
+	syncCmd.Flags().Bool("dry-run", false, "Show the plan without changing anything")
+	syncCmd.Flags().Bool("exit-code", false, "Exit with status 2 when files were changed (or would be with --dry-run)")
+	syncCmd.Flags().Int("concurrency", manifest.DefaultConcurrency, "Number of plugins downloaded at the same time")
+	_ = syncCmd.RegisterFlagCompletionFunc("dir", completeDir)
+	_ = syncCmd.RegisterFlagCompletionFunc("quarantine", completeDir)
+}
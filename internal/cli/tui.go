@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"github.com/lexfrei/go-hangar/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse Hangar interactively",
+	Long: `Browse projects in an interactive terminal UI.
+
+Search projects (/), filter by category (c, C), page through results ([, ]) and open
+a project (enter) to see its versions, rendered README and statistics of the last
+30 days. On the versions tab, d downloads the selected version for the platform
+selected with p into --dir.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		query, _ := cmd.Flags().GetString("query")
+		category, _ := cmd.Flags().GetString("category")
+		platform, _ := cmd.Flags().GetString("platform")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		return tui.Run(cmd.Context(), createClient(), tui.Options{
+			Query:       query,
+			Category:    category,
+			Platform:    platform,
+			DownloadDir: dir,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+
+	// TUI command flags
+	tuiCmd.Flags().String("query", "", "Initial search query")
+	tuiCmd.Flags().String("category", "", "Initial category filter")
+	tuiCmd.Flags().String("platform", "PAPER", "Platform to download for (PAPER, WATERFALL, VELOCITY)")
+	tuiCmd.Flags().String("dir", ".", "Directory to save downloads to")
+	_ = tuiCmd.RegisterFlagCompletionFunc("category", completeCategories)
+	_ = tuiCmd.RegisterFlagCompletionFunc("platform", completePlatforms)
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/manifest"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/internal/upgrade"
+	"github.com/spf13/cobra"
+)
+
+var upgradePlanCmd = &cobra.Command{
+	Use:   "upgrade-plan --target <minecraft-version> [slug]...",
+	Short: "Check which plugins block a Minecraft upgrade",
+	Long: `Check whether the plugins of a server have versions supporting a target Minecraft
+version before upgrading the server.
+
+Plugins are given as project slugs, read from a lockfile with --lock, or both. For each
+plugin the versions supporting the target on its platform are looked up, and the
+earliest and latest compatible versions are reported. Plugins without a compatible
+version are reported as blocked, along with the newest Minecraft version any of their
+versions supports.
+
+With a lockfile the locked version is taken into account: "ready" means it already
+supports the target, "update" means a newer version is needed. Plugins given as slugs
+are reported as "compatible" or "blocked".
+
+The command exits with status 2 when plugins block the upgrade, so it can gate CI
+pipelines.`,
+	Example: `  hangar upgrade-plan --target 1.21.1 --lock hangar.lock
+  hangar upgrade-plan --target 1.21.1 CoolPlugin OtherPlugin
+  hangar upgrade-plan --target 1.21.1 --lock hangar.lock --channels Release -o json`,
+	ValidArgsFunction: completeSlugs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, _ := cmd.Flags().GetString("target")
+		lockPath, _ := cmd.Flags().GetString("lock")
+		platform, _ := cmd.Flags().GetString("platform")
+		channels, _ := cmd.Flags().GetStringSlice("channels")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+		if target == "" {
+			return errors.New("--target is required")
+		}
+
+		var plugins []upgrade.Plugin
+		if lockPath != "" {
+			lock, err := manifest.LoadLock(lockPath)
+			if err != nil {
+				return err
+			}
+			for _, plugin := range lock.Plugins {
+				plugins = append(plugins, upgrade.Plugin{Slug: plugin.Slug, Platform: plugin.Platform, Current: plugin.Version})
+			}
+		}
+		for _, slug := range args {
+			plugins = append(plugins, upgrade.Plugin{Slug: slug, Platform: platform})
+		}
+		if len(plugins) == 0 {
+			return errors.New("no plugins to check: pass project slugs or --lock")
+		}
+
+		results, err := upgrade.Plan(cmd.Context(), createClient(), plugins, upgrade.Options{
+			Target:      target,
+			Channels:    channels,
+			Concurrency: concurrency,
+		})
+		if err != nil {
+			return err
+		}
+
+		blocked := upgrade.Blocked(results)
+		footer := fmt.Sprintf("All %d plugins can run on Minecraft %s", len(results), target)
+		if blocked > 0 {
+			footer = fmt.Sprintf("%d of %d plugins block the upgrade to Minecraft %s", blocked, len(results), target)
+		}
+
+		err = render(cmd, output.View[upgrade.Result]{
+			Items:   results,
+			Columns: upgradePlanColumns,
+			Footer:  footer,
+		})
+		if err != nil {
+			return err
+		}
+
+		if blocked > 0 {
+			return exitWith(cmd, exitBlocked)
+		}
+
+		return nil
+	},
+}
+
+// upgradePlanColumns are the columns of the upgrade plan.
+var upgradePlanColumns = []output.Column[upgrade.Result]{
+	{Name: "slug", Header: "Slug", Value: func(r upgrade.Result) any { return r.Slug }},
+	{Name: "platform", Header: "Platform", Value: func(r upgrade.Result) any { return r.Platform }},
+	{Name: "current", Header: "Current", Value: func(r upgrade.Result) any { return r.Current }},
+	{
+		Name:   "status",
+		Header: "Status",
+		Value:  func(r upgrade.Result) any { return r.Status },
+		Format: func(r upgrade.Result) string {
+			if r.Status == upgrade.StatusBlocked {
+				return "no compatible release"
+			}
+			return r.Status
+		},
+	},
+	{Name: "earliest", Header: "Earliest", Value: func(r upgrade.Result) any { return r.Earliest }},
+	{Name: "latest", Header: "Latest", Value: func(r upgrade.Result) any { return r.Latest }},
+	{
+		Name:   "lastSupported",
+		Header: "Last Supported",
+		Value:  func(r upgrade.Result) any { return r.LastSupported },
+		Format: func(r upgrade.Result) string {
+			if r.Status == upgrade.StatusBlocked && r.LastSupported == "" {
+				return "-"
+			}
+			return r.LastSupported
+		},
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(upgradePlanCmd)
+
+	// Upgrade plan command flags
+	upgradePlanCmd.Flags().String("target", "", "Minecraft version to upgrade to (e.g., 1.21.1)")
+	upgradePlanCmd.Flags().String("lock", "", "Check the plugins of a lockfile, e.g. hangar.lock")
+	upgradePlanCmd.Flags().String("platform", manifest.DefaultPlatform, "Server platform of plugins given as slugs (PAPER, WATERFALL, VELOCITY)")
+	upgradePlanCmd.Flags().StringSlice("channels", nil, "Only consider upgrade targets in these release channels, e.g. Release (default: all)")
+	upgradePlanCmd.Flags().Int("concurrency", upgrade.DefaultConcurrency, "Number of plugins checked at the same time")
+	_ = upgradePlanCmd.RegisterFlagCompletionFunc("platform", completePlatforms)
+	_ = upgradePlanCmd.RegisterFlagCompletionFunc("channels", completeChannelList)
+}
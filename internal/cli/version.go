@@ -1,15 +1,34 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/cockroachdb/errors"
-	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
 	"github.com/spf13/cobra"
 )
 
+// downloadURLResult is the resolved download URL of a version.
+type downloadURLResult struct {
+	Owner       string `json:"owner"`
+	Slug        string `json:"slug"`
+	Version     string `json:"version"`
+	Platform    string `json:"platform"`
+	DownloadURL string `json:"downloadUrl"`
+}
+
+// downloadURLColumns are the fields of a resolved download URL.
+var downloadURLColumns = []output.Column[downloadURLResult]{
+	{Name: "owner", Header: "Owner", Value: func(r downloadURLResult) any { return r.Owner }},
+	{Name: "slug", Header: "Slug", Value: func(r downloadURLResult) any { return r.Slug }},
+	{Name: "version", Header: "Version", Value: func(r downloadURLResult) any { return r.Version }},
+	{Name: "platform", Header: "Platform", Value: func(r downloadURLResult) any { return r.Platform }},
+	{Name: "downloadUrl", Header: "Download URL", Value: func(r downloadURLResult) any { return r.DownloadURL }},
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Commands for working with versions",
@@ -17,10 +36,11 @@ var versionCmd = &cobra.Command{
 }
 
 var versionDownloadURLCmd = &cobra.Command{
-	Use:   "download-url <slug> <version>",
-	Short: "Get download URL for a specific version",
-	Long:  "Retrieve the download URL for a specific version of a project.",
-	Args:  cobra.ExactArgs(2),
+	Use:               "download-url <slug> <version>",
+	Short:             "Get download URL for a specific version",
+	Long:              "Retrieve the download URL for a specific version of a project.",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: positional(completeSlugs, completeVersions),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		slug := args[0]
@@ -41,28 +61,24 @@ var versionDownloadURLCmd = &cobra.Command{
 			return errors.Wrap(err, "failed to get download URL")
 		}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			result := map[string]string{
-				"owner":       project.Namespace.Owner,
-				"slug":        slug,
-				"version":     versionName,
-				"platform":    platform,
-				"downloadUrl": downloadURL,
-			}
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(result); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		default:
-			// For table and other formats, just print the URL
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), downloadURL)
+		result := downloadURLResult{
+			Owner:       project.Namespace.Owner,
+			Slug:        slug,
+			Version:     versionName,
+			Platform:    platform,
+			DownloadURL: downloadURL,
 		}
 
-		return nil
+		return render(cmd, output.View[downloadURLResult]{
+			Data:    result,
+			Items:   []downloadURLResult{result},
+			Columns: downloadURLColumns,
+			// For table output, just print the URL
+			Text: func(w io.Writer) error {
+				_, err := fmt.Fprintln(w, downloadURL)
+				return err
+			},
+		})
 	},
 }
 
@@ -84,37 +100,13 @@ var versionGetByIDCmd = &cobra.Command{
 			return errors.Wrap(err, "failed to get version")
 		}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(version); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Field", "Value"})
-			t.AppendRows([]table.Row{
-				{"ID", version.ID},
-				{"Name", version.Name},
-				{"Author", version.Author},
-				{"Created", version.CreatedAt.Format("2006-01-02 15:04:05")},
-				{"Visibility", version.Visibility},
-				{"Review State", version.ReviewState},
-				{"Downloads", version.Stats.TotalDownloads},
-			})
-			t.Render()
-			if version.Description != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nDescription:\n%s\n", version.Description)
-			}
-		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.Version]{
+			Data:    version,
+			Items:   []hangar.Version{*version},
+			Columns: versionDetailColumns,
+			Detail:  true,
+			Footer:  versionDescription(version),
+		})
 	},
 }
 
@@ -133,45 +125,22 @@ var versionFindByHashCmd = &cobra.Command{
 			return errors.Wrap(err, "failed to find version by hash")
 		}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(version); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Field", "Value"})
-			t.AppendRows([]table.Row{
-				{"ID", version.ID},
-				{"Name", version.Name},
-				{"Author", version.Author},
-				{"Created", version.CreatedAt.Format("2006-01-02 15:04:05")},
-				{"Visibility", version.Visibility},
-				{"Review State", version.ReviewState},
-				{"Downloads", version.Stats.TotalDownloads},
-			})
-			t.Render()
-			if version.Description != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nDescription:\n%s\n", version.Description)
-			}
-		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.Version]{
+			Data:    version,
+			Items:   []hangar.Version{*version},
+			Columns: versionDetailColumns,
+			Detail:  true,
+			Footer:  versionDescription(version),
+		})
 	},
 }
 
 var versionLatestCmd = &cobra.Command{
-	Use:   "latest <slug>",
-	Short: "Get latest version of a project",
-	Long:  "Retrieve the latest version of a project, optionally filtered by channel, platform, and Minecraft version.",
-	Args:  cobra.ExactArgs(1),
+	Use:               "latest <slug>",
+	Short:             "Get latest version of a project",
+	Long:              "Retrieve the latest version of a project, optionally filtered by channel, platform, and Minecraft version.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: positional(completeSlugs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		slug := args[0]
@@ -186,40 +155,25 @@ var versionLatestCmd = &cobra.Command{
 			return errors.Wrap(err, "failed to get latest version")
 		}
 
-		// Output based on format
-		outputFormat := cmd.Flag("output").Value.String()
-		switch outputFormat {
-		case "json":
-			encoder := json.NewEncoder(cmd.OutOrStdout())
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(version); err != nil {
-				return errors.Wrap(err, "failed to encode JSON")
-			}
-		case "table":
-			t := table.NewWriter()
-			t.SetOutputMirror(cmd.OutOrStdout())
-			t.AppendHeader(table.Row{"Field", "Value"})
-			t.AppendRows([]table.Row{
-				{"ID", version.ID},
-				{"Name", version.Name},
-				{"Author", version.Author},
-				{"Created", version.CreatedAt.Format("2006-01-02 15:04:05")},
-				{"Visibility", version.Visibility},
-				{"Review State", version.ReviewState},
-				{"Downloads", version.Stats.TotalDownloads},
-			})
-			t.Render()
-			if version.Description != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nDescription:\n%s\n", version.Description)
-			}
-		default:
-			return errors.Newf("unsupported output format: %s", outputFormat)
-		}
-
-		return nil
+		return render(cmd, output.View[hangar.Version]{
+			Data:    version,
+			Items:   []hangar.Version{*version},
+			Columns: versionDetailColumns,
+			Detail:  true,
+			Footer:  versionDescription(version),
+		})
 	},
 }
 
+// versionDescription returns the table footer with the version description, if any.
+func versionDescription(version *hangar.Version) string {
+	if version.Description == "" {
+		return ""
+	}
+
+	return "Description:\n" + version.Description
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
 	versionCmd.AddCommand(versionDownloadURLCmd)
@@ -229,9 +183,12 @@ func init() {
 
 	// download-url command flags
 	versionDownloadURLCmd.Flags().String("platform", "PAPER", "Platform to download for (PAPER, WATERFALL, VELOCITY)")
+	_ = versionDownloadURLCmd.RegisterFlagCompletionFunc("platform", completePlatforms)
 
 	// latest command flags
 	versionLatestCmd.Flags().String("channel", "", "Release channel (Release, Snapshot, etc.)")
 	versionLatestCmd.Flags().String("platform", "", "Platform filter (PAPER, WATERFALL, VELOCITY)")
 	versionLatestCmd.Flags().String("minecraft-version", "", "Minecraft version filter (e.g., 1.20.1)")
+	_ = versionLatestCmd.RegisterFlagCompletionFunc("channel", completeChannels)
+	_ = versionLatestCmd.RegisterFlagCompletionFunc("platform", completePlatforms)
 }
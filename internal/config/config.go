@@ -0,0 +1,456 @@
+// Package config reads and writes the hangar configuration file and resolves
+// effective settings from flags, environment variables, named profiles and defaults.
+//
+// The file keeps top-level defaults next to named profiles:
+//
+//	base_url: https://hangar.papermc.io/api/v1
+//	current_profile: prod
+//	profiles:
+//	  prod:
+//	    api_token: ...
+//	  staging:
+//	    base_url: https://hangar.staging.example/api/v1
+//	    api_token: ...
+package config
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// EnvPrefix prefixes the environment variables of settings, e.g. HANGAR_BASE_URL.
+	EnvPrefix = "HANGAR"
+	// ProfileEnv selects the profile, overriding current_profile of the file.
+	ProfileEnv = EnvPrefix + "_PROFILE"
+
+	// currentProfileKey is the file key of the profile selected with `hangar config use`.
+	currentProfileKey = "current_profile"
+	// profilesKey is the file key holding the named profiles.
+	profilesKey = "profiles"
+)
+
+// Key describes a setting that can be stored in the file and in profiles.
+type Key struct {
+	// Name is the key in the file, e.g. base_url.
+	Name string
+	// Flag is the command-line flag overriding the key.
+	Flag string
+	// Description is shown by `hangar config` help and validation errors.
+	Description string
+	// Secret masks the value when displayed.
+	Secret bool
+	// Redact hides credentials inside a value when displayed, if set.
+	Redact func(value string) string
+	// Validate checks a value, if set.
+	Validate func(value string) error
+}
+
+// Env returns the environment variable overriding the key.
+func (k Key) Env() string {
+	return EnvPrefix + "_" + strings.ToUpper(k.Name)
+}
+
+// Keys are the supported settings.
+var Keys = []Key{
+	{Name: "base_url", Flag: "base-url", Description: "Hangar API base URL", Validate: validateURL},
+	{Name: "api_token", Flag: "token", Description: "Hangar API token", Secret: true},
+	{Name: "timeout", Flag: "timeout", Description: "HTTP client timeout", Validate: validateDuration},
+	{Name: "output", Flag: "output", Description: "Default output format", Validate: validateOutput},
+	{Name: "proxy", Flag: "proxy", Description: "Proxy URL, overriding HTTP_PROXY and HTTPS_PROXY", Validate: validateProxy, Redact: hangar.RedactURL},
+	{Name: "ca_file", Flag: "ca-file", Description: "PEM bundle of additional trusted certificate authorities", Validate: validateFile},
+	{Name: "client_cert", Flag: "client-cert", Description: "PEM client certificate for mutual TLS", Validate: validateFile},
+	{Name: "client_key", Flag: "client-key", Description: "PEM client key for mutual TLS", Validate: validateFile},
+	{Name: "tls_min_version", Flag: "tls-min-version", Description: "Minimum TLS version", Validate: validateTLSVersion},
+	{Name: "max_idle_conns", Flag: "max-idle-conns", Description: "Maximum idle connections", Validate: validateCount},
+	{Name: "max_idle_conns_per_host", Flag: "max-idle-conns-per-host", Description: "Maximum idle connections per host", Validate: validateCount},
+	{Name: "max_conns_per_host", Flag: "max-conns-per-host", Description: "Maximum connections per host", Validate: validateCount},
+}
+
+// LookupKey returns the key with the given name.
+func LookupKey(name string) (Key, error) {
+	for _, key := range Keys {
+		if key.Name == name {
+			return key, nil
+		}
+	}
+
+	return Key{}, errors.Newf("unknown config key %q (expected %s)", name, KeyNames())
+}
+
+// KeyNames returns the supported key names as a comma-separated list.
+func KeyNames() string {
+	names := make([]string, len(Keys))
+	for i, key := range Keys {
+		names[i] = key.Name
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// DefaultPath returns $HOME/.config/hangar/config.yaml, or config.yml in the same
+// directory if only that file exists.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get home directory")
+	}
+
+	dir := filepath.Join(home, ".config", "hangar")
+	path := filepath.Join(dir, "config.yaml")
+	if legacy := filepath.Join(dir, "config.yml"); !fileExists(path) && fileExists(legacy) {
+		return legacy, nil
+	}
+
+	return path, nil
+}
+
+// fileExists reports whether path exists and is not a directory.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && !info.IsDir()
+}
+
+// File is a configuration file. Unknown keys are preserved when saving.
+type File struct {
+	path string
+	data map[string]any
+}
+
+// Load reads the file at path. A missing file yields an empty configuration.
+func Load(path string) (*File, error) {
+	file := &File{path: path, data: map[string]any{}}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read config file")
+	}
+
+	if err := yaml.Unmarshal(raw, &file.data); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse config file %s", path)
+	}
+	if file.data == nil {
+		file.data = map[string]any{}
+	}
+
+	return file, nil
+}
+
+// Path returns the location of the file.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Save writes the file, readable only by the current user since it may hold tokens.
+func (f *File) Save() error {
+	raw, err := yaml.Marshal(f.data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode config file")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return errors.Wrap(err, "failed to create config directory")
+	}
+
+	if err := os.WriteFile(f.path, raw, 0o600); err != nil {
+		return errors.Wrap(err, "failed to write config file")
+	}
+
+	return errors.Wrap(os.Chmod(f.path, 0o600), "failed to write config file")
+}
+
+// Profiles returns the profile names in alphabetical order.
+func (f *File) Profiles() []string {
+	profiles, _ := f.data[profilesKey].(map[string]any)
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// HasProfile reports whether the profile exists.
+func (f *File) HasProfile(name string) bool {
+	_, ok := f.profile(name)
+	return ok
+}
+
+// CurrentProfile returns the profile selected with `hangar config use`.
+func (f *File) CurrentProfile() string {
+	name, _ := f.data[currentProfileKey].(string)
+	return name
+}
+
+// Use selects a profile for later invocations.
+func (f *File) Use(name string) error {
+	if !f.HasProfile(name) {
+		return errors.Newf("profile %q does not exist (profiles: %s)", name, strings.Join(f.Profiles(), ", "))
+	}
+
+	f.data[currentProfileKey] = name
+
+	return nil
+}
+
+// ActiveProfile returns the selected profile: flag, then environment, then current_profile.
+// It returns an error if the selected profile does not exist.
+func (f *File) ActiveProfile(flag, env string) (string, error) {
+	name := f.CurrentProfile()
+	switch {
+	case flag != "":
+		name = flag
+	case env != "":
+		name = env
+	}
+
+	if name != "" && !f.HasProfile(name) {
+		return "", errors.Newf("profile %q does not exist in %s", name, f.path)
+	}
+
+	return name, nil
+}
+
+// Get returns a value of a profile, or a top-level value if profile is empty.
+func (f *File) Get(profile, key string) (string, bool) {
+	values := f.data
+	if profile != "" {
+		var ok bool
+		if values, ok = f.profile(profile); !ok {
+			return "", false
+		}
+	}
+
+	value, ok := values[key]
+	if !ok || value == nil {
+		return "", false
+	}
+
+	return stringValue(value), true
+}
+
+// Values returns the values of a profile, or the top-level values if profile is empty.
+func (f *File) Values(profile string) map[string]string {
+	values := make(map[string]string)
+	for _, key := range Keys {
+		if value, ok := f.Get(profile, key.Name); ok {
+			values[key.Name] = value
+		}
+	}
+
+	return values
+}
+
+// Set validates and stores a value in a profile, creating the profile if needed,
+// or at the top level if profile is empty.
+func (f *File) Set(profile, name, value string) error {
+	key, err := LookupKey(name)
+	if err != nil {
+		return err
+	}
+	if key.Validate != nil {
+		if err := key.Validate(value); err != nil {
+			return errors.Wrapf(err, "invalid %s", name)
+		}
+	}
+
+	if profile == "" {
+		f.data[name] = value
+		return nil
+	}
+
+	profiles, _ := f.data[profilesKey].(map[string]any)
+	if profiles == nil {
+		profiles = map[string]any{}
+		f.data[profilesKey] = profiles
+	}
+
+	values, _ := profiles[profile].(map[string]any)
+	if values == nil {
+		values = map[string]any{}
+		profiles[profile] = values
+	}
+	values[name] = value
+
+	return nil
+}
+
+// Validate returns the problems of the file: unknown keys, invalid values,
+// malformed profiles and a current_profile that does not exist.
+func (f *File) Validate() []error {
+	var problems []error
+
+	for _, name := range sortedKeys(f.data) {
+		switch name {
+		case currentProfileKey:
+			if current := f.CurrentProfile(); current != "" && !f.HasProfile(current) {
+				problems = append(problems, errors.Newf("current_profile %q does not exist", current))
+			}
+		case profilesKey:
+			profiles, ok := f.data[profilesKey].(map[string]any)
+			if !ok {
+				problems = append(problems, errors.New("profiles must be a mapping of profile names to settings"))
+				continue
+			}
+			for _, profile := range sortedKeys(profiles) {
+				values, ok := profiles[profile].(map[string]any)
+				if !ok {
+					problems = append(problems, errors.Newf("profile %q must be a mapping of settings", profile))
+					continue
+				}
+				for _, problem := range validateValues(values) {
+					problems = append(problems, errors.Wrapf(problem, "profile %q", profile))
+				}
+			}
+		}
+	}
+
+	top := make(map[string]any)
+	for name, value := range f.data {
+		if name != currentProfileKey && name != profilesKey {
+			top[name] = value
+		}
+	}
+
+	return append(problems, validateValues(top)...)
+}
+
+// profile returns the values of a profile.
+func (f *File) profile(name string) (map[string]any, bool) {
+	profiles, _ := f.data[profilesKey].(map[string]any)
+	values, ok := profiles[name].(map[string]any)
+
+	return values, ok
+}
+
+// validateValues checks that all keys are known and all values valid.
+func validateValues(values map[string]any) []error {
+	var problems []error
+
+	for _, name := range sortedKeys(values) {
+		key, err := LookupKey(name)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if key.Validate != nil {
+			if err := key.Validate(stringValue(values[name])); err != nil {
+				problems = append(problems, errors.Wrapf(err, "invalid %s", name))
+			}
+		}
+	}
+
+	return problems
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// stringValue formats a YAML scalar as a string.
+func stringValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	raw, err := yaml.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(raw))
+}
+
+func validateURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return errors.Wrap(err, "invalid URL")
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || parsed.Host == "" {
+		return errors.Newf("%q is not an http(s) URL", value)
+	}
+
+	return nil
+}
+
+func validateProxy(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return errors.Newf("%q is not a URL", hangar.RedactURL(value))
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return errors.Newf("%q is not an http, https or socks5 URL", hangar.RedactURL(value))
+	}
+	if parsed.Host == "" {
+		return errors.Newf("%q has no host", hangar.RedactURL(value))
+	}
+
+	return nil
+}
+
+func validateFile(value string) error {
+	info, err := os.Stat(value)
+	if err != nil {
+		return errors.Newf("cannot read %s", value)
+	}
+	if info.IsDir() {
+		return errors.Newf("%s is a directory", value)
+	}
+
+	return nil
+}
+
+func validateTLSVersion(value string) error {
+	_, err := hangar.ParseTLSVersion(value)
+	return err
+}
+
+func validateCount(value string) error {
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return errors.Newf("%q is not a non-negative number", value)
+	}
+
+	return nil
+}
+
+func validateOutput(value string) error {
+	_, err := output.ParseOptions(value, "")
+	return err
+}
+
+func validateDuration(value string) error {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return errors.Newf("%q is not a duration such as 30s or 1m", value)
+	}
+	if duration <= 0 {
+		return errors.Newf("%q must be positive", value)
+	}
+
+	return nil
+}
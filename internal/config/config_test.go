@@ -0,0 +1,234 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lexfrei/go-hangar/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `base_url: https://hangar.papermc.io/api/v1
+timeout: 10s
+current_profile: prod
+profiles:
+  prod:
+    api_token: prod-token
+  staging:
+    base_url: https://hangar.staging.example/api/v1
+    api_token: staging-token
+extra: kept
+`
+
+func load(t *testing.T, content string) *config.File {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	file, err := config.Load(path)
+	require.NoError(t, err)
+
+	return file
+}
+
+func TestLoad_Missing(t *testing.T) {
+	t.Parallel()
+
+	file, err := config.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+	assert.Empty(t, file.Profiles())
+	assert.Empty(t, file.CurrentProfile())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("profiles: [\n"), 0o600))
+
+	_, err := config.Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse config file")
+}
+
+func TestDefaultPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "hangar")
+
+	path, err := config.DefaultPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "config.yaml"), path)
+
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yml"), []byte(sample), 0o600))
+	path, err = config.DefaultPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "config.yml"), path, "an existing config.yml is used")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(sample), 0o600))
+	path, err = config.DefaultPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "config.yaml"), path, "config.yaml takes precedence")
+}
+
+func TestFile_Profiles(t *testing.T) {
+	t.Parallel()
+
+	file := load(t, sample)
+
+	assert.Equal(t, []string{"prod", "staging"}, file.Profiles())
+	assert.Equal(t, "prod", file.CurrentProfile())
+	assert.True(t, file.HasProfile("staging"))
+	assert.False(t, file.HasProfile("dev"))
+
+	value, ok := file.Get("staging", "base_url")
+	assert.True(t, ok)
+	assert.Equal(t, "https://hangar.staging.example/api/v1", value)
+
+	_, ok = file.Get("prod", "base_url")
+	assert.False(t, ok, "profiles do not inherit top-level values")
+
+	assert.Equal(t, map[string]string{
+		"base_url": "https://hangar.papermc.io/api/v1",
+		"timeout":  "10s",
+	}, file.Values(""))
+}
+
+func TestFile_ActiveProfile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		flag    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{name: "current profile", want: "prod"},
+		{name: "environment", env: "staging", want: "staging"},
+		{name: "flag over environment", flag: "prod", env: "staging", want: "prod"},
+		{name: "missing profile", flag: "dev", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := load(t, sample).ActiveProfile(tt.flag, tt.env)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFile_SetUseSave(t *testing.T) {
+	t.Parallel()
+
+	file := load(t, sample)
+
+	require.NoError(t, file.Set("dev", "base_url", "http://localhost:8080/api/v1"))
+	require.NoError(t, file.Set("", "output", "json"))
+	require.NoError(t, file.Use("dev"))
+	require.Error(t, file.Use("missing"))
+	require.Error(t, file.Set("dev", "timeout", "soon"))
+	require.Error(t, file.Set("dev", "color", "red"))
+	require.NoError(t, file.Save())
+
+	info, err := os.Stat(file.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	reloaded, err := config.Load(file.Path())
+	require.NoError(t, err)
+	assert.Equal(t, "dev", reloaded.CurrentProfile())
+	assert.Equal(t, []string{"dev", "prod", "staging"}, reloaded.Profiles())
+	assert.Equal(t, map[string]string{"base_url": "http://localhost:8080/api/v1"}, reloaded.Values("dev"))
+
+	value, _ := reloaded.Get("", "output")
+	assert.Equal(t, "json", value)
+	value, _ = reloaded.Get("", "extra")
+	assert.Equal(t, "kept", value, "unknown keys are preserved")
+}
+
+func TestFile_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, load(t, strings.TrimSuffix(sample, "extra: kept\n")).Validate())
+
+	problems := load(t, `current_profile: gone
+timeout: -1s
+color: red
+profiles:
+  broken: 1
+  dev:
+    base_url: ftp://example.com
+    output: xml
+  proxied:
+    proxy: ftp://proxy.example.com
+    ca_file: /nonexistent/ca.pem
+    tls_min_version: "1.4"
+    max_conns_per_host: -1
+`).Validate()
+
+	messages := make([]string, len(problems))
+	for i, problem := range problems {
+		messages[i] = problem.Error()
+	}
+
+	assert.Equal(t, []string{
+		`current_profile "gone" does not exist`,
+		`profile "broken" must be a mapping of settings`,
+		`profile "dev": invalid base_url: "ftp://example.com" is not an http(s) URL`,
+		`profile "dev": invalid output: unsupported output format: xml (expected table, json, yaml, csv, tsv, jsonl, markdown, go-template=TEMPLATE, jsonpath=EXPR)`,
+		`profile "proxied": invalid ca_file: cannot read /nonexistent/ca.pem`,
+		`profile "proxied": invalid max_conns_per_host: "-1" is not a non-negative number`,
+		`profile "proxied": invalid proxy: "ftp://proxy.example.com" is not an http, https or socks5 URL`,
+		`profile "proxied": invalid tls_min_version: unsupported TLS version "1.4" (expected 1.0, 1.1, 1.2 or 1.3)`,
+		`unknown config key "color" (expected ` + config.KeyNames() + `)`,
+		`invalid timeout: "-1s" must be positive`,
+	}, messages)
+}
+
+func TestFile_Resolve(t *testing.T) {
+	t.Parallel()
+
+	file := load(t, sample)
+	env := map[string]string{"HANGAR_TIMEOUT": "1m"}
+
+	settings := file.Resolve("prod", config.Overrides{
+		Flags: map[string]string{"output": "json"},
+		LookupEnv: func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		},
+		Defaults: map[string]string{"output": "table", "timeout": "30s"},
+	})
+
+	assert.Equal(t, []config.Setting{
+		{Key: "base_url", Value: "https://hangar.papermc.io/api/v1", Source: config.SourceFile},
+		{Key: "api_token", Value: "prod-token", Source: config.SourceProfile},
+		{Key: "timeout", Value: "1m", Source: config.SourceEnv},
+		{Key: "output", Value: "json", Source: config.SourceFlag},
+	}, settings[:4])
+
+	settings = file.Resolve("", config.Overrides{Defaults: map[string]string{"output": "table"}})
+	assert.Equal(t, config.Setting{Key: "output", Value: "table", Source: config.SourceDefault}, settings[3])
+	assert.Equal(t, config.Setting{Key: "api_token", Source: config.SourceDefault}, settings[1])
+}
+
+func TestMask(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, config.Mask(""))
+	assert.Equal(t, "********", config.Mask("short"))
+	assert.Equal(t, "********cdef", config.Mask("0123456789abcdef"))
+}
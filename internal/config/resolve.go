@@ -0,0 +1,85 @@
+package config
+
+import "strings"
+
+// Source is where an effective value comes from.
+type Source string
+
+const (
+	// SourceFlag is a command-line flag.
+	SourceFlag Source = "flag"
+	// SourceEnv is an environment variable.
+	SourceEnv Source = "env"
+	// SourceProfile is the active profile of the file.
+	SourceProfile Source = "profile"
+	// SourceFile is a top-level value of the file.
+	SourceFile Source = "file"
+	// SourceCredentials is the credential store written by `hangar auth login`.
+	SourceCredentials Source = "credentials"
+	// SourceDefault is the built-in default.
+	SourceDefault Source = "default"
+)
+
+// Setting is the effective value of a key.
+type Setting struct {
+	// Key is the key name.
+	Key string `json:"key"`
+	// Value is the effective value.
+	Value string `json:"value"`
+	// Source is where the value comes from.
+	Source Source `json:"source"`
+}
+
+// Overrides are the values taking precedence over the file, and the defaults below it.
+type Overrides struct {
+	// Flags are the values of flags set on the command line, by key name.
+	Flags map[string]string
+	// LookupEnv looks up environment variables, usually os.LookupEnv.
+	LookupEnv func(name string) (string, bool)
+	// Defaults are the built-in values, by key name.
+	Defaults map[string]string
+}
+
+// Resolve returns the effective value of every key in the order flag, environment variable,
+// profile, top-level file value and default.
+func (f *File) Resolve(profile string, overrides Overrides) []Setting {
+	settings := make([]Setting, len(Keys))
+
+	for i, key := range Keys {
+		setting := Setting{Key: key.Name, Value: overrides.Defaults[key.Name], Source: SourceDefault}
+
+		if value, ok := overrides.Flags[key.Name]; ok {
+			setting.Value, setting.Source = value, SourceFlag
+		} else if value, ok := lookupEnv(overrides, key.Env()); ok {
+			setting.Value, setting.Source = value, SourceEnv
+		} else if value, ok := f.Get(profile, key.Name); ok && profile != "" {
+			setting.Value, setting.Source = value, SourceProfile
+		} else if value, ok := f.Get("", key.Name); ok {
+			setting.Value, setting.Source = value, SourceFile
+		}
+
+		settings[i] = setting
+	}
+
+	return settings
+}
+
+func lookupEnv(overrides Overrides, name string) (string, bool) {
+	if overrides.LookupEnv == nil {
+		return "", false
+	}
+
+	return overrides.LookupEnv(name)
+}
+
+// Mask hides all but the last four characters of a secret.
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 8 {
+		return strings.Repeat("*", 8)
+	}
+
+	return strings.Repeat("*", 8) + value[len(value)-4:]
+}
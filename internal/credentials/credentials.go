@@ -0,0 +1,184 @@
+// Package credentials stores Hangar API keys per configuration profile,
+// separately from the configuration file.
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultProfile is the name under which credentials are stored when no profile is active.
+const DefaultProfile = "default"
+
+// ErrNotFound is returned when no credentials are stored for a profile.
+var ErrNotFound = errors.New("no credentials stored")
+
+// Credential is a stored API key.
+type Credential struct {
+	// APIKey is the Hangar API key.
+	APIKey string `yaml:"api_key"`
+	// User is the user the key belonged to when it was stored.
+	User string `yaml:"user,omitempty"`
+	// CreatedAt is when the key was stored.
+	CreatedAt time.Time `yaml:"created_at"`
+}
+
+// Store persists credentials per profile. Implementations may keep them in a file
+// or in an OS keyring.
+type Store interface {
+	// Name describes where credentials are kept, e.g. the file path.
+	Name() string
+	// Get returns the credential of a profile, or ErrNotFound.
+	Get(profile string) (Credential, error)
+	// Set stores the credential of a profile, replacing any previous one.
+	Set(profile string, credential Credential) error
+	// Delete removes the credential of a profile, or returns ErrNotFound.
+	Delete(profile string) error
+	// Profiles returns the profiles with stored credentials in alphabetical order.
+	Profiles() ([]string, error)
+}
+
+// FileStore keeps credentials in a YAML file readable only by the current user.
+type FileStore struct {
+	path string
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a store backed by the file at path, created on first write.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Name returns the file path.
+func (s *FileStore) Name() string {
+	return s.path
+}
+
+// Get returns the credential of a profile, or ErrNotFound.
+func (s *FileStore) Get(profile string) (Credential, error) {
+	credentials, err := s.load()
+	if err != nil {
+		return Credential{}, err
+	}
+
+	credential, ok := credentials[key(profile)]
+	if !ok || credential.APIKey == "" {
+		return Credential{}, errors.Wrapf(ErrNotFound, "profile %s", key(profile))
+	}
+
+	return credential, nil
+}
+
+// Set stores the credential of a profile, replacing any previous one.
+func (s *FileStore) Set(profile string, credential Credential) error {
+	credentials, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	credentials[key(profile)] = credential
+
+	return s.save(credentials)
+}
+
+// Delete removes the credential of a profile, or returns ErrNotFound.
+func (s *FileStore) Delete(profile string) error {
+	credentials, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := credentials[key(profile)]; !ok {
+		return errors.Wrapf(ErrNotFound, "profile %s", key(profile))
+	}
+	delete(credentials, key(profile))
+
+	return s.save(credentials)
+}
+
+// Profiles returns the profiles with stored credentials in alphabetical order.
+func (s *FileStore) Profiles() ([]string, error) {
+	credentials, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]string, 0, len(credentials))
+	for profile := range credentials {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+
+	return profiles, nil
+}
+
+// load reads the file. A missing file yields no credentials.
+func (s *FileStore) load() (map[string]Credential, error) {
+	credentials := make(map[string]Credential)
+
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return credentials, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read credentials file")
+	}
+
+	if err := yaml.Unmarshal(raw, &credentials); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse credentials file %s", s.path)
+	}
+	if credentials == nil {
+		credentials = make(map[string]Credential)
+	}
+
+	return credentials, nil
+}
+
+// save replaces the file atomically with mode 0600.
+func (s *FileStore) save(credentials map[string]Credential) error {
+	raw, err := yaml.Marshal(credentials)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode credentials")
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return errors.Wrap(err, "failed to create credentials directory")
+	}
+
+	tmp, err := os.CreateTemp(dir, ".credentials-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to write credentials file")
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	// CreateTemp already uses 0600, but be explicit since the file holds secrets
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "failed to write credentials file")
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "failed to write credentials file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write credentials file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), s.path), "failed to write credentials file")
+}
+
+// key returns the storage key of a profile.
+func key(profile string) string {
+	if profile == "" {
+		return DefaultProfile
+	}
+
+	return profile
+}
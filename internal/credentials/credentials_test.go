@@ -0,0 +1,63 @@
+package credentials_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/internal/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "hangar", "credentials.yaml")
+	store := credentials.NewFileStore(path)
+	assert.Equal(t, path, store.Name())
+
+	_, err := store.Get("")
+	require.ErrorIs(t, err, credentials.ErrNotFound)
+
+	created := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Set("", credentials.Credential{APIKey: "default-key", User: "alice", CreatedAt: created}))
+	require.NoError(t, store.Set("staging", credentials.Credential{APIKey: "staging-key"}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// A new store reads what the first one wrote
+	reopened := credentials.NewFileStore(path)
+
+	credential, err := reopened.Get(credentials.DefaultProfile)
+	require.NoError(t, err)
+	assert.Equal(t, credentials.Credential{APIKey: "default-key", User: "alice", CreatedAt: created}, credential)
+
+	profiles, err := reopened.Profiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "staging"}, profiles)
+
+	require.NoError(t, reopened.Delete("staging"))
+	require.ErrorIs(t, reopened.Delete("staging"), credentials.ErrNotFound)
+
+	_, err = reopened.Get("staging")
+	require.ErrorIs(t, err, credentials.ErrNotFound)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestFileStore_InvalidFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "credentials.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("default: [\n"), 0o600))
+
+	_, err := credentials.NewFileStore(path).Get("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse credentials file")
+}
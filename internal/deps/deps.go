@@ -0,0 +1,524 @@
+// Package deps resolves the transitive plugin dependencies of Hangar projects for a
+// platform and Minecraft version.
+package deps
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/manifest"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultConcurrency is the number of projects fetched at the same time by default.
+const DefaultConcurrency = 4
+
+// Kinds of dependency problems.
+const (
+	// ProblemMissing means the dependency is not on Hangar or has no compatible version.
+	ProblemMissing = "missing"
+	// ProblemExternal means the dependency is only available outside Hangar.
+	ProblemExternal = "external"
+	// ProblemConflict means the dependency cannot be satisfied as declared, e.g. because
+	// one name refers to different plugins or a pinned version is incompatible.
+	ProblemConflict = "conflict"
+)
+
+// externalPrefix starts the IDs of dependencies that are not Hangar projects.
+const externalPrefix = "external:"
+
+// Client is the part of the Hangar API used to resolve dependencies.
+type Client interface {
+	GetProject(ctx context.Context, slug string) (*hangar.Project, error)
+	ListAllVersions(ctx context.Context, owner, slug string) ([]hangar.Version, error)
+}
+
+// Options configure dependency resolution.
+type Options struct {
+	// Platform is the server platform (defaults to manifest.DefaultPlatform).
+	Platform string
+	// MinecraftVersion limits versions to those supporting the game version, if not empty.
+	MinecraftVersion string
+	// Channel is the release channel versions are picked from (defaults to manifest.DefaultChannel).
+	Channel string
+	// Concurrency is the number of projects fetched at the same time.
+	Concurrency int
+}
+
+// Node is a plugin in the dependency graph.
+type Node struct {
+	// ID identifies the node: the project slug for Hangar projects, "external:<name>" for
+	// dependencies outside Hangar and "project:<id>" for unknown project IDs.
+	ID string `json:"id"`
+	// Name is the project name, or the dependency name for nodes that are not Hangar projects.
+	Name string `json:"name"`
+	// Owner is the project owner.
+	Owner string `json:"owner,omitempty"`
+	// Version is the resolved version.
+	Version string `json:"version,omitempty"`
+	// Channel is the release channel of the resolved version.
+	Channel string `json:"channel,omitempty"`
+	// ExternalURL is the download page of external dependencies.
+	ExternalURL string `json:"externalUrl,omitempty"`
+	// Root reports whether the plugin was requested directly.
+	Root bool `json:"root"`
+	// Required reports whether the plugin is a root or reachable through required dependencies only.
+	Required bool `json:"required"`
+	// External reports whether the plugin is not published on Hangar.
+	External bool `json:"external"`
+	// Missing reports whether no compatible version could be resolved.
+	Missing bool `json:"missing"`
+
+	// Project is the Hangar project, if found.
+	Project *hangar.Project `json:"-"`
+	// Resolved is the resolved version, if any.
+	Resolved *hangar.Version `json:"-"`
+}
+
+// Edge is a dependency of one plugin on another.
+type Edge struct {
+	// From is the ID of the dependent plugin.
+	From string `json:"from"`
+	// To is the ID of the dependency.
+	To string `json:"to"`
+	// Required reports whether the dependency is mandatory.
+	Required bool `json:"required"`
+}
+
+// Problem is a dependency that cannot be installed from Hangar as declared.
+type Problem struct {
+	// Kind is one of the Problem constants.
+	Kind string `json:"kind"`
+	// Dependency is the name of the affected plugin.
+	Dependency string `json:"dependency"`
+	// RequiredBy lists the names of the plugins depending on it.
+	RequiredBy []string `json:"requiredBy,omitempty"`
+	// Required reports whether the plugin is needed by the requested plugins.
+	Required bool `json:"required"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// Graph is the transitive dependency graph of a set of plugins.
+type Graph struct {
+	// Platform is the platform the graph was resolved for.
+	Platform string `json:"platform"`
+	// MinecraftVersion is the game version the graph was resolved for, if any.
+	MinecraftVersion string `json:"minecraft,omitempty"`
+	// Nodes are the plugins in the order they were discovered, requested plugins first.
+	Nodes []*Node `json:"nodes"`
+	// Edges are the dependencies between the plugins.
+	Edges []Edge `json:"edges"`
+	// Problems are the dependencies that need attention.
+	Problems []Problem `json:"problems"`
+	// Cycles are the groups of plugins that depend on each other, directly or indirectly.
+	Cycles [][]string `json:"cycles"`
+}
+
+// Node returns the node with the ID, or nil.
+func (g *Graph) Node(id string) *Node {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// Unresolved returns the problems that prevent installing the required plugins: missing
+// required dependencies and conflicts. External dependencies are not included, as they
+// can be installed by hand.
+func (g *Graph) Unresolved() []Problem {
+	var unresolved []Problem
+	for _, problem := range g.Problems {
+		if problem.Kind == ProblemConflict || (problem.Kind == ProblemMissing && problem.Required) {
+			unresolved = append(unresolved, problem)
+		}
+	}
+
+	return unresolved
+}
+
+// Installable returns the resolved Hangar plugins that are required, or all resolved
+// Hangar plugins if optional is true.
+func (g *Graph) Installable(optional bool) []*Node {
+	var nodes []*Node
+	for _, node := range g.Nodes {
+		if node.Resolved != nil && !node.Missing && (node.Required || optional) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+// target is a project to fetch.
+type target struct {
+	// ref is the project slug or ID.
+	ref string
+	// name is the dependency name, used if the project does not exist.
+	name string
+	// pinned is the requested version of roots, if any.
+	pinned string
+	root   bool
+}
+
+// fetched is the result of fetching a target.
+type fetched struct {
+	project  *hangar.Project
+	version  *hangar.Version
+	notFound bool
+	// problem explains why version is nil or cannot be used.
+	problem string
+}
+
+// pendingEdge is an edge whose dependency is known by reference only.
+type pendingEdge struct {
+	from     string
+	ref      string
+	name     string
+	required bool
+}
+
+// resolver builds a Graph.
+type resolver struct {
+	client Client
+	opts   Options
+	graph  *Graph
+	// nodes indexes the nodes by lower-case ID.
+	nodes map[string]*Node
+	// refs maps lower-case project slugs and IDs to node IDs.
+	refs map[string]string
+	// queued holds the refs that are fetched or about to be.
+	queued   map[string]bool
+	edges    []pendingEdge
+	problems map[string]fetched
+}
+
+// Resolve builds the dependency graph of the plugins. Each plugin is a project slug,
+// optionally followed by @version to pin the version; otherwise the newest version in
+// the channel that supports the platform and Minecraft version is used. Dependencies
+// are resolved the same way, level by level.
+func Resolve(ctx context.Context, client Client, plugins []string, opts Options) (*Graph, error) {
+	if opts.Platform == "" {
+		opts.Platform = manifest.DefaultPlatform
+	}
+	opts.Platform = strings.ToUpper(opts.Platform)
+	if opts.Channel == "" {
+		opts.Channel = manifest.DefaultChannel
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+
+	r := &resolver{
+		client:   client,
+		opts:     opts,
+		graph:    &Graph{Platform: opts.Platform, MinecraftVersion: opts.MinecraftVersion},
+		nodes:    make(map[string]*Node),
+		refs:     make(map[string]string),
+		queued:   make(map[string]bool),
+		problems: make(map[string]fetched),
+	}
+
+	level := make([]target, 0, len(plugins))
+	for _, plugin := range plugins {
+		slug, pinned, _ := strings.Cut(plugin, "@")
+		if slug == "" {
+			return nil, errors.Newf("invalid plugin %q", plugin)
+		}
+		level = append(level, target{ref: slug, name: slug, pinned: pinned, root: true})
+		r.queued[strings.ToLower(slug)] = true
+	}
+
+	for len(level) > 0 {
+		results := make([]fetched, len(level))
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(opts.Concurrency)
+		for i, t := range level {
+			group.Go(func() error {
+				result, err := r.fetch(groupCtx, t)
+				if err != nil {
+					return errors.Wrapf(err, "failed to resolve %s", t.name)
+				}
+				results[i] = *result
+				return nil
+			})
+		}
+		if err := group.Wait(); err != nil {
+			return nil, err
+		}
+
+		var next []target
+		for i, t := range level {
+			if node := r.add(t, results[i]); node != nil && node.Resolved != nil {
+				next = r.link(node, next)
+			}
+		}
+		level = next
+	}
+
+	r.finish()
+
+	return r.graph, nil
+}
+
+// fetch gets the project and picks its version.
+func (r *resolver) fetch(ctx context.Context, t target) (*fetched, error) {
+	project, err := r.client.GetProject(ctx, t.ref)
+	if hangar.IsNotFound(err) && !t.root {
+		return &fetched{notFound: true, problem: "not found on Hangar"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := r.client.ListAllVersions(ctx, project.Namespace.Owner, project.Namespace.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &fetched{project: project}
+	anyVersion, _ := manifest.ParseConstraint("")
+
+	if t.pinned != "" {
+		for i := range versions {
+			if versions[i].Name == t.pinned {
+				result.version = &versions[i]
+				break
+			}
+		}
+		if result.version == nil {
+			return nil, errors.Newf("version %s not found", t.pinned)
+		}
+		if manifest.SelectVersion([]hangar.Version{*result.version}, anyVersion, "", r.opts.Platform, r.opts.MinecraftVersion) == nil {
+			result.problem = "pinned version " + t.pinned + " does not support " + r.target()
+		}
+		return result, nil
+	}
+
+	result.version = manifest.SelectVersion(versions, anyVersion, r.opts.Channel, r.opts.Platform, r.opts.MinecraftVersion)
+	if result.version == nil {
+		result.problem = "no " + r.opts.Channel + " version for " + r.target()
+	}
+
+	return result, nil
+}
+
+// target describes the platform and Minecraft version in problem messages.
+func (r *resolver) target() string {
+	if r.opts.MinecraftVersion == "" {
+		return r.opts.Platform
+	}
+
+	return r.opts.Platform + " on Minecraft " + r.opts.MinecraftVersion
+}
+
+// add records a fetched target as a node. It returns nil if the project is already in
+// the graph under another reference.
+func (r *resolver) add(t target, result fetched) *Node {
+	ref := strings.ToLower(t.ref)
+
+	if result.notFound {
+		node := &Node{ID: "project:" + t.ref, Name: t.name, Missing: true}
+		r.insert(node, ref)
+		r.problems[node.ID] = result
+		return node
+	}
+
+	id := result.project.Namespace.Slug
+	if existing, ok := r.nodes[strings.ToLower(id)]; ok {
+		r.refs[ref] = existing.ID
+		existing.Root = existing.Root || t.root
+		return nil
+	}
+
+	node := &Node{
+		ID:      id,
+		Name:    result.project.Name,
+		Owner:   result.project.Namespace.Owner,
+		Root:    t.root,
+		Project: result.project,
+	}
+	if result.version != nil {
+		node.Version = result.version.Name
+		node.Channel = result.version.Channel.Name
+		node.Resolved = result.version
+	}
+	if result.problem != "" {
+		node.Missing = result.version == nil
+		r.problems[node.ID] = result
+	}
+
+	r.insert(node, ref, strings.ToLower(id), strconv.FormatInt(result.project.ID, 10))
+
+	return node
+}
+
+// insert adds a node to the graph and maps the references to it.
+func (r *resolver) insert(node *Node, refs ...string) {
+	r.graph.Nodes = append(r.graph.Nodes, node)
+	r.nodes[strings.ToLower(node.ID)] = node
+	for _, ref := range refs {
+		r.refs[ref] = node.ID
+		r.queued[ref] = true
+	}
+}
+
+// link records the dependencies of a resolved node and returns next with the projects
+// that still have to be fetched.
+func (r *resolver) link(node *Node, next []target) []target {
+	for _, dependency := range node.Resolved.PluginDependencies[r.opts.Platform] {
+		edge := pendingEdge{from: node.ID, name: dependency.Name, required: dependency.Required}
+
+		if dependency.ProjectID == nil {
+			id := externalPrefix + dependency.Name
+			edge.ref = strings.ToLower(id)
+			if _, ok := r.nodes[edge.ref]; !ok {
+				r.insert(&Node{
+					ID:          id,
+					Name:        dependency.Name,
+					ExternalURL: dependency.ExternalURL,
+					External:    true,
+					Missing:     dependency.ExternalURL == "",
+				}, edge.ref)
+			}
+			r.edges = append(r.edges, edge)
+			continue
+		}
+
+		edge.ref = strconv.FormatInt(*dependency.ProjectID, 10)
+		r.edges = append(r.edges, edge)
+		if !r.queued[edge.ref] {
+			r.queued[edge.ref] = true
+			next = append(next, target{ref: edge.ref, name: dependency.Name})
+		}
+	}
+
+	return next
+}
+
+// finish resolves the pending edges, marks the required nodes and collects the problems.
+func (r *resolver) finish() {
+	seen := make(map[Edge]bool, len(r.edges))
+	names := make(map[string][]string)
+	display := make(map[string]string)
+	for _, pending := range r.edges {
+		edge := Edge{From: pending.from, To: r.refs[pending.ref], Required: pending.required}
+		if !seen[edge] {
+			seen[edge] = true
+			r.graph.Edges = append(r.graph.Edges, edge)
+		}
+
+		key := strings.ToLower(pending.name)
+		if _, ok := display[key]; !ok {
+			display[key] = pending.name
+		}
+		if !slices.Contains(names[key], edge.To) {
+			names[key] = append(names[key], edge.To)
+		}
+	}
+
+	// Dependencies named like a Hangar project in the graph should refer to it
+	for _, node := range r.graph.Nodes {
+		if node.Project == nil {
+			continue
+		}
+		for _, name := range []string{node.ID, node.Name} {
+			key := strings.ToLower(name)
+			if _, ok := names[key]; ok && !slices.Contains(names[key], node.ID) {
+				names[key] = append(names[key], node.ID)
+			}
+		}
+	}
+
+	r.markRequired()
+	r.graph.Cycles = cycles(r.graph)
+
+	for _, node := range r.graph.Nodes {
+		dependents := r.dependents(node.ID)
+		problem := Problem{Dependency: node.Name, RequiredBy: dependents, Required: node.Required}
+
+		switch result, ok := r.problems[node.ID]; {
+		case ok && node.Missing:
+			problem.Kind = ProblemMissing
+			problem.Message = result.problem
+		case ok:
+			problem.Kind = ProblemConflict
+			problem.Message = result.problem
+		case node.External && node.Missing:
+			problem.Kind = ProblemMissing
+			problem.Message = "not published on Hangar and no download URL given"
+		case node.External:
+			problem.Kind = ProblemExternal
+			problem.Message = "not on Hangar; download it from " + node.ExternalURL
+		default:
+			continue
+		}
+		r.graph.Problems = append(r.graph.Problems, problem)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(names)) {
+		targets := names[key]
+		if len(targets) < 2 {
+			continue
+		}
+
+		var dependents []string
+		required := false
+		for _, id := range targets {
+			dependents = append(dependents, r.dependents(id)...)
+			required = required || r.nodes[strings.ToLower(id)].Required
+		}
+		r.graph.Problems = append(r.graph.Problems, Problem{
+			Kind:       ProblemConflict,
+			Dependency: display[key],
+			RequiredBy: dependents,
+			Required:   required,
+			Message:    "the name refers to different plugins: " + strings.Join(targets, ", "),
+		})
+	}
+}
+
+// markRequired marks the roots and every node reachable from them through required edges.
+func (r *resolver) markRequired() {
+	var queue []string
+	for _, node := range r.graph.Nodes {
+		if node.Root {
+			node.Required = true
+			queue = append(queue, node.ID)
+		}
+	}
+
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, edge := range r.graph.Edges {
+			if edge.From != from || !edge.Required {
+				continue
+			}
+			if node := r.nodes[strings.ToLower(edge.To)]; !node.Required {
+				node.Required = true
+				queue = append(queue, node.ID)
+			}
+		}
+	}
+}
+
+// dependents returns the names of the nodes depending on the node.
+func (r *resolver) dependents(id string) []string {
+	var dependents []string
+	for _, edge := range r.graph.Edges {
+		if edge.To == id {
+			dependents = append(dependents, r.nodes[strings.ToLower(edge.From)].Name)
+		}
+	}
+
+	return dependents
+}
@@ -0,0 +1,164 @@
+package deps_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lexfrei/go-hangar/internal/deps"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient serves projects and their versions from memory. Projects are found by
+// slug or ID.
+type fakeClient struct {
+	projects []hangar.Project
+	versions map[string][]hangar.Version
+}
+
+func (f *fakeClient) GetProject(_ context.Context, slug string) (*hangar.Project, error) {
+	for i := range f.projects {
+		project := &f.projects[i]
+		if strings.EqualFold(project.Namespace.Slug, slug) || strconv.FormatInt(project.ID, 10) == slug {
+			return project, nil
+		}
+	}
+
+	return nil, &hangar.APIError{StatusCode: 404, Body: "not found"}
+}
+
+func (f *fakeClient) ListAllVersions(_ context.Context, _, slug string) ([]hangar.Version, error) {
+	return f.versions[slug], nil
+}
+
+func id(value int64) *int64 {
+	return &value
+}
+
+// version returns a PAPER version supporting the Minecraft versions with the dependencies.
+func version(name string, minecraft []string, dependencies ...hangar.PluginDependency) hangar.Version {
+	return hangar.Version{
+		Name:                 name,
+		Channel:              hangar.Channel{Name: "Release"},
+		Downloads:            map[string]hangar.DownloadInfo{"PAPER": {DownloadURL: "https://hangar.test/" + name}},
+		PlatformDependencies: map[string][]string{"PAPER": minecraft},
+		PluginDependencies:   map[string][]hangar.PluginDependency{"PAPER": dependencies},
+	}
+}
+
+func newFakeClient() *fakeClient {
+	project := func(id int64, slug string) hangar.Project {
+		return hangar.Project{ID: id, Name: slug, Namespace: hangar.Namespace{Owner: "owner", Slug: slug}}
+	}
+
+	return &fakeClient{
+		projects: []hangar.Project{project(1, "Alpha"), project(2, "Beta"), project(3, "Gamma"), project(4, "Delta")},
+		versions: map[string][]hangar.Version{
+			"Alpha": {
+				version("2.0", []string{"1.21"},
+					hangar.PluginDependency{Name: "Beta", ProjectID: id(2), Required: true},
+					hangar.PluginDependency{Name: "Vault", ExternalURL: "https://vault.test", Required: false},
+					hangar.PluginDependency{Name: "Ghost", ProjectID: id(99), Required: true},
+				),
+				version("1.0", []string{"1.20"}),
+			},
+			"Beta": {
+				version("3.0", []string{"1.21"},
+					hangar.PluginDependency{Name: "Alpha", ProjectID: id(1), Required: false},
+					hangar.PluginDependency{Name: "Gamma", ProjectID: id(3), Required: false},
+				),
+			},
+			"Gamma": {version("1.0", []string{"1.19"})},
+			"Delta": {
+				version("1.0", []string{"1.21"},
+					hangar.PluginDependency{Name: "beta", ExternalURL: "https://beta.test", Required: true},
+				),
+			},
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	graph, err := deps.Resolve(context.Background(), newFakeClient(), []string{"alpha"}, deps.Options{MinecraftVersion: "1.21"})
+	require.NoError(t, err)
+
+	var nodes []string
+	for _, node := range graph.Nodes {
+		nodes = append(nodes, node.ID+"@"+node.Version)
+	}
+	assert.Equal(t, []string{"Alpha@2.0", "external:Vault@", "Beta@3.0", "project:99@", "Gamma@"}, nodes)
+
+	assert.Equal(t, []deps.Edge{
+		{From: "Alpha", To: "Beta", Required: true},
+		{From: "Alpha", To: "external:Vault"},
+		{From: "Alpha", To: "project:99", Required: true},
+		{From: "Beta", To: "Alpha"},
+		{From: "Beta", To: "Gamma"},
+	}, graph.Edges)
+
+	assert.True(t, graph.Node("Alpha").Root)
+	assert.True(t, graph.Node("Beta").Required)
+	assert.False(t, graph.Node("Gamma").Required, "only reachable through optional dependencies")
+	assert.True(t, graph.Node("external:Vault").External)
+
+	assert.Equal(t, []deps.Problem{
+		{
+			Kind: deps.ProblemExternal, Dependency: "Vault", RequiredBy: []string{"Alpha"},
+			Message: "not on Hangar; download it from https://vault.test",
+		},
+		{
+			Kind: deps.ProblemMissing, Dependency: "Ghost", RequiredBy: []string{"Alpha"}, Required: true,
+			Message: "not found on Hangar",
+		},
+		{
+			Kind: deps.ProblemMissing, Dependency: "Gamma", RequiredBy: []string{"Beta"},
+			Message: "no Release version for PAPER on Minecraft 1.21",
+		},
+	}, graph.Problems)
+
+	require.Len(t, graph.Unresolved(), 1)
+	assert.Equal(t, "Ghost", graph.Unresolved()[0].Dependency)
+
+	var installable []string
+	for _, node := range graph.Installable(false) {
+		installable = append(installable, node.ID)
+	}
+	assert.Equal(t, []string{"Alpha", "Beta"}, installable)
+}
+
+func TestResolve_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	graph, err := deps.Resolve(context.Background(), newFakeClient(), []string{"Beta", "delta", "alpha@1.0"}, deps.Options{
+		MinecraftVersion: "1.21",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.0", graph.Node("Alpha").Version, "pinned versions are kept")
+
+	var conflicts []string
+	for _, problem := range graph.Unresolved() {
+		conflicts = append(conflicts, problem.Kind+": "+problem.Dependency+": "+problem.Message)
+	}
+	assert.Equal(t, []string{
+		"conflict: Alpha: pinned version 1.0 does not support PAPER on Minecraft 1.21",
+		"conflict: beta: the name refers to different plugins: external:beta, Beta",
+	}, conflicts)
+}
+
+func TestResolve_UnknownRoot(t *testing.T) {
+	t.Parallel()
+
+	_, err := deps.Resolve(context.Background(), newFakeClient(), []string{"nope"}, deps.Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to resolve nope")
+
+	_, err = deps.Resolve(context.Background(), newFakeClient(), []string{"alpha@9.9"}, deps.Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "version 9.9 not found")
+}
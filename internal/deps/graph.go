@@ -0,0 +1,227 @@
+package deps
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// cycles returns the strongly connected components of the graph that form cycles,
+// including plugins that depend on themselves, in the order of their first node.
+func cycles(graph *Graph) [][]string {
+	adjacent := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+	}
+
+	// Tarjan's algorithm
+	var (
+		index    = make(map[string]int)
+		low      = make(map[string]int)
+		onStack  = make(map[string]bool)
+		stack    []string
+		next     int
+		found    [][]string
+		position = make(map[string]int)
+	)
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id], low[id] = next, next
+		next++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, to := range adjacent[id] {
+			if _, seen := index[to]; !seen {
+				visit(to)
+				low[id] = min(low[id], low[to])
+			} else if onStack[to] {
+				low[id] = min(low[id], index[to])
+			}
+		}
+
+		if low[id] != index[id] {
+			return
+		}
+
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == id {
+				break
+			}
+		}
+		if len(component) > 1 || slices.Contains(adjacent[id], id) {
+			found = append(found, component)
+		}
+	}
+
+	for i, node := range graph.Nodes {
+		position[node.ID] = i
+	}
+	for _, node := range graph.Nodes {
+		if _, seen := index[node.ID]; !seen {
+			visit(node.ID)
+		}
+	}
+
+	// Report the plugins of each cycle and the cycles themselves in graph order
+	byPosition := func(a, b string) int { return cmp.Compare(position[a], position[b]) }
+	for _, component := range found {
+		slices.SortFunc(component, byPosition)
+	}
+	slices.SortFunc(found, func(a, b []string) int { return byPosition(a[0], b[0]) })
+
+	return found
+}
+
+// InCycle reports whether the edge is part of a cycle.
+func (g *Graph) InCycle(edge Edge) bool {
+	for _, cycle := range g.Cycles {
+		from, to := false, false
+		for _, id := range cycle {
+			from = from || id == edge.From
+			to = to || id == edge.To
+		}
+		if from && to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// label returns the display text of a node.
+func (n *Node) label() string {
+	switch {
+	case n.Missing:
+		return n.Name + " (missing)"
+	case n.External:
+		return n.Name + " (external)"
+	default:
+		return n.Name + " " + n.Version
+	}
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language. Requested plugins are bold,
+// external dependencies dashed, missing ones red, optional edges dashed and the edges of
+// cycles red.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for _, node := range g.Nodes {
+		var attributes []string
+		attributes = append(attributes, "label="+dotQuote(node.label()))
+		var styles []string
+		if node.Root {
+			styles = append(styles, "bold")
+		}
+		if node.External {
+			styles = append(styles, "dashed")
+		}
+		if len(styles) > 0 {
+			attributes = append(attributes, "style="+dotQuote(strings.Join(styles, ",")))
+		}
+		if node.Missing {
+			attributes = append(attributes, "color=red", "fontcolor=red")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(node.ID), strings.Join(attributes, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		var attributes []string
+		if !edge.Required {
+			attributes = append(attributes, "style=dashed", `label="optional"`)
+		}
+		if g.InCycle(edge) {
+			attributes = append(attributes, "color=red", "penwidth=2")
+		}
+
+		fmt.Fprintf(&b, "  %s -> %s", dotQuote(edge.From), dotQuote(edge.To))
+		if len(attributes) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attributes, ", "))
+		}
+		b.WriteString(";\n")
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// dotQuote returns s as a DOT string literal.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart with the same styling as WriteDOT.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	ids := make(map[string]string, len(g.Nodes))
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "  %s[%s]\n", ids[node.ID], mermaidQuote(node.label()))
+	}
+
+	var cycleLinks []string
+	for i, edge := range g.Edges {
+		arrow := "-->"
+		if !edge.Required {
+			arrow = "-. optional .->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+		if g.InCycle(edge) {
+			cycleLinks = append(cycleLinks, fmt.Sprint(i))
+		}
+	}
+
+	classes := []struct {
+		name  string
+		style string
+		match func(n *Node) bool
+	}{
+		{name: "root", style: "stroke-width:3px", match: func(n *Node) bool { return n.Root }},
+		{name: "external", style: "stroke-dasharray:5 5", match: func(n *Node) bool { return n.External }},
+		{name: "missing", style: "stroke:#d00,color:#d00", match: func(n *Node) bool { return n.Missing }},
+	}
+	for _, class := range classes {
+		var members []string
+		for _, node := range g.Nodes {
+			if class.match(node) {
+				members = append(members, ids[node.ID])
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "  classDef %s %s\n", class.name, class.style)
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(members, ","), class.name)
+	}
+
+	if len(cycleLinks) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:#d00,stroke-width:2px\n", strings.Join(cycleLinks, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// mermaidQuote returns s as a quoted Mermaid label.
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
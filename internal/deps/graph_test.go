@@ -0,0 +1,62 @@
+package deps_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/lexfrei/go-hangar/internal/deps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph_Export(t *testing.T) {
+	t.Parallel()
+
+	graph, err := deps.Resolve(context.Background(), newFakeClient(), []string{"alpha"}, deps.Options{MinecraftVersion: "1.21"})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"Alpha", "Beta"}}, graph.Cycles)
+	assert.True(t, graph.InCycle(deps.Edge{From: "Beta", To: "Alpha"}))
+	assert.False(t, graph.InCycle(deps.Edge{From: "Beta", To: "Gamma"}))
+
+	var dot bytes.Buffer
+	require.NoError(t, graph.WriteDOT(&dot))
+	assert.Equal(t, `digraph dependencies {
+  rankdir=LR;
+  node [shape=box];
+  "Alpha" [label="Alpha 2.0", style="bold"];
+  "external:Vault" [label="Vault (external)", style="dashed"];
+  "Beta" [label="Beta 3.0"];
+  "project:99" [label="Ghost (missing)", color=red, fontcolor=red];
+  "Gamma" [label="Gamma (missing)", color=red, fontcolor=red];
+  "Alpha" -> "Beta" [color=red, penwidth=2];
+  "Alpha" -> "external:Vault" [style=dashed, label="optional"];
+  "Alpha" -> "project:99";
+  "Beta" -> "Alpha" [style=dashed, label="optional", color=red, penwidth=2];
+  "Beta" -> "Gamma" [style=dashed, label="optional"];
+}
+`, dot.String())
+
+	var mermaid bytes.Buffer
+	require.NoError(t, graph.WriteMermaid(&mermaid))
+	assert.Equal(t, `flowchart LR
+  n0["Alpha 2.0"]
+  n1["Vault (external)"]
+  n2["Beta 3.0"]
+  n3["Ghost (missing)"]
+  n4["Gamma (missing)"]
+  n0 --> n2
+  n0 -. optional .-> n1
+  n0 --> n3
+  n2 -. optional .-> n0
+  n2 -. optional .-> n4
+  classDef root stroke-width:3px
+  class n0 root
+  classDef external stroke-dasharray:5 5
+  class n1 external
+  classDef missing stroke:#d00,color:#d00
+  class n3,n4 missing
+  linkStyle 0,3 stroke:#d00,stroke-width:2px
+`, mermaid.String())
+}
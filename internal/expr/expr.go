@@ -0,0 +1,551 @@
+// Package expr implements the small boolean expression language used by --filter,
+// e.g. `stats.downloads > 1000 && category == "chat"`.
+//
+// Operands are field paths, quoted strings, numbers, true, false and null.
+// Operators are == != < <= > >= =~ (regex match) !~ (regex mismatch), && || ! and parentheses.
+// A bare field is true when it is set and not false, zero or empty.
+// Comparing a list is true when any element matches.
+package expr
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Resolver returns the value of a field path and whether it exists.
+type Resolver func(path string) (any, bool)
+
+// Expr is a compiled expression.
+type Expr struct {
+	root node
+}
+
+// Compile parses an expression.
+func Compile(src string) (*Expr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid filter %q", src)
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid filter %q", src)
+	}
+	if !p.done() {
+		return nil, errors.Newf("invalid filter %q: unexpected %q", src, p.peek().text)
+	}
+
+	return &Expr{root: root}, nil
+}
+
+// Fields returns the field paths referenced by the expression in order of appearance.
+func (e *Expr) Fields() []string {
+	var fields []string
+	seen := make(map[string]bool)
+
+	var walk func(n node)
+	walk = func(n node) {
+		switch n := n.(type) {
+		case orNode:
+			walk(n.left)
+			walk(n.right)
+		case andNode:
+			walk(n.left)
+			walk(n.right)
+		case notNode:
+			walk(n.inner)
+		case truthyNode:
+			n.operand.collect(&fields, seen)
+		case compareNode:
+			n.left.collect(&fields, seen)
+			n.right.collect(&fields, seen)
+		}
+	}
+	walk(e.root)
+
+	return fields
+}
+
+// Match evaluates the expression with fields looked up by resolve.
+func (e *Expr) Match(resolve Resolver) bool {
+	return e.root.eval(resolve)
+}
+
+// node is a boolean expression node.
+type node interface {
+	eval(resolve Resolver) bool
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(resolve Resolver) bool { return n.left.eval(resolve) || n.right.eval(resolve) }
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(resolve Resolver) bool { return n.left.eval(resolve) && n.right.eval(resolve) }
+
+type notNode struct{ inner node }
+
+func (n notNode) eval(resolve Resolver) bool { return !n.inner.eval(resolve) }
+
+// truthyNode tests a bare operand.
+type truthyNode struct{ operand operand }
+
+func (n truthyNode) eval(resolve Resolver) bool {
+	value, ok := n.operand.value(resolve)
+	return ok && Truthy(value)
+}
+
+// compareNode compares two operands.
+type compareNode struct {
+	left, right operand
+	op          string
+	pattern     *regexp.Regexp
+}
+
+func (n compareNode) eval(resolve Resolver) bool {
+	left, _ := n.left.value(resolve)
+	right, _ := n.right.value(resolve)
+
+	// Lists match when any element matches
+	if list, ok := asList(left); ok {
+		for _, element := range list {
+			if n.compare(element, right) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return n.compare(left, right)
+}
+
+func (n compareNode) compare(left, right any) bool {
+	switch n.op {
+	case "=~":
+		return left != nil && n.pattern.MatchString(toString(left))
+	case "!~":
+		return left == nil || !n.pattern.MatchString(toString(left))
+	case "==":
+		return Compare(left, right) == 0
+	case "!=":
+		return Compare(left, right) != 0
+	}
+
+	// Ordering comparisons never match missing values
+	if left == nil || right == nil {
+		return false
+	}
+
+	c := Compare(left, right)
+	switch n.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	default:
+		return false
+	}
+}
+
+// operand is a field reference or a literal.
+type operand struct {
+	field   string
+	literal any
+}
+
+// collect appends the field of a field reference once.
+func (o operand) collect(fields *[]string, seen map[string]bool) {
+	if o.field != "" && !seen[o.field] {
+		seen[o.field] = true
+		*fields = append(*fields, o.field)
+	}
+}
+
+func (o operand) value(resolve Resolver) (any, bool) {
+	if o.field == "" {
+		return o.literal, true
+	}
+
+	value, ok := resolve(o.field)
+	return Normalize(value), ok
+}
+
+// Normalize converts numbers to float64, times to RFC 3339 strings and dereferences pointers
+// so values compare uniformly.
+func Normalize(value any) any {
+	if value == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	// Times compare as RFC 3339 text, which also orders correctly against date literals
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		// json.Number and other string kinds
+		if n, ok := value.(interface{ Float64() (float64, error) }); ok {
+			if f, err := n.Float64(); err == nil {
+				return f
+			}
+		}
+		return v.String()
+	default:
+		return v.Interface()
+	}
+}
+
+// Compare orders two normalized values: nil first, then numbers, then other values by their text.
+func Compare(a, b any) int {
+	a, b = Normalize(a), Normalize(b)
+
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	af, aNumber := a.(float64)
+	bf, bNumber := b.(float64)
+	switch {
+	case aNumber && bNumber:
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		default:
+			return 0
+		}
+	case aNumber:
+		return -1
+	case bNumber:
+		return 1
+	}
+
+	return strings.Compare(toString(a), toString(b))
+}
+
+// Truthy reports whether a value is set and not false, zero or empty.
+func Truthy(value any) bool {
+	switch v := Normalize(value).(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	default:
+		if list, ok := asList(v); ok {
+			return len(list) > 0
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Map {
+			return rv.Len() > 0
+		}
+		return true
+	}
+}
+
+// asList returns the elements of a slice value.
+func asList(value any) ([]any, bool) {
+	if list, ok := value.([]any); ok {
+		return list, true
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		return nil, false
+	}
+
+	list := make([]any, v.Len())
+	for i := range list {
+		list[i] = Normalize(v.Index(i).Interface())
+	}
+
+	return list, true
+}
+
+// toString formats a value for string comparisons and regex matching.
+func toString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// token is a lexical token.
+type token struct {
+	kind tokenKind
+	text string
+}
+
+type tokenKind int
+
+const (
+	tokenField tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+// operators are matched longest first.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!", "(", ")"}
+
+// tokenize splits the source into tokens.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, errors.Newf("unterminated string at position %d", i)
+			}
+			text := src[i+1 : end]
+			if c == '"' {
+				unquoted, err := strconv.Unquote(src[i : end+1])
+				if err != nil {
+					return nil, errors.Newf("invalid string at position %d", i)
+				}
+				text = unquoted
+			} else {
+				text = strings.ReplaceAll(text, `\'`, "'")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text})
+			i = end + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			end := i + 1
+			for end < len(src) && (src[end] >= '0' && src[end] <= '9' || src[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[i:end]})
+			i = end
+		case isFieldChar(c, true):
+			end := i + 1
+			for end < len(src) && isFieldChar(src[end], false) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenField, text: src[i:end]})
+			i = end
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, errors.Newf("unexpected %q at position %d", c, i)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// isFieldChar reports whether c may appear in a field path.
+func isFieldChar(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case !first && (c >= '0' && c <= '9' || c == '.' || c == '-'):
+		return true
+	default:
+		return false
+	}
+}
+
+// parser is a recursive descent parser over tokens.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) accept(op string) bool {
+	if !p.done() && p.peek().kind == tokenOperator && p.peek().text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, errors.New("missing )")
+		}
+		return inner, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "=~", "!~"} {
+		if !p.accept(op) {
+			continue
+		}
+
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		comparison := compareNode{left: left, right: right, op: op}
+		if op == "=~" || op == "!~" {
+			pattern, ok := right.literal.(string)
+			if right.field != "" || !ok {
+				return nil, errors.Newf("%s requires a string pattern", op)
+			}
+			if comparison.pattern, err = regexp.Compile(pattern); err != nil {
+				return nil, errors.Wrapf(err, "invalid pattern %q", pattern)
+			}
+		}
+
+		return comparison, nil
+	}
+
+	return truthyNode{operand: left}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	if p.done() {
+		return operand{}, errors.New("unexpected end of expression")
+	}
+
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch tok.kind {
+	case tokenString:
+		return operand{literal: tok.text}, nil
+	case tokenNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return operand{}, errors.Newf("invalid number %q", tok.text)
+		}
+		return operand{literal: n}, nil
+	case tokenField:
+		switch tok.text {
+		case "true":
+			return operand{literal: true}, nil
+		case "false":
+			return operand{literal: false}, nil
+		case "null":
+			return operand{literal: nil}, nil
+		}
+		return operand{field: tok.text}, nil
+	default:
+		return operand{}, errors.Newf("unexpected %q", tok.text)
+	}
+}
@@ -0,0 +1,130 @@
+package expr_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/internal/expr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fields = map[string]any{
+	"name":            "EssentialsX",
+	"category":        "chat",
+	"stats.downloads": int64(12345),
+	"stats.stars":     json.Number("42"),
+	"rating":          (*float64)(nil),
+	"featured":        true,
+	"archived":        false,
+	"tags":            []string{"admin", "chat"},
+	"createdAt":       time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC),
+}
+
+func resolve(path string) (any, bool) {
+	value, ok := fields[path]
+	return value, ok
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{src: `stats.downloads > 1000 && category == "chat"`, want: true},
+		{src: `stats.downloads > 1000 && category == "admin"`, want: false},
+		{src: `stats.downloads < 1000 || category == 'chat'`, want: true},
+		{src: `stats.downloads >= 12345`, want: true},
+		{src: `stats.downloads <= 12344`, want: false},
+		{src: `stats.stars == 42`, want: true},
+		{src: `stats.stars != 42`, want: false},
+		{src: `name =~ "^Essentials"`, want: true},
+		{src: `name !~ "(?i)worldedit"`, want: true},
+		{src: `tags == "admin"`, want: true},
+		{src: `tags == "economy"`, want: false},
+		{src: `featured`, want: true},
+		{src: `!archived`, want: true},
+		{src: `rating`, want: false},
+		{src: `rating == null`, want: true},
+		{src: `rating > 3`, want: false},
+		{src: `missing`, want: false},
+		{src: `!(category == "chat" && featured)`, want: false},
+		{src: `createdAt >= "2024-01-01"`, want: true},
+		{src: `createdAt < "2023"`, want: false},
+		{src: `featured == true && archived == false`, want: true},
+		{src: `stats.downloads > -1.5`, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := expr.Compile(tt.src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, e.Match(resolve))
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		``,
+		`category ==`,
+		`(featured`,
+		`featured)`,
+		`name == "unterminated`,
+		`name =~ "["`,
+		`name =~ category`,
+		`stats.downloads > 1000 &`,
+		`downloads # 3`,
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := expr.Compile(src)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	e, err := expr.Compile(`stats.downloads > 10 && (category == "chat" || !featured) && category != "x"`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"stats.downloads", "category", "featured"}, e.Fields())
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b any
+		want int
+	}{
+		{name: "nil first", a: nil, b: 1, want: -1},
+		{name: "both nil", a: nil, b: (*int)(nil), want: 0},
+		{name: "numbers across types", a: int32(3), b: 2.5, want: 1},
+		{name: "json number", a: json.Number("10"), b: 9, want: 1},
+		{name: "numbers before strings", a: 100, b: "a", want: -1},
+		{name: "strings", a: "alpha", b: "beta", want: -1},
+		{name: "times", a: time.Unix(0, 0), b: time.Unix(60, 0), want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, expr.Compare(tt.a, tt.b))
+		})
+	}
+}
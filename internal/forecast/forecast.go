@@ -0,0 +1,309 @@
+// Package forecast fits a linear trend with weekly seasonality to daily series,
+// forecasts future values and flags anomalous days.
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// MinPoints is the minimum number of days required to fit a model (two full weeks).
+	MinPoints = 14
+	// outlierZ is the score above which points are excluded when refitting the model.
+	outlierZ = 3.5
+	// madScale converts the median absolute deviation into a standard deviation estimate.
+	madScale = 1.4826
+	// minSigma keeps scores finite for perfectly regular series (one download or view).
+	minSigma = 1.0
+)
+
+// Point is a single day of a series.
+type Point struct {
+	// Date is the day of the observation.
+	Date time.Time
+	// Value is the observed value.
+	Value float64
+}
+
+// Model is an additive model: value = intercept + slope*day + seasonal[weekday] + noise.
+type Model struct {
+	// Intercept is the trend value on the first day.
+	Intercept float64 `json:"intercept"`
+	// Slope is the trend change per day.
+	Slope float64 `json:"slope"`
+	// Seasonal is the weekly effect indexed by time.Weekday (Sunday first).
+	Seasonal [7]float64 `json:"seasonal"`
+	// Sigma is the robust standard deviation of the residuals.
+	Sigma float64 `json:"sigma"`
+	// Outliers is the number of days excluded from the final fit.
+	Outliers int `json:"outliers"`
+
+	start time.Time
+	days  int
+	meanT float64
+	sxx   float64
+}
+
+// Fit fits the model to a contiguous daily series (missing days should be filled with zeros).
+// Extreme outliers are excluded in a second pass so that single spikes do not skew the trend.
+func Fit(points []Point) (*Model, error) {
+	if len(points) < MinPoints {
+		return nil, errors.Newf("at least %d days of data are required, got %d", MinPoints, len(points))
+	}
+
+	model := &Model{start: dayOf(points[0].Date), days: len(points)}
+	include := make([]bool, len(points))
+	for i := range include {
+		include[i] = true
+	}
+
+	model.fit(points, include)
+
+	// Refit without extreme points
+	for i, point := range points {
+		residual := point.Value - model.Predict(point.Date)
+		if math.Abs(residual/model.Sigma) > outlierZ {
+			include[i] = false
+			model.Outliers++
+		}
+	}
+	if model.Outliers > 0 && len(points)-model.Outliers >= MinPoints {
+		model.fit(points, include)
+	} else {
+		model.Outliers = 0
+	}
+
+	return model, nil
+}
+
+// fit estimates trend and seasonality by alternating between the two, then the residual spread.
+func (m *Model) fit(points []Point, include []bool) {
+	m.Seasonal = [7]float64{}
+
+	for range 3 {
+		m.fitTrend(points, include)
+		m.fitSeasonal(points, include)
+	}
+
+	residuals := make([]float64, 0, len(points))
+	for i, point := range points {
+		if include[i] {
+			residuals = append(residuals, point.Value-m.Predict(point.Date))
+		}
+	}
+
+	m.Sigma = robustSigma(residuals)
+}
+
+// fitTrend runs an ordinary least squares regression on the deseasonalized values.
+func (m *Model) fitTrend(points []Point, include []bool) {
+	var n, sumT, sumY float64
+	for i, point := range points {
+		if !include[i] {
+			continue
+		}
+		n++
+		sumT += m.dayIndex(point.Date)
+		sumY += point.Value - m.Seasonal[point.Date.Weekday()]
+	}
+
+	m.meanT = sumT / n
+	meanY := sumY / n
+
+	var sxy float64
+	m.sxx = 0
+	for i, point := range points {
+		if !include[i] {
+			continue
+		}
+		dt := m.dayIndex(point.Date) - m.meanT
+		m.sxx += dt * dt
+		sxy += dt * (point.Value - m.Seasonal[point.Date.Weekday()] - meanY)
+	}
+
+	m.Slope = 0
+	if m.sxx > 0 {
+		m.Slope = sxy / m.sxx
+	}
+	m.Intercept = meanY - m.Slope*m.meanT
+}
+
+// fitSeasonal takes the median detrended value per weekday and centers the effects around zero.
+// Medians keep a single spike from shifting the whole weekday.
+func (m *Model) fitSeasonal(points []Point, include []bool) {
+	var detrended [7][]float64
+	for i, point := range points {
+		if !include[i] {
+			continue
+		}
+		weekday := point.Date.Weekday()
+		detrended[weekday] = append(detrended[weekday], point.Value-m.Intercept-m.Slope*m.dayIndex(point.Date))
+	}
+
+	var total, present float64
+	for day, values := range detrended {
+		m.Seasonal[day] = 0
+		if len(values) > 0 {
+			m.Seasonal[day] = medianOf(values)
+			total += m.Seasonal[day]
+			present++
+		}
+	}
+
+	for day, values := range detrended {
+		if len(values) > 0 {
+			m.Seasonal[day] -= total / present
+		}
+	}
+}
+
+// Predict returns the expected value for a day.
+func (m *Model) Predict(date time.Time) float64 {
+	return m.Intercept + m.Slope*m.dayIndex(date) + m.Seasonal[date.Weekday()]
+}
+
+// Anomaly is a day whose value deviates from the model beyond the threshold.
+type Anomaly struct {
+	// Date is the day of the anomaly.
+	Date time.Time `json:"date"`
+	// Value is the observed value.
+	Value float64 `json:"value"`
+	// Expected is the value predicted by the model.
+	Expected float64 `json:"expected"`
+	// ZScore is the residual divided by the model sigma.
+	ZScore float64 `json:"zScore"`
+	// Kind is "spike" for unexpectedly high and "drop" for unexpectedly low values.
+	Kind string `json:"kind"`
+}
+
+// Anomalies returns the points whose absolute z-score exceeds threshold.
+func (m *Model) Anomalies(points []Point, threshold float64) []Anomaly {
+	anomalies := []Anomaly{}
+
+	for _, point := range points {
+		expected := m.Predict(point.Date)
+		score := (point.Value - expected) / m.Sigma
+		if math.Abs(score) <= threshold {
+			continue
+		}
+
+		kind := "spike"
+		if score < 0 {
+			kind = "drop"
+		}
+
+		anomalies = append(anomalies, Anomaly{
+			Date:     dayOf(point.Date),
+			Value:    point.Value,
+			Expected: expected,
+			ZScore:   score,
+			Kind:     kind,
+		})
+	}
+
+	return anomalies
+}
+
+// Prediction is a forecast value with its confidence band.
+type Prediction struct {
+	// Date is the forecast day.
+	Date time.Time `json:"date"`
+	// Value is the expected value.
+	Value float64 `json:"value"`
+	// Lower is the lower bound of the confidence band (never below zero).
+	Lower float64 `json:"lower"`
+	// Upper is the upper bound of the confidence band.
+	Upper float64 `json:"upper"`
+}
+
+// Forecast predicts the days following the fitted series.
+// confidence is the two-sided coverage of the band, e.g. 0.95.
+func (m *Model) Forecast(days int, confidence float64) ([]Prediction, error) {
+	if days < 1 {
+		return nil, errors.Newf("forecast horizon must be positive, got %d", days)
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return nil, errors.Newf("confidence must be between 0 and 1, got %g", confidence)
+	}
+
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	n := float64(m.days - m.Outliers)
+	predictions := make([]Prediction, days)
+
+	for h := range days {
+		date := m.start.AddDate(0, 0, m.days+h)
+		value := m.Predict(date)
+
+		// Prediction interval of a linear regression widens with distance from the data
+		dt := m.dayIndex(date) - m.meanT
+		spread := 1 + 1/n
+		if m.sxx > 0 {
+			spread += dt * dt / m.sxx
+		}
+		margin := z * m.Sigma * math.Sqrt(spread)
+
+		predictions[h] = Prediction{
+			Date:  date,
+			Value: math.Max(value, 0),
+			Lower: math.Max(value-margin, 0),
+			Upper: math.Max(value+margin, 0),
+		}
+	}
+
+	return predictions, nil
+}
+
+// dayIndex returns the number of days between the first day of the series and date.
+func (m *Model) dayIndex(date time.Time) float64 {
+	return math.Round(dayOf(date).Sub(m.start).Hours() / 24)
+}
+
+// robustSigma estimates the standard deviation from the median absolute deviation,
+// falling back to the sample standard deviation and finally to minSigma.
+func robustSigma(residuals []float64) float64 {
+	if len(residuals) == 0 {
+		return minSigma
+	}
+
+	median := medianOf(residuals)
+	deviations := make([]float64, len(residuals))
+	for i, r := range residuals {
+		deviations[i] = math.Abs(r - median)
+	}
+
+	if sigma := madScale * medianOf(deviations); sigma >= minSigma {
+		return sigma
+	}
+
+	var sum, sumSq float64
+	for _, r := range residuals {
+		sum += r
+		sumSq += r * r
+	}
+	mean := sum / float64(len(residuals))
+	variance := sumSq/float64(len(residuals)) - mean*mean
+
+	return math.Max(math.Sqrt(math.Max(variance, 0)), minSigma)
+}
+
+// medianOf returns the median of values without modifying them.
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return sorted[mid]
+}
+
+// dayOf truncates t to midnight UTC.
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
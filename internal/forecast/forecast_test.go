@@ -0,0 +1,112 @@
+package forecast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/internal/forecast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weeklySeries builds a series with a linear trend, a weekend boost and a little noise.
+func weeklySeries(days int) []forecast.Point {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC) // Monday
+	noise := []float64{1, -1, 2, -2, 0, 1, -1}
+
+	points := make([]forecast.Point, days)
+	for i := range points {
+		date := start.AddDate(0, 0, i)
+		value := 100 + 2*float64(i) + noise[i%len(noise)]
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			value += 30
+		}
+		points[i] = forecast.Point{Date: date, Value: value}
+	}
+
+	return points
+}
+
+func TestFit_RecoversTrendAndSeasonality(t *testing.T) {
+	t.Parallel()
+
+	model, err := forecast.Fit(weeklySeries(56))
+	require.NoError(t, err)
+
+	assert.InDelta(t, 2.0, model.Slope, 0.05)
+	assert.Greater(t, model.Seasonal[time.Saturday], 15.0)
+	assert.Less(t, model.Seasonal[time.Wednesday], 0.0)
+	assert.Zero(t, model.Outliers)
+}
+
+func TestFit_TooFewPoints(t *testing.T) {
+	t.Parallel()
+
+	_, err := forecast.Fit(weeklySeries(10))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at least 14 days")
+}
+
+func TestModel_Anomalies(t *testing.T) {
+	t.Parallel()
+
+	points := weeklySeries(56)
+	points[30].Value += 500
+	points[45].Value = 0
+
+	model, err := forecast.Fit(points)
+	require.NoError(t, err)
+
+	anomalies := model.Anomalies(points, 3)
+
+	require.Len(t, anomalies, 2)
+	assert.Equal(t, points[30].Date, anomalies[0].Date)
+	assert.Equal(t, "spike", anomalies[0].Kind)
+	assert.Greater(t, anomalies[0].ZScore, 3.0)
+	assert.Equal(t, "drop", anomalies[1].Kind)
+	assert.Equal(t, 2, model.Outliers)
+
+	// The spike must not distort the trend
+	assert.InDelta(t, 2.0, model.Slope, 0.1)
+}
+
+func TestModel_Forecast(t *testing.T) {
+	t.Parallel()
+
+	points := weeklySeries(56)
+	model, err := forecast.Fit(points)
+	require.NoError(t, err)
+
+	predictions, err := model.Forecast(14, 0.95)
+	require.NoError(t, err)
+	require.Len(t, predictions, 14)
+
+	last := points[len(points)-1].Date
+	assert.Equal(t, last.AddDate(0, 0, 1), predictions[0].Date)
+
+	// Day 56 is a Monday: 100 + 2*56
+	assert.InDelta(t, 212, predictions[0].Value, 3)
+	for _, prediction := range predictions {
+		assert.LessOrEqual(t, prediction.Lower, prediction.Value)
+		assert.GreaterOrEqual(t, prediction.Upper, prediction.Value)
+	}
+
+	// Bands widen further into the future
+	first := predictions[0].Upper - predictions[0].Lower
+	final := predictions[13].Upper - predictions[13].Lower
+	assert.Greater(t, final, first)
+}
+
+func TestModel_Forecast_InvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	model, err := forecast.Fit(weeklySeries(28))
+	require.NoError(t, err)
+
+	_, err = model.Forecast(0, 0.95)
+	require.Error(t, err)
+
+	_, err = model.Forecast(7, 1)
+	require.Error(t, err)
+}
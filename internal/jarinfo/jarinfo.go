@@ -0,0 +1,280 @@
+// Package jarinfo reads the plugin descriptors embedded in plugin jars and matches them
+// to Hangar projects, identifying jars whose checksums are unknown to Hangar.
+package jarinfo
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"go.yaml.in/yaml/v3"
+)
+
+// ErrNoDescriptor is returned for jars without a known plugin descriptor.
+var ErrNoDescriptor = errors.New("no plugin descriptor found")
+
+// Platforms of the descriptors, named like the Hangar platforms.
+const (
+	PlatformPaper     = "PAPER"
+	PlatformWaterfall = "WATERFALL"
+	PlatformVelocity  = "VELOCITY"
+)
+
+// Metadata describes a plugin as declared by its descriptor.
+type Metadata struct {
+	// Descriptor is the descriptor file the metadata was read from, e.g. "plugin.yml".
+	Descriptor string `json:"descriptor"`
+	// Platform is the Hangar platform the descriptor belongs to, e.g. "PAPER".
+	Platform string `json:"platform"`
+	// Name is the plugin name.
+	Name string `json:"name"`
+	// Version is the plugin version.
+	Version string `json:"version"`
+	// Authors are the declared authors.
+	Authors []string `json:"authors,omitempty"`
+	// APIVersion is the minimum server API version, e.g. "1.20" (Paper only).
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Dependencies are the declared plugin dependencies, sorted by name.
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+}
+
+// Dependency is a plugin dependency declared by a descriptor.
+type Dependency struct {
+	// Name is the name of the plugin depended on.
+	Name string `json:"name"`
+	// Required reports whether the plugin fails to load without the dependency.
+	Required bool `json:"required"`
+}
+
+// descriptors are the supported descriptor files in order of preference.
+// paper-plugin.yml comes first as Paper ignores plugin.yml when both are present.
+var descriptors = []struct {
+	name     string
+	platform string
+	parse    func(data []byte, meta *Metadata) error
+}{
+	{name: "paper-plugin.yml", platform: PlatformPaper, parse: parsePaperPlugin},
+	{name: "plugin.yml", platform: PlatformPaper, parse: parseBukkitPlugin},
+	{name: "bungee.yml", platform: PlatformWaterfall, parse: parseBungeePlugin},
+	{name: "velocity-plugin.json", platform: PlatformVelocity, parse: parseVelocityPlugin},
+}
+
+// Read reads the plugin descriptor of the jar at path.
+func Read(path string) (*Metadata, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open jar")
+	}
+	defer func() { _ = archive.Close() }()
+
+	return ReadZip(&archive.Reader)
+}
+
+// ReadZip reads the plugin descriptor of an opened jar. It returns ErrNoDescriptor if the
+// jar contains none of plugin.yml, paper-plugin.yml, bungee.yml and velocity-plugin.json.
+func ReadZip(archive *zip.Reader) (*Metadata, error) {
+	for _, descriptor := range descriptors {
+		file, err := archive.Open(descriptor.name)
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", descriptor.name)
+		}
+
+		meta := &Metadata{Descriptor: descriptor.name, Platform: descriptor.platform}
+		if err := descriptor.parse(data, meta); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", descriptor.name)
+		}
+		if meta.Name == "" {
+			return nil, errors.Newf("invalid %s: missing plugin name", descriptor.name)
+		}
+		sortDependencies(meta)
+
+		return meta, nil
+	}
+
+	return nil, errors.WithStack(ErrNoDescriptor)
+}
+
+// bukkitPlugin is the format of plugin.yml.
+type bukkitPlugin struct {
+	Name       string   `yaml:"name"`
+	Version    string   `yaml:"version"`
+	Author     string   `yaml:"author"`
+	Authors    []string `yaml:"authors"`
+	APIVersion string   `yaml:"api-version"`
+	Depend     []string `yaml:"depend"`
+	SoftDepend []string `yaml:"softdepend"`
+}
+
+func parseBukkitPlugin(data []byte, meta *Metadata) error {
+	var plugin bukkitPlugin
+	if err := yaml.Unmarshal(data, &plugin); err != nil {
+		return errors.Wrap(err, "failed to parse YAML")
+	}
+
+	meta.Name = plugin.Name
+	meta.Version = plugin.Version
+	meta.Authors = authors(plugin.Author, plugin.Authors)
+	meta.APIVersion = plugin.APIVersion
+	addDependencies(meta, plugin.Depend, true)
+	addDependencies(meta, plugin.SoftDepend, false)
+
+	return nil
+}
+
+// paperPlugin is the format of paper-plugin.yml.
+type paperPlugin struct {
+	Name       string    `yaml:"name"`
+	Version    string    `yaml:"version"`
+	Author     string    `yaml:"author"`
+	Authors    []string  `yaml:"authors"`
+	APIVersion string    `yaml:"api-version"`
+	Depends    yaml.Node `yaml:"dependencies"`
+}
+
+// paperDependency is a dependency of paper-plugin.yml; Required defaults to true.
+type paperDependency struct {
+	Name     string `yaml:"name"`
+	Required *bool  `yaml:"required"`
+}
+
+func parsePaperPlugin(data []byte, meta *Metadata) error {
+	var plugin paperPlugin
+	if err := yaml.Unmarshal(data, &plugin); err != nil {
+		return errors.Wrap(err, "failed to parse YAML")
+	}
+
+	meta.Name = plugin.Name
+	meta.Version = plugin.Version
+	meta.Authors = authors(plugin.Author, plugin.Authors)
+	meta.APIVersion = plugin.APIVersion
+
+	var dependencies []paperDependency
+	switch plugin.Depends.Kind {
+	case yaml.SequenceNode:
+		// Early format: a list of dependencies
+		if err := plugin.Depends.Decode(&dependencies); err != nil {
+			return errors.Wrap(err, "invalid dependencies")
+		}
+	case yaml.MappingNode:
+		// Current format: dependencies by name, grouped into bootstrap and server
+		var groups map[string]map[string]paperDependency
+		if err := plugin.Depends.Decode(&groups); err != nil {
+			return errors.Wrap(err, "invalid dependencies")
+		}
+		for _, group := range groups {
+			for name, dependency := range group {
+				dependency.Name = name
+				dependencies = append(dependencies, dependency)
+			}
+		}
+	}
+
+	for _, dependency := range dependencies {
+		required := dependency.Required == nil || *dependency.Required
+		addDependencies(meta, []string{dependency.Name}, required)
+	}
+
+	return nil
+}
+
+// bungeePlugin is the format of bungee.yml.
+type bungeePlugin struct {
+	Name        string   `yaml:"name"`
+	Version     string   `yaml:"version"`
+	Author      string   `yaml:"author"`
+	Depends     []string `yaml:"depends"`
+	SoftDepends []string `yaml:"softDepends"`
+}
+
+func parseBungeePlugin(data []byte, meta *Metadata) error {
+	var plugin bungeePlugin
+	if err := yaml.Unmarshal(data, &plugin); err != nil {
+		return errors.Wrap(err, "failed to parse YAML")
+	}
+
+	meta.Name = plugin.Name
+	meta.Version = plugin.Version
+	meta.Authors = authors(plugin.Author, nil)
+	addDependencies(meta, plugin.Depends, true)
+	addDependencies(meta, plugin.SoftDepends, false)
+
+	return nil
+}
+
+// velocityPlugin is the format of velocity-plugin.json.
+type velocityPlugin struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Version      string   `json:"version"`
+	Authors      []string `json:"authors"`
+	Dependencies []struct {
+		ID       string `json:"id"`
+		Optional bool   `json:"optional"`
+	} `json:"dependencies"`
+}
+
+func parseVelocityPlugin(data []byte, meta *Metadata) error {
+	var plugin velocityPlugin
+	if err := json.Unmarshal(data, &plugin); err != nil {
+		return errors.Wrap(err, "failed to parse JSON")
+	}
+
+	meta.Name = plugin.Name
+	if meta.Name == "" {
+		meta.Name = plugin.ID
+	}
+	meta.Version = plugin.Version
+	meta.Authors = authors("", plugin.Authors)
+	for _, dependency := range plugin.Dependencies {
+		addDependencies(meta, []string{dependency.ID}, !dependency.Optional)
+	}
+
+	return nil
+}
+
+// authors combines the single author and author list fields, dropping empty entries.
+func authors(author string, list []string) []string {
+	var all []string
+	for _, name := range append([]string{author}, list...) {
+		if name = strings.TrimSpace(name); name != "" {
+			all = append(all, name)
+		}
+	}
+
+	return all
+}
+
+// addDependencies adds dependencies by name; a dependency declared twice is required if
+// either declaration requires it.
+func addDependencies(meta *Metadata, names []string, required bool) {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		found := false
+		for i := range meta.Dependencies {
+			if meta.Dependencies[i].Name == name {
+				meta.Dependencies[i].Required = meta.Dependencies[i].Required || required
+				found = true
+			}
+		}
+		if !found {
+			meta.Dependencies = append(meta.Dependencies, Dependency{Name: name, Required: required})
+		}
+	}
+}
+
+// sortDependencies orders the dependencies by name for stable output.
+func sortDependencies(meta *Metadata) {
+	sort.Slice(meta.Dependencies, func(i, j int) bool {
+		return meta.Dependencies[i].Name < meta.Dependencies[j].Name
+	})
+}
@@ -0,0 +1,295 @@
+package jarinfo_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/jarinfo"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildJar returns a zip archive with the given files.
+func buildJar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
+
+func readJar(t *testing.T, files map[string]string) (*jarinfo.Metadata, error) {
+	t.Helper()
+
+	data := buildJar(t, files)
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	return jarinfo.ReadZip(reader)
+}
+
+func TestReadZip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  jarinfo.Metadata
+	}{
+		{
+			name: "plugin.yml",
+			files: map[string]string{
+				"plugin.yml": `name: Essentials
+version: 2.20.1
+author: md_5
+authors: [zml, " "]
+api-version: 1.20
+main: com.earth2me.essentials.Essentials
+depend: [Vault]
+softdepend: [LuckPerms, Vault]
+`,
+			},
+			want: jarinfo.Metadata{
+				Descriptor: "plugin.yml",
+				Platform:   jarinfo.PlatformPaper,
+				Name:       "Essentials",
+				Version:    "2.20.1",
+				Authors:    []string{"md_5", "zml"},
+				APIVersion: "1.20",
+				Dependencies: []jarinfo.Dependency{
+					{Name: "LuckPerms", Required: false},
+					{Name: "Vault", Required: true},
+				},
+			},
+		},
+		{
+			name: "paper-plugin.yml takes precedence",
+			files: map[string]string{
+				"plugin.yml": "name: Legacy\nversion: 1\n",
+				"paper-plugin.yml": `name: Modern
+version: 3
+authors: [alice]
+api-version: '1.21'
+dependencies:
+  bootstrap:
+    Bootstrapper: {}
+  server:
+    Vault:
+      load: BEFORE
+      required: false
+    ProtocolLib:
+      required: true
+`,
+			},
+			want: jarinfo.Metadata{
+				Descriptor: "paper-plugin.yml",
+				Platform:   jarinfo.PlatformPaper,
+				Name:       "Modern",
+				Version:    "3",
+				Authors:    []string{"alice"},
+				APIVersion: "1.21",
+				Dependencies: []jarinfo.Dependency{
+					{Name: "Bootstrapper", Required: true},
+					{Name: "ProtocolLib", Required: true},
+					{Name: "Vault", Required: false},
+				},
+			},
+		},
+		{
+			name: "paper-plugin.yml with dependency list",
+			files: map[string]string{
+				"paper-plugin.yml": "name: Early\nversion: 0.1\ndependencies:\n  - name: Vault\n    required: false\n",
+			},
+			want: jarinfo.Metadata{
+				Descriptor:   "paper-plugin.yml",
+				Platform:     jarinfo.PlatformPaper,
+				Name:         "Early",
+				Version:      "0.1",
+				Dependencies: []jarinfo.Dependency{{Name: "Vault", Required: false}},
+			},
+		},
+		{
+			name: "bungee.yml",
+			files: map[string]string{
+				"bungee.yml": "name: Proxy\nversion: 1.0\nauthor: bob\ndepends: [Core]\nsoftDepends: [Extra]\n",
+			},
+			want: jarinfo.Metadata{
+				Descriptor: "bungee.yml",
+				Platform:   jarinfo.PlatformWaterfall,
+				Name:       "Proxy",
+				Version:    "1.0",
+				Authors:    []string{"bob"},
+				Dependencies: []jarinfo.Dependency{
+					{Name: "Core", Required: true},
+					{Name: "Extra", Required: false},
+				},
+			},
+		},
+		{
+			name: "velocity-plugin.json",
+			files: map[string]string{
+				"velocity-plugin.json": `{"id":"vproxy","version":"2.1","authors":["carol"],` +
+					`"dependencies":[{"id":"luckperms","optional":true},{"id":"core","optional":false}]}`,
+			},
+			want: jarinfo.Metadata{
+				Descriptor: "velocity-plugin.json",
+				Platform:   jarinfo.PlatformVelocity,
+				Name:       "vproxy",
+				Version:    "2.1",
+				Authors:    []string{"carol"},
+				Dependencies: []jarinfo.Dependency{
+					{Name: "core", Required: true},
+					{Name: "luckperms", Required: false},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			meta, err := readJar(t, tt.files)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *meta)
+		})
+	}
+}
+
+func TestReadZip_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := readJar(t, map[string]string{"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\n"})
+	require.ErrorIs(t, err, jarinfo.ErrNoDescriptor)
+
+	_, err = readJar(t, map[string]string{"plugin.yml": "version: 1.0\n"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing plugin name")
+
+	_, err = readJar(t, map[string]string{"plugin.yml": "name: [unclosed\n"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid plugin.yml")
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plugin.jar")
+	require.NoError(t, os.WriteFile(path, buildJar(t, map[string]string{"plugin.yml": "name: Alpha\nversion: 1.0\n"}), 0o600))
+
+	meta, err := jarinfo.Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Alpha", meta.Name)
+
+	notZip := filepath.Join(dir, "broken.jar")
+	require.NoError(t, os.WriteFile(notZip, []byte("not a zip"), 0o600))
+	_, err = jarinfo.Read(notZip)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open jar")
+}
+
+// searchClient returns fixed search results and the versions published per project.
+type searchClient struct {
+	projects []hangar.Project
+	versions map[string]*hangar.Version
+	query    string
+}
+
+func (s *searchClient) ListProjects(_ context.Context, opts hangar.ListOptions) (*hangar.ProjectsList, error) {
+	s.query = opts.Query
+	return &hangar.ProjectsList{Result: s.projects}, nil
+}
+
+func (s *searchClient) GetVersion(_ context.Context, slug, version string) (*hangar.Version, error) {
+	if found, ok := s.versions[slug+"/"+version]; ok {
+		return found, nil
+	}
+	return nil, errors.WithStack(&hangar.APIError{StatusCode: http.StatusNotFound})
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	project := func(name, owner, slug string) hangar.Project {
+		return hangar.Project{Name: name, Namespace: hangar.Namespace{Owner: owner, Slug: slug}}
+	}
+	client := &searchClient{
+		projects: []hangar.Project{
+			project("EssentialsX Chat", "EssentialsX", "EssentialsXChat"),
+			project("Unrelated", "someone", "Unrelated"),
+			project("EssentialsX", "EssentialsX", "Essentials"),
+		},
+		versions: map[string]*hangar.Version{
+			"Essentials/2.20.1": {Name: "2.20.1", Downloads: map[string]hangar.DownloadInfo{"PAPER": {}}},
+		},
+	}
+	meta := &jarinfo.Metadata{Platform: jarinfo.PlatformPaper, Name: "Essentials-X", Version: "2.20.1", Authors: []string{"essentialsx"}}
+
+	candidates, err := jarinfo.Match(context.Background(), client, meta)
+	require.NoError(t, err)
+	assert.Equal(t, "Essentials-X", client.query)
+
+	require.Len(t, candidates, 2, "projects with an unrelated name are dropped")
+	assert.Equal(t, "Essentials", candidates[0].Project.Namespace.Slug)
+	assert.InDelta(t, 1.0, candidates[0].Confidence, 0.001)
+	assert.Equal(t, []string{"name matches project name", "author matches owner", "version 2.20.1 is published for PAPER"},
+		candidates[0].Reasons)
+	require.NotNil(t, candidates[0].Version)
+
+	assert.Equal(t, "EssentialsXChat", candidates[1].Project.Namespace.Slug)
+	assert.InDelta(t, 0.55, candidates[1].Confidence, 0.001)
+	assert.Nil(t, candidates[1].Version)
+
+	// A version published for another platform does not count
+	meta.Platform = jarinfo.PlatformVelocity
+	candidates, err = jarinfo.Match(context.Background(), client, meta)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.8, candidates[0].Confidence, 0.001)
+}
+
+func TestMatch_ShortNames(t *testing.T) {
+	t.Parallel()
+
+	project := func(name, slug string) hangar.Project {
+		return hangar.Project{Name: name, Namespace: hangar.Namespace{Owner: "someone", Slug: slug}}
+	}
+	client := &searchClient{
+		projects: []hangar.Project{
+			project("★★★", "Stars"),
+			project("E", "E"),
+			project("WorldEdit", "WorldEdit"),
+			project("Essentials Chat", "EssentialsChat"),
+		},
+	}
+
+	candidates, err := jarinfo.Match(context.Background(), client, &jarinfo.Metadata{Name: "Essentials"})
+	require.NoError(t, err)
+	require.Len(t, candidates, 1, "symbol-only and short project names do not resemble the plugin name")
+	assert.Equal(t, "EssentialsChat", candidates[0].Project.Namespace.Slug)
+
+	candidates, err = jarinfo.Match(context.Background(), client, &jarinfo.Metadata{Name: "Ed"})
+	require.NoError(t, err)
+	assert.Empty(t, candidates, "a short plugin name does not resemble longer project names")
+
+	client.query = ""
+	candidates, err = jarinfo.Match(context.Background(), client, &jarinfo.Metadata{Name: "---"})
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+	assert.Empty(t, client.query, "a name without letters or digits is not searched")
+}
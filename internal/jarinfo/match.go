@@ -0,0 +1,136 @@
+package jarinfo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+)
+
+// DefaultMinConfidence is the confidence a match needs to be trusted: a matching name
+// confirmed by the owner or a published version of the same name.
+const DefaultMinConfidence = 0.7
+
+// searchLimit is the number of search results considered as candidates.
+const searchLimit = 10
+
+// minPartialName is the length both normalized names need for one containing the other to count.
+// Shorter names are contained in too many unrelated ones.
+const minPartialName = 3
+
+// Points of the confidence score, out of 100.
+const (
+	pointsExactName   = 50
+	pointsPartialName = 25
+	pointsAuthor      = 30
+	pointsVersion     = 20
+)
+
+// Client is the part of the Hangar API used to match metadata to projects.
+type Client interface {
+	ListProjects(ctx context.Context, opts hangar.ListOptions) (*hangar.ProjectsList, error)
+	GetVersion(ctx context.Context, slug, versionNameOrID string) (*hangar.Version, error)
+}
+
+// Candidate is a Hangar project that may be the plugin described by the metadata.
+type Candidate struct {
+	// Project is the matched project.
+	Project hangar.Project `json:"project"`
+	// Version is the published version named like the plugin version, if any.
+	Version *hangar.Version `json:"version,omitempty"`
+	// Confidence is the match score from 0 to 1.
+	Confidence float64 `json:"confidence"`
+	// Reasons explain the score, e.g. "name matches slug".
+	Reasons []string `json:"reasons"`
+}
+
+// Match searches Hangar for the plugin name and scores the results by how well their name,
+// owner and published versions agree with the metadata. Candidates are sorted best first;
+// projects whose name does not resemble the plugin name are dropped.
+func Match(ctx context.Context, client Client, meta *Metadata) ([]Candidate, error) {
+	// A name without letters or digits resembles nothing
+	name := normalize(meta.Name)
+	candidates := []Candidate{}
+	if name == "" {
+		return candidates, nil
+	}
+
+	list, err := client.ListProjects(ctx, hangar.ListOptions{Query: meta.Name, Limit: searchLimit})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search projects")
+	}
+
+	for _, project := range list.Result {
+		points := 0
+		var reasons []string
+
+		projectName := normalize(project.Name)
+		switch {
+		case name == normalize(project.Namespace.Slug):
+			points += pointsExactName
+			reasons = append(reasons, "name matches slug")
+		case name == projectName:
+			points += pointsExactName
+			reasons = append(reasons, "name matches project name")
+		case len(name) >= minPartialName && len(projectName) >= minPartialName &&
+			(strings.Contains(projectName, name) || strings.Contains(name, projectName)):
+			points += pointsPartialName
+			reasons = append(reasons, "name resembles project name")
+		default:
+			continue
+		}
+
+		for _, author := range meta.Authors {
+			if normalize(author) == normalize(project.Namespace.Owner) {
+				points += pointsAuthor
+				reasons = append(reasons, "author matches owner")
+				break
+			}
+		}
+
+		candidate := Candidate{Project: project}
+		if meta.Version != "" {
+			version, err := client.GetVersion(ctx, project.Namespace.Slug, meta.Version)
+			switch {
+			case hangar.IsNotFound(err):
+			case err != nil:
+				return nil, errors.Wrapf(err, "failed to get version of %s", project.Namespace.Slug)
+			case hasPlatform(version, meta.Platform):
+				candidate.Version = version
+				points += pointsVersion
+				reasons = append(reasons, "version "+meta.Version+" is published for "+meta.Platform)
+			}
+		}
+
+		candidate.Confidence = float64(points) / 100
+		candidate.Reasons = reasons
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+
+	return candidates, nil
+}
+
+// hasPlatform reports whether a version has a download for the platform.
+func hasPlatform(version *hangar.Version, platform string) bool {
+	_, ok := version.Downloads[platform]
+
+	return ok
+}
+
+// normalize lowercases s and drops everything but letters and digits, so that
+// "Essentials-X", "essentials_x" and "EssentialsX" compare equal.
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
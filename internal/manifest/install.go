@@ -0,0 +1,113 @@
+package manifest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/scan"
+	"golang.org/x/sync/errgroup"
+)
+
+// Actions taken for a locked plugin by Install.
+const (
+	// ActionInstalled means the artifact was downloaded.
+	ActionInstalled = "installed"
+	// ActionUnchanged means the installed file already had the locked checksum.
+	ActionUnchanged = "unchanged"
+)
+
+// Downloader downloads artifacts.
+type Downloader interface {
+	Download(ctx context.Context, downloadURL string, w io.Writer) (int64, error)
+}
+
+// InstallResult is the outcome of installing a locked plugin.
+type InstallResult struct {
+	// Slug is the project slug.
+	Slug string `json:"slug"`
+	// Version is the locked version.
+	Version string `json:"version"`
+	// File is the installed file name.
+	File string `json:"file"`
+	// Action is ActionInstalled or ActionUnchanged.
+	Action string `json:"action"`
+}
+
+// Install places the locked artifacts in dir, which is created if needed. Files that
+// already have the locked checksum are kept; all others are downloaded and verified before
+// they replace the installed file. Files not in the lock are left alone.
+func Install(ctx context.Context, client Downloader, lock *Lock, dir string, concurrency int) ([]InstallResult, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "failed to create plugin directory")
+	}
+
+	results := make([]InstallResult, len(lock.Plugins))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for i, plugin := range lock.Plugins {
+		group.Go(func() error {
+			result := InstallResult{Slug: plugin.Slug, Version: plugin.Version, File: plugin.File, Action: ActionUnchanged}
+			if !Installed(dir, plugin) {
+				if err := Fetch(groupCtx, client, plugin, dir); err != nil {
+					return err
+				}
+				result.Action = ActionInstalled
+			}
+			results[i] = result
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Installed reports whether the file of a locked plugin exists in dir with the locked checksum.
+func Installed(dir string, plugin LockedPlugin) bool {
+	hash, err := scan.HashFile(filepath.Join(dir, plugin.File))
+
+	return err == nil && hash == plugin.SHA256
+}
+
+// Fetch downloads the artifact of a locked plugin into dir. The download is written to a
+// temporary file and only renamed into place once its checksum matches the lock.
+func Fetch(ctx context.Context, client Downloader, plugin LockedPlugin, dir string) error {
+	if err := plugin.validate(); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(dir, "."+plugin.File+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer func() { _ = os.Remove(temp.Name()) }()
+
+	hash := sha256.New()
+	_, err = client.Download(ctx, plugin.URL, io.MultiWriter(temp, hash))
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to download %s %s", plugin.Slug, plugin.Version)
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != plugin.SHA256 {
+		return errors.Newf("checksum mismatch for %s %s: expected %s, got %s", plugin.Slug, plugin.Version, plugin.SHA256, sum)
+	}
+
+	if err := os.Chmod(temp.Name(), 0o644); err != nil {
+		return errors.Wrap(err, "failed to set file mode")
+	}
+
+	return errors.Wrapf(os.Rename(temp.Name(), filepath.Join(dir, plugin.File)), "failed to install %s", plugin.File)
+}
@@ -0,0 +1,320 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/sync/errgroup"
+)
+
+// LockVersion is the format version of lockfiles written by this package.
+const LockVersion = 1
+
+// DefaultConcurrency is the number of plugins resolved or downloaded at the same time by default.
+const DefaultConcurrency = 4
+
+// lockHeader is written above the content of lockfiles.
+const lockHeader = "# Generated by hangar lock. Do not edit.\n"
+
+// Lock pins every plugin of a manifest to an exact artifact.
+type Lock struct {
+	// Version is the lockfile format version.
+	Version int `yaml:"version" json:"version"`
+	// ManifestDigest is the Digest of the manifest the lock was resolved from.
+	ManifestDigest string `yaml:"manifest" json:"manifest"`
+	// MinecraftVersion is the game version the plugins were resolved for, if any.
+	MinecraftVersion string `yaml:"minecraft,omitempty" json:"minecraft,omitempty"`
+	// Plugins are the locked plugins, sorted by slug.
+	Plugins []LockedPlugin `yaml:"plugins" json:"plugins"`
+}
+
+// LockedPlugin is the exact artifact of a plugin.
+type LockedPlugin struct {
+	// Slug is the Hangar project slug.
+	Slug string `yaml:"slug" json:"slug"`
+	// Owner is the project owner.
+	Owner string `yaml:"owner" json:"owner"`
+	// Version is the locked version name.
+	Version string `yaml:"version" json:"version"`
+	// Channel is the release channel of the version.
+	Channel string `yaml:"channel" json:"channel"`
+	// Platform is the platform of the artifact, e.g. "PAPER".
+	Platform string `yaml:"platform" json:"platform"`
+	// File is the file name the artifact is installed as.
+	File string `yaml:"file" json:"file"`
+	// URL is the download URL of the artifact.
+	URL string `yaml:"url" json:"url"`
+	// SHA256 is the hex-encoded SHA-256 checksum of the artifact.
+	SHA256 string `yaml:"sha256" json:"sha256"`
+	// Size is the size of the artifact in bytes, if known.
+	Size int64 `yaml:"size,omitempty" json:"size,omitempty"`
+}
+
+// Client is the part of the Hangar API used to resolve and install plugins.
+type Client interface {
+	GetProject(ctx context.Context, slug string) (*hangar.Project, error)
+	ListAllVersions(ctx context.Context, owner, slug string) ([]hangar.Version, error)
+	Download(ctx context.Context, downloadURL string, w io.Writer) (int64, error)
+}
+
+// LoadLock reads and validates a lockfile.
+func LoadLock(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read lockfile")
+	}
+
+	var lock Lock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse lockfile %s", path)
+	}
+	if lock.Version != LockVersion {
+		return nil, errors.Newf("unsupported lockfile version %d in %s (expected %d)", lock.Version, path, LockVersion)
+	}
+
+	for i := range lock.Plugins {
+		lock.Plugins[i].SHA256 = strings.ToLower(lock.Plugins[i].SHA256)
+		if err := lock.Plugins[i].validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid lockfile %s", path)
+		}
+	}
+
+	return &lock, nil
+}
+
+// validate checks that the plugin can be installed safely.
+func (p LockedPlugin) validate() error {
+	switch {
+	case p.Slug == "":
+		return errors.New("plugin without slug")
+	case p.File == "" || p.File != filepath.Base(p.File) || p.File == ".." || strings.ContainsAny(p.File, `/\`):
+		return errors.Newf("%s: invalid file name %q", p.Slug, p.File)
+	case p.URL == "":
+		return errors.Newf("%s: missing download URL", p.Slug)
+	case !validChecksum(p.SHA256):
+		return errors.Newf("%s: invalid SHA-256 checksum %q", p.Slug, p.SHA256)
+	default:
+		return nil
+	}
+}
+
+// validChecksum reports whether sum is a lowercase hex SHA-256 checksum, the form computed
+// when verifying downloads.
+func validChecksum(sum string) bool {
+	if len(sum) != sha256.Size*2 || sum != strings.ToLower(sum) {
+		return false
+	}
+	_, err := hex.DecodeString(sum)
+
+	return err == nil
+}
+
+// Save writes the lockfile atomically.
+func (l *Lock) Save(path string) error {
+	var buf bytes.Buffer
+	buf.WriteString(lockHeader)
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(l); err != nil {
+		return errors.Wrap(err, "failed to encode lockfile")
+	}
+	if err := encoder.Close(); err != nil {
+		return errors.Wrap(err, "failed to encode lockfile")
+	}
+
+	return writeFileAtomic(path, buf.Bytes(), 0o644)
+}
+
+// Stale reports whether the manifest changed since the lock was resolved.
+func (l *Lock) Stale(manifest *Manifest) bool {
+	return l.ManifestDigest != manifest.Digest()
+}
+
+// Resolve locks every plugin of the manifest to the highest version that matches its
+// constraint, channel, platform and the Minecraft version of the manifest (see Pin).
+func Resolve(ctx context.Context, client Client, manifest *Manifest, concurrency int) (*Lock, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	plugins := make([]LockedPlugin, len(manifest.Plugins))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for i, requirement := range manifest.Plugins {
+		group.Go(func() error {
+			locked, err := resolvePlugin(groupCtx, client, manifest, requirement)
+			if err != nil {
+				return errors.Wrapf(err, "failed to resolve %s", requirement.Slug)
+			}
+			// Never write a lock that LoadLock would reject
+			if err := locked.validate(); err != nil {
+				return errors.Wrapf(err, "failed to resolve %s", requirement.Slug)
+			}
+			plugins[i] = *locked
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return strings.ToLower(plugins[i].Slug) < strings.ToLower(plugins[j].Slug)
+	})
+
+	return &Lock{
+		Version:          LockVersion,
+		ManifestDigest:   manifest.Digest(),
+		MinecraftVersion: manifest.MinecraftVersion,
+		Plugins:          plugins,
+	}, nil
+}
+
+// resolvePlugin locks a single plugin.
+func resolvePlugin(ctx context.Context, client Client, manifest *Manifest, requirement Requirement) (*LockedPlugin, error) {
+	constraint, err := ParseConstraint(requirement.Version)
+	if err != nil {
+		return nil, err
+	}
+	platform := manifest.PlatformOf(requirement)
+	channel := ChannelOf(requirement)
+
+	project, err := client.GetProject(ctx, requirement.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := client.ListAllVersions(ctx, project.Namespace.Owner, project.Namespace.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	version := SelectVersion(versions, constraint, channel, platform, manifest.MinecraftVersion)
+	if version == nil {
+		return nil, errors.Newf("no %s version %s for %s%s", channel, constraint, platform, minecraftSuffix(manifest.MinecraftVersion))
+	}
+
+	return Pin(ctx, client, project, version, platform)
+}
+
+// Pin locks a version of a project to its artifact for the platform. Artifacts hosted
+// outside Hangar carry no checksum, so they are downloaded once to compute it.
+func Pin(ctx context.Context, client Downloader, project *hangar.Project, version *hangar.Version, platform string) (*LockedPlugin, error) {
+	downloadURL, ok := version.DownloadURL(platform)
+	if !ok {
+		return nil, errors.Newf("%s %s has no download for %s", project.Namespace.Slug, version.Name, platform)
+	}
+
+	locked := &LockedPlugin{
+		Slug:     project.Namespace.Slug,
+		Owner:    project.Namespace.Owner,
+		Version:  version.Name,
+		Channel:  version.Channel.Name,
+		Platform: platform,
+		File:     version.FileName(project.Namespace.Slug, platform, downloadURL),
+		URL:      downloadURL,
+	}
+
+	if info := version.Downloads[platform].FileInfo; info != nil && info.SHA256Hash != "" {
+		locked.SHA256 = strings.ToLower(info.SHA256Hash)
+		locked.Size = info.SizeBytes
+		return locked, nil
+	}
+
+	hash := sha256.New()
+	size, err := client.Download(ctx, downloadURL, hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download artifact to compute its checksum")
+	}
+	locked.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	locked.Size = size
+
+	return locked, nil
+}
+
+// SelectVersion returns the highest version in the channel that has a download for the
+// platform, supports the Minecraft version (if not empty) and satisfies the constraint.
+// Versions with equal names are ordered by publication date. It returns nil if none match.
+func SelectVersion(versions []hangar.Version, constraint Constraint, channel, platform, minecraftVersion string) *hangar.Version {
+	var best *hangar.Version
+	for i := range versions {
+		version := &versions[i]
+
+		if _, ok := version.DownloadURL(platform); !ok {
+			continue
+		}
+		if channel != "" && !strings.EqualFold(version.Channel.Name, channel) {
+			continue
+		}
+		if minecraftVersion != "" && !Supports(version, platform, minecraftVersion) {
+			continue
+		}
+		if !constraint.Allows(version.Name) {
+			continue
+		}
+
+		if best == nil {
+			best = version
+			continue
+		}
+		result := CompareVersions(version.Name, best.Name)
+		if result > 0 || (result == 0 && version.CreatedAt.After(best.CreatedAt)) {
+			best = version
+		}
+	}
+
+	return best
+}
+
+// Supports reports whether a version lists the Minecraft version for the platform.
+func Supports(version *hangar.Version, platform, minecraftVersion string) bool {
+	for _, supported := range version.PlatformDependencies[platform] {
+		if supported == minecraftVersion {
+			return true
+		}
+	}
+
+	return false
+}
+
+// minecraftSuffix describes the Minecraft version in error messages.
+func minecraftSuffix(minecraftVersion string) string {
+	if minecraftVersion == "" {
+		return ""
+	}
+
+	return " on Minecraft " + minecraftVersion
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer func() { _ = os.Remove(temp.Name()) }()
+
+	if _, err := temp.Write(data); err != nil {
+		_ = temp.Close()
+		return errors.Wrap(err, "failed to write temporary file")
+	}
+	if err := temp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write temporary file")
+	}
+	if err := os.Chmod(temp.Name(), perm); err != nil {
+		return errors.Wrap(err, "failed to set file mode")
+	}
+
+	return errors.Wrapf(os.Rename(temp.Name(), path), "failed to write %s", path)
+}
@@ -0,0 +1,146 @@
+// Package manifest declares the plugins of a server in a manifest (hangar.yaml), resolves
+// them into a lockfile of exact artifacts (hangar.lock) and installs the locked artifacts.
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// DefaultManifestFile is the file name of the manifest.
+	DefaultManifestFile = "hangar.yaml"
+	// DefaultLockFile is the file name of the lockfile.
+	DefaultLockFile = "hangar.lock"
+	// DefaultPlatform is the platform of plugins that declare none.
+	DefaultPlatform = "PAPER"
+	// DefaultChannel is the release channel of plugins that declare none.
+	DefaultChannel = "Release"
+)
+
+// Manifest declares the plugins of a server.
+type Manifest struct {
+	// Platform is the default platform of the plugins, e.g. "PAPER" (defaults to DefaultPlatform).
+	Platform string `yaml:"platform,omitempty" json:"platform,omitempty"`
+	// MinecraftVersion limits plugin versions to those supporting the game version, e.g. "1.21.1".
+	MinecraftVersion string `yaml:"minecraft,omitempty" json:"minecraft,omitempty"`
+	// Plugins are the declared plugins.
+	Plugins []Requirement `yaml:"plugins" json:"plugins"`
+}
+
+// Requirement declares a plugin and the versions it may be locked to.
+type Requirement struct {
+	// Slug is the Hangar project slug.
+	Slug string `yaml:"slug" json:"slug"`
+	// Version is a version constraint, e.g. "2.20.1", ">=2.20, <3" or "~2.20" (any version if empty).
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// Channel is the release channel (defaults to DefaultChannel).
+	Channel string `yaml:"channel,omitempty" json:"channel,omitempty"`
+	// Platform overrides the platform of the manifest.
+	Platform string `yaml:"platform,omitempty" json:"platform,omitempty"`
+}
+
+// LoadManifest reads and validates a manifest file.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+
+	var manifest Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse manifest %s", path)
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid manifest %s", path)
+	}
+
+	return &manifest, nil
+}
+
+// Validate checks that every plugin is declared once with a valid constraint and platform.
+func (m *Manifest) Validate() error {
+	var problems []string
+
+	if m.Platform != "" && !knownPlatform(m.Platform) {
+		problems = append(problems, "unknown platform "+m.Platform)
+	}
+
+	seen := make(map[string]bool, len(m.Plugins))
+	for i, plugin := range m.Plugins {
+		switch {
+		case plugin.Slug == "":
+			problems = append(problems, fmt.Sprintf("plugin %d has no slug", i+1))
+			continue
+		case seen[strings.ToLower(plugin.Slug)]:
+			problems = append(problems, plugin.Slug+" is declared more than once")
+		}
+		seen[strings.ToLower(plugin.Slug)] = true
+
+		if _, err := ParseConstraint(plugin.Version); err != nil {
+			problems = append(problems, plugin.Slug+": "+err.Error())
+		}
+		if plugin.Platform != "" && !knownPlatform(plugin.Platform) {
+			problems = append(problems, plugin.Slug+": unknown platform "+plugin.Platform)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// PlatformOf returns the platform a plugin is resolved for.
+func (m *Manifest) PlatformOf(plugin Requirement) string {
+	switch {
+	case plugin.Platform != "":
+		return strings.ToUpper(plugin.Platform)
+	case m.Platform != "":
+		return strings.ToUpper(m.Platform)
+	default:
+		return DefaultPlatform
+	}
+}
+
+// ChannelOf returns the release channel a plugin is resolved from.
+func ChannelOf(plugin Requirement) string {
+	if plugin.Channel != "" {
+		return plugin.Channel
+	}
+
+	return DefaultChannel
+}
+
+// Digest returns a checksum of the manifest content, recorded in the lockfile to detect
+// manifests changed after locking.
+func (m *Manifest) Digest() string {
+	data, _ := json.Marshal(m)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// knownPlatform reports whether platform is a Hangar platform, ignoring case.
+func knownPlatform(platform string) bool {
+	for _, known := range hangar.KnownPlatforms {
+		if strings.EqualFold(platform, known) {
+			return true
+		}
+	}
+
+	return false
+}
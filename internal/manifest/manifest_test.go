@@ -0,0 +1,414 @@
+package manifest_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/manifest"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sum returns the hex-encoded SHA-256 checksum of content.
+func sum(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+// fakeClient serves projects, versions and artifacts from memory.
+type fakeClient struct {
+	projects  map[string]hangar.Project
+	versions  map[string][]hangar.Version
+	artifacts map[string]string
+
+	mu        sync.Mutex
+	downloads []string
+}
+
+func (f *fakeClient) GetProject(_ context.Context, slug string) (*hangar.Project, error) {
+	project, ok := f.projects[slug]
+	if !ok {
+		return nil, errors.Newf("project %s not found", slug)
+	}
+	return &project, nil
+}
+
+func (f *fakeClient) ListAllVersions(_ context.Context, _, slug string) ([]hangar.Version, error) {
+	return f.versions[slug], nil
+}
+
+func (f *fakeClient) Download(_ context.Context, downloadURL string, w io.Writer) (int64, error) {
+	f.mu.Lock()
+	f.downloads = append(f.downloads, downloadURL)
+	f.mu.Unlock()
+
+	content, ok := f.artifacts[downloadURL]
+	if !ok {
+		return 0, errors.Newf("download failed with status 404")
+	}
+	written, err := io.Copy(w, strings.NewReader(content))
+	return written, err
+}
+
+// hosted returns a version with a Hangar-hosted PAPER download.
+func hosted(name, channel string, minecraft ...string) hangar.Version {
+	return hangar.Version{
+		Name:    name,
+		Channel: hangar.Channel{Name: channel},
+		Downloads: map[string]hangar.DownloadInfo{"PAPER": {
+			DownloadURL: "https://hangar.test/alpha/" + name,
+			FileInfo:    &hangar.FileInfo{Name: "Alpha-" + name + ".jar", SizeBytes: 5, SHA256Hash: sum("alpha " + name)},
+		}},
+		PlatformDependencies: map[string][]string{"PAPER": minecraft},
+	}
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		projects: map[string]hangar.Project{
+			"alpha": {Namespace: hangar.Namespace{Owner: "alice", Slug: "Alpha"}},
+			"beta":  {Namespace: hangar.Namespace{Owner: "bob", Slug: "Beta"}},
+		},
+		versions: map[string][]hangar.Version{
+			"Alpha": {
+				hosted("2.1-beta", "Beta", "1.21.1"),
+				hosted("2.0", "Release", "1.21", "1.21.1"),
+				hosted("1.9", "Release", "1.20.4"),
+				hosted("1.10", "Release", "1.20.4", "1.21"),
+			},
+			"Beta": {{
+				Name:      "3.0",
+				Channel:   hangar.Channel{Name: "Release"},
+				Downloads: map[string]hangar.DownloadInfo{"PAPER": {ExternalURL: "https://cdn.test/beta.jar?v=3"}},
+			}},
+		},
+		artifacts: map[string]string{
+			"https://hangar.test/alpha/2.0":  "alpha 2.0",
+			"https://hangar.test/alpha/1.10": "alpha 1.10",
+			"https://cdn.test/beta.jar?v=3":  "beta 3.0",
+		},
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, manifest.DefaultManifestFile)
+	require.NoError(t, os.WriteFile(path, []byte(`platform: paper
+minecraft: 1.21.1
+plugins:
+  - slug: alpha
+    version: ">=2.0, <3"
+  - slug: beta
+    channel: Beta
+    platform: VELOCITY
+`), 0o600))
+
+	loaded, err := manifest.LoadManifest(path)
+	require.NoError(t, err)
+	assert.Equal(t, "1.21.1", loaded.MinecraftVersion)
+	require.Len(t, loaded.Plugins, 2)
+	assert.Equal(t, "PAPER", loaded.PlatformOf(loaded.Plugins[0]))
+	assert.Equal(t, "Release", manifest.ChannelOf(loaded.Plugins[0]))
+	assert.Equal(t, "VELOCITY", loaded.PlatformOf(loaded.Plugins[1]))
+	assert.Equal(t, "Beta", manifest.ChannelOf(loaded.Plugins[1]))
+
+	invalid := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "unknown field", content: "plugins:\n  - slug: alpha\n    versoin: 1.0\n", want: "field versoin not found"},
+		{name: "missing slug", content: "plugins:\n  - version: 1.0\n", want: "plugin 1 has no slug"},
+		{name: "duplicate", content: "plugins:\n  - slug: alpha\n  - slug: Alpha\n", want: "Alpha is declared more than once"},
+		{name: "bad constraint", content: "plugins:\n  - slug: alpha\n    version: '>='\n", want: "invalid version constraint"},
+		{name: "bad platform", content: "platform: FORGE\nplugins: []\n", want: "unknown platform FORGE"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), manifest.DefaultManifestFile)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			_, err := manifest.LoadManifest(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+	m := &manifest.Manifest{Plugins: []manifest.Requirement{{Slug: "beta"}, {Slug: "alpha"}}}
+
+	lock, err := manifest.Resolve(context.Background(), client, m, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, manifest.LockVersion, lock.Version)
+	assert.Equal(t, m.Digest(), lock.ManifestDigest)
+	assert.False(t, lock.Stale(m))
+	assert.Equal(t, []manifest.LockedPlugin{
+		{
+			Slug: "Alpha", Owner: "alice", Version: "2.0", Channel: "Release", Platform: "PAPER",
+			File: "Alpha-2.0.jar", URL: "https://hangar.test/alpha/2.0", SHA256: sum("alpha 2.0"), Size: 5,
+		},
+		{
+			Slug: "Beta", Owner: "bob", Version: "3.0", Channel: "Release", Platform: "PAPER",
+			File: "beta.jar", URL: "https://cdn.test/beta.jar?v=3", SHA256: sum("beta 3.0"), Size: 8,
+		},
+	}, lock.Plugins)
+	assert.Equal(t, []string{"https://cdn.test/beta.jar?v=3"}, client.downloads, "only external artifacts are downloaded")
+
+	m.Plugins = append(m.Plugins, manifest.Requirement{Slug: "gamma"})
+	assert.True(t, lock.Stale(m))
+}
+
+func TestSelectVersion(t *testing.T) {
+	t.Parallel()
+
+	versions := newFakeClient().versions["Alpha"]
+
+	tests := []struct {
+		name       string
+		constraint string
+		channel    string
+		minecraft  string
+		want       string
+	}{
+		{name: "highest release", want: "2.0", channel: "Release"},
+		{name: "numeric ordering", constraint: "<2", channel: "Release", want: "1.10"},
+		{name: "minecraft version", channel: "Release", minecraft: "1.20.4", want: "1.10"},
+		{name: "beta channel", channel: "beta", want: "2.1-beta"},
+		{name: "no match", constraint: ">=3", channel: "Release"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			constraint, err := manifest.ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+
+			version := manifest.SelectVersion(versions, constraint, tt.channel, "PAPER", tt.minecraft)
+			if tt.want == "" {
+				assert.Nil(t, version)
+				return
+			}
+			require.NotNil(t, version)
+			assert.Equal(t, tt.want, version.Name)
+		})
+	}
+}
+
+func TestResolve_NoMatchingVersion(t *testing.T) {
+	t.Parallel()
+
+	m := &manifest.Manifest{MinecraftVersion: "1.19", Plugins: []manifest.Requirement{{Slug: "alpha", Version: "~2.0"}}}
+
+	_, err := manifest.Resolve(context.Background(), newFakeClient(), m, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to resolve alpha: no Release version ~2.0 for PAPER on Minecraft 1.19")
+}
+
+func TestLock_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, manifest.DefaultLockFile)
+	lock := &manifest.Lock{
+		Version:        manifest.LockVersion,
+		ManifestDigest: "digest",
+		Plugins: []manifest.LockedPlugin{{
+			Slug: "Alpha", Owner: "alice", Version: "2.0", Channel: "Release", Platform: "PAPER",
+			File: "Alpha-2.0.jar", URL: "https://hangar.test/alpha/2.0", SHA256: sum("alpha 2.0"),
+		}},
+	}
+
+	require.NoError(t, lock.Save(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# Generated by hangar lock"))
+
+	loaded, err := manifest.LoadLock(path)
+	require.NoError(t, err)
+	assert.Equal(t, lock, loaded)
+
+	invalid := []struct {
+		name   string
+		modify func(lock *manifest.Lock)
+		want   string
+	}{
+		{name: "version", modify: func(l *manifest.Lock) { l.Version = 99 }, want: "unsupported lockfile version 99"},
+		{name: "path traversal", modify: func(l *manifest.Lock) { l.Plugins[0].File = "../evil.jar" }, want: "invalid file name"},
+		{name: "checksum", modify: func(l *manifest.Lock) { l.Plugins[0].SHA256 = "abc" }, want: "invalid SHA-256 checksum"},
+		{
+			name:   "checksum not hex",
+			modify: func(l *manifest.Lock) { l.Plugins[0].SHA256 = strings.Repeat("zz", 32) },
+			want:   "invalid SHA-256 checksum",
+		},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			broken := *lock
+			broken.Plugins = append([]manifest.LockedPlugin{}, lock.Plugins...)
+			tt.modify(&broken)
+
+			path := filepath.Join(t.TempDir(), manifest.DefaultLockFile)
+			require.NoError(t, broken.Save(path))
+
+			_, err := manifest.LoadLock(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoadLock_UppercaseChecksum(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), manifest.DefaultLockFile)
+	lock := &manifest.Lock{
+		Version: manifest.LockVersion,
+		Plugins: []manifest.LockedPlugin{{
+			Slug: "Alpha", File: "Alpha-2.0.jar", URL: "https://hangar.test/alpha/2.0", SHA256: strings.ToUpper(sum("alpha 2.0")),
+		}},
+	}
+	require.NoError(t, lock.Save(path))
+
+	loaded, err := manifest.LoadLock(path)
+	require.NoError(t, err)
+	assert.Equal(t, sum("alpha 2.0"), loaded.Plugins[0].SHA256)
+
+	dir := t.TempDir()
+	require.NoError(t, manifest.Fetch(context.Background(), newFakeClient(), loaded.Plugins[0], dir))
+	assert.FileExists(t, filepath.Join(dir, "Alpha-2.0.jar"))
+}
+
+func TestResolve_ValidatesPins(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		fileInfo hangar.FileInfo
+		wantFile string
+		wantErr  string
+	}{
+		{
+			name:     "uppercase checksum",
+			fileInfo: hangar.FileInfo{Name: "Alpha-2.0.jar", SHA256Hash: strings.ToUpper(sum("alpha 2.0"))},
+			wantFile: "Alpha-2.0.jar",
+		},
+		{
+			name:     "backslash in file name",
+			fileInfo: hangar.FileInfo{Name: `build\libs\Alpha-2.0.jar`, SHA256Hash: sum("alpha 2.0")},
+			wantFile: "Alpha-2.0.jar",
+		},
+		{
+			name:     "invalid checksum",
+			fileInfo: hangar.FileInfo{Name: "Alpha-2.0.jar", SHA256Hash: "not-a-checksum"},
+			wantErr:  `failed to resolve alpha: Alpha: invalid SHA-256 checksum "not-a-checksum"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newFakeClient()
+			version := hosted("2.0", "Release", "1.21")
+			version.Downloads["PAPER"] = hangar.DownloadInfo{DownloadURL: "https://hangar.test/alpha/2.0", FileInfo: &tt.fileInfo}
+			client.versions["Alpha"] = []hangar.Version{version}
+
+			lock, err := manifest.Resolve(context.Background(), client, &manifest.Manifest{Plugins: []manifest.Requirement{{Slug: "alpha"}}}, 0)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFile, lock.Plugins[0].File)
+			assert.Equal(t, sum("alpha 2.0"), lock.Plugins[0].SHA256)
+
+			path := filepath.Join(t.TempDir(), manifest.DefaultLockFile)
+			require.NoError(t, lock.Save(path))
+			_, err = manifest.LoadLock(path)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestInstall(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+	lock, err := manifest.Resolve(context.Background(), client, &manifest.Manifest{
+		Plugins: []manifest.Requirement{{Slug: "alpha"}, {Slug: "beta"}},
+	}, 0)
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "plugins")
+	results, err := manifest.Install(context.Background(), client, lock, dir, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []manifest.InstallResult{
+		{Slug: "Alpha", Version: "2.0", File: "Alpha-2.0.jar", Action: manifest.ActionInstalled},
+		{Slug: "Beta", Version: "3.0", File: "beta.jar", Action: manifest.ActionInstalled},
+	}, results)
+
+	content, err := os.ReadFile(filepath.Join(dir, "Alpha-2.0.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, "alpha 2.0", string(content))
+
+	// A second install keeps verified files and replaces modified ones
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "beta.jar"), []byte("patched"), 0o600))
+	results, err = manifest.Install(context.Background(), client, lock, dir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.ActionUnchanged, results[0].Action)
+	assert.Equal(t, manifest.ActionInstalled, results[1].Action)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "no temporary files are left behind")
+}
+
+func TestFetch_ChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeClient()
+	client.artifacts["https://hangar.test/alpha/2.0"] = "tampered"
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Alpha-2.0.jar"), []byte("previous"), 0o600))
+
+	plugin := manifest.LockedPlugin{
+		Slug: "Alpha", Version: "2.0", File: "Alpha-2.0.jar",
+		URL: "https://hangar.test/alpha/2.0", SHA256: sum("alpha 2.0"),
+	}
+	err := manifest.Fetch(context.Background(), client, plugin, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch for Alpha 2.0")
+
+	content, err := os.ReadFile(filepath.Join(dir, "Alpha-2.0.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(content), "the installed file is kept")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,242 @@
+package manifest
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/scan"
+	"golang.org/x/sync/errgroup"
+)
+
+// Steps of a sync plan.
+const (
+	// SyncKeep keeps a file that already has the locked checksum.
+	SyncKeep = "keep"
+	// SyncDownload downloads a locked plugin that is not installed.
+	SyncDownload = "download"
+	// SyncReplace replaces a file whose checksum differs from the lock.
+	SyncReplace = "replace"
+	// SyncRemove deletes a jar that is not in the lock.
+	SyncRemove = "remove"
+	// SyncQuarantine moves a jar that is not in the lock to the quarantine directory.
+	SyncQuarantine = "quarantine"
+)
+
+// SyncStep is a single change, or a kept file, of a sync plan.
+type SyncStep struct {
+	// Action is one of the Sync constants.
+	Action string `json:"action"`
+	// File is the jar file name in the plugin directory.
+	File string `json:"file"`
+	// Slug is the project slug of locked plugins.
+	Slug string `json:"slug,omitempty"`
+	// Version is the locked version of locked plugins.
+	Version string `json:"version,omitempty"`
+}
+
+// SyncPlan lists the steps that make a plugin directory match a lock.
+type SyncPlan struct {
+	// Dir is the plugin directory.
+	Dir string `json:"dir"`
+	// Quarantine is the directory undeclared jars are moved to, or empty to delete them.
+	Quarantine string `json:"quarantine,omitempty"`
+	// Steps are the locked plugins in lock order, followed by the undeclared jars.
+	Steps []SyncStep `json:"steps"`
+}
+
+// Changes returns the number of steps that change the plugin directory.
+func (p *SyncPlan) Changes() int {
+	changes := 0
+	for _, step := range p.Steps {
+		if step.Action != SyncKeep {
+			changes++
+		}
+	}
+
+	return changes
+}
+
+// PlanSync compares the jars in dir with the lock. Locked plugins are kept, downloaded or
+// replaced depending on the checksum of the installed file; any other jar is removed, or
+// moved to the quarantine directory if it is not empty. A missing dir is treated as empty.
+func PlanSync(lock *Lock, dir, quarantine string) (*SyncPlan, error) {
+	plan := &SyncPlan{Dir: dir, Quarantine: quarantine}
+
+	locked := make(map[string]bool, len(lock.Plugins))
+	for _, plugin := range lock.Plugins {
+		if err := plugin.validate(); err != nil {
+			return nil, err
+		}
+		if locked[plugin.File] {
+			return nil, errors.Newf("%s: file %s is locked more than once", plugin.Slug, plugin.File)
+		}
+		locked[plugin.File] = true
+
+		step := SyncStep{Action: SyncKeep, File: plugin.File, Slug: plugin.Slug, Version: plugin.Version}
+		hash, err := scan.HashFile(filepath.Join(dir, plugin.File))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			step.Action = SyncDownload
+		case err != nil:
+			return nil, err
+		case hash != plugin.SHA256:
+			step.Action = SyncReplace
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	jars, err := scan.Jars(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	action := SyncRemove
+	if quarantine != "" {
+		action = SyncQuarantine
+	}
+	for _, jar := range jars {
+		if !locked[jar] {
+			plan.Steps = append(plan.Steps, SyncStep{Action: action, File: jar})
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplySync carries out a plan created by PlanSync for the same lock. All downloads are
+// verified and in place before undeclared jars are removed, so a failed sync leaves the
+// previously installed plugins untouched.
+func ApplySync(ctx context.Context, client Downloader, lock *Lock, plan *SyncPlan, concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if err := os.MkdirAll(plan.Dir, 0o755); err != nil {
+		return errors.Wrap(err, "failed to create plugin directory")
+	}
+
+	plugins := make(map[string]LockedPlugin, len(lock.Plugins))
+	for _, plugin := range lock.Plugins {
+		plugins[plugin.File] = plugin
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for _, step := range plan.Steps {
+		if step.Action != SyncDownload && step.Action != SyncReplace {
+			continue
+		}
+		plugin, ok := plugins[step.File]
+		if !ok {
+			return errors.Newf("%s is not in the lock", step.File)
+		}
+		group.Go(func() error {
+			return Fetch(groupCtx, client, plugin, plan.Dir)
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	for _, step := range plan.Steps {
+		if err := removeUndeclared(plan, step); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeUndeclared deletes or quarantines the jar of a remove or quarantine step.
+func removeUndeclared(plan *SyncPlan, step SyncStep) error {
+	path := filepath.Join(plan.Dir, step.File)
+
+	switch step.Action {
+	case SyncRemove:
+		return errors.Wrapf(os.Remove(path), "failed to remove %s", step.File)
+	case SyncQuarantine:
+		if err := os.MkdirAll(plan.Quarantine, 0o755); err != nil {
+			return errors.Wrap(err, "failed to create quarantine directory")
+		}
+		return errors.Wrapf(moveFile(path, quarantinePath(plan.Quarantine, step.File, time.Now())),
+			"failed to quarantine %s", step.File)
+	default:
+		return nil
+	}
+}
+
+// quarantinePath returns a path in the quarantine directory that is not taken yet. A jar
+// quarantined earlier under the same name is kept by adding the time, and a counter if
+// needed, to the new name: "Plugin-1.0.20240102T150405Z.jar".
+func quarantinePath(dir, file string, now time.Time) string {
+	path := filepath.Join(dir, file)
+	if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+		return path
+	}
+
+	ext := filepath.Ext(file)
+	base := strings.TrimSuffix(file, ext) + "." + now.UTC().Format("20060102T150405Z")
+	path = filepath.Join(dir, base+ext)
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+			return path
+		}
+		path = filepath.Join(dir, base+"-"+strconv.Itoa(i)+ext)
+	}
+}
+
+// moveFile renames src to dst, copying and removing src when they are on different
+// filesystems, e.g. a quarantine directory on another volume.
+func moveFile(src, dst string) error {
+	err := os.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+
+	return os.Remove(src)
+}
+
+// copyFile copies src to dst through a temporary file so dst never holds a partial copy.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(temp.Name()) }()
+
+	if _, err := io.Copy(temp, in); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err := temp.Chmod(info.Mode().Perm()); err != nil {
+		_ = temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), dst)
+}
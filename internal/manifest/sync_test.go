@@ -0,0 +1,175 @@
+package manifest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lexfrei/go-hangar/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncLock returns a lock of the alpha and beta artifacts served by newFakeClient.
+func syncLock() *manifest.Lock {
+	return &manifest.Lock{
+		Version: manifest.LockVersion,
+		Plugins: []manifest.LockedPlugin{
+			{Slug: "Alpha", Version: "2.0", File: "Alpha-2.0.jar", URL: "https://hangar.test/alpha/2.0", SHA256: sum("alpha 2.0")},
+			{Slug: "Beta", Version: "3.0", File: "beta.jar", URL: "https://cdn.test/beta.jar?v=3", SHA256: sum("beta 3.0")},
+		},
+	}
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		quarantine bool
+		want       []manifest.SyncStep
+	}{
+		{
+			name: "remove",
+			want: []manifest.SyncStep{
+				{Action: manifest.SyncReplace, File: "Alpha-2.0.jar", Slug: "Alpha", Version: "2.0"},
+				{Action: manifest.SyncKeep, File: "beta.jar", Slug: "Beta", Version: "3.0"},
+				{Action: manifest.SyncRemove, File: "Alpha-1.10.jar"},
+			},
+		},
+		{
+			name:       "quarantine",
+			quarantine: true,
+			want: []manifest.SyncStep{
+				{Action: manifest.SyncReplace, File: "Alpha-2.0.jar", Slug: "Alpha", Version: "2.0"},
+				{Action: manifest.SyncKeep, File: "beta.jar", Slug: "Beta", Version: "3.0"},
+				{Action: manifest.SyncQuarantine, File: "Alpha-1.10.jar"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			quarantine := ""
+			if tt.quarantine {
+				quarantine = filepath.Join(dir, ".quarantine")
+			}
+			files := map[string]string{
+				"Alpha-2.0.jar":  "modified",
+				"beta.jar":       "beta 3.0",
+				"Alpha-1.10.jar": "alpha 1.10",
+				"config.yml":     "kept",
+			}
+			for name, content := range files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+			}
+
+			lock := syncLock()
+			plan, err := manifest.PlanSync(lock, dir, quarantine)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, plan.Steps)
+			assert.Equal(t, 2, plan.Changes())
+
+			require.NoError(t, manifest.ApplySync(context.Background(), newFakeClient(), lock, plan, 2))
+
+			content, err := os.ReadFile(filepath.Join(dir, "Alpha-2.0.jar"))
+			require.NoError(t, err)
+			assert.Equal(t, "alpha 2.0", string(content))
+			assert.NoFileExists(t, filepath.Join(dir, "Alpha-1.10.jar"))
+			assert.FileExists(t, filepath.Join(dir, "config.yml"), "only jars are synced")
+			if tt.quarantine {
+				assert.FileExists(t, filepath.Join(quarantine, "Alpha-1.10.jar"))
+			}
+
+			// Syncing again changes nothing
+			plan, err = manifest.PlanSync(lock, dir, quarantine)
+			require.NoError(t, err)
+			assert.Zero(t, plan.Changes())
+		})
+	}
+}
+
+func TestSync_QuarantineKeepsEarlierJars(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	quarantine := filepath.Join(dir, ".quarantine")
+	require.NoError(t, os.MkdirAll(quarantine, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(quarantine, "Alpha-1.10.jar"), []byte("quarantined before"), 0o600))
+
+	lock := syncLock()
+	for _, content := range []string{"alpha 1.10", "alpha 1.10 again"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "Alpha-1.10.jar"), []byte(content), 0o600))
+
+		plan, err := manifest.PlanSync(lock, dir, quarantine)
+		require.NoError(t, err)
+		require.NoError(t, manifest.ApplySync(context.Background(), newFakeClient(), lock, plan, 1))
+	}
+
+	entries, err := os.ReadDir(quarantine)
+	require.NoError(t, err)
+
+	contents := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		assert.Equal(t, ".jar", filepath.Ext(entry.Name()))
+		content, err := os.ReadFile(filepath.Join(quarantine, entry.Name()))
+		require.NoError(t, err)
+		contents = append(contents, string(content))
+	}
+	assert.ElementsMatch(t, []string{"quarantined before", "alpha 1.10", "alpha 1.10 again"}, contents)
+
+	content, err := os.ReadFile(filepath.Join(quarantine, "Alpha-1.10.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, "quarantined before", string(content))
+}
+
+func TestSync_MissingDirectory(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "plugins")
+	lock := syncLock()
+
+	plan, err := manifest.PlanSync(lock, dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, plan.Changes())
+	assert.Equal(t, manifest.SyncDownload, plan.Steps[0].Action)
+
+	require.NoError(t, manifest.ApplySync(context.Background(), newFakeClient(), lock, plan, 0))
+	assert.FileExists(t, filepath.Join(dir, "Alpha-2.0.jar"))
+	assert.FileExists(t, filepath.Join(dir, "beta.jar"))
+}
+
+func TestSync_FailedDownloadKeepsPlugins(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Alpha-1.10.jar"), []byte("alpha 1.10"), 0o600))
+
+	client := newFakeClient()
+	client.artifacts["https://cdn.test/beta.jar?v=3"] = "tampered"
+
+	lock := syncLock()
+	plan, err := manifest.PlanSync(lock, dir, "")
+	require.NoError(t, err)
+
+	err = manifest.ApplySync(context.Background(), client, lock, plan, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch for Beta 3.0")
+	assert.FileExists(t, filepath.Join(dir, "Alpha-1.10.jar"), "undeclared jars are only removed after all downloads")
+	assert.NoFileExists(t, filepath.Join(dir, "beta.jar"))
+}
+
+func TestPlanSync_DuplicateFile(t *testing.T) {
+	t.Parallel()
+
+	lock := syncLock()
+	lock.Plugins[1].File = lock.Plugins[0].File
+
+	_, err := manifest.PlanSync(lock, t.TempDir(), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "file Alpha-2.0.jar is locked more than once")
+}
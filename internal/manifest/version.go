@@ -0,0 +1,252 @@
+package manifest
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// CompareVersions compares two plugin version names and returns -1, 0 or 1.
+//
+// Versions are compared by their dot-separated parts, numerically where possible, so that
+// 1.10 > 1.9 and 1.2 == 1.2.0. A leading "v" and build metadata after "+" are ignored.
+// A pre-release suffix after "-", e.g. 2.0-beta1 or 2.0-SNAPSHOT, sorts before the release.
+func CompareVersions(a, b string) int {
+	aRelease, aPre := splitVersion(a)
+	bRelease, bPre := splitVersion(b)
+
+	if result := compareParts(aRelease, bRelease); result != 0 {
+		return result
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	default:
+		return compareParts(aPre, bPre)
+	}
+}
+
+// splitVersion splits a version into its release and pre-release parts.
+func splitVersion(version string) (release, pre string) {
+	version = strings.TrimSpace(version)
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	version, _, _ = strings.Cut(version, "+")
+	release, pre, _ = strings.Cut(version, "-")
+
+	return release, pre
+}
+
+// compareParts compares dot-separated parts; missing parts count as zero.
+func compareParts(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := range max(len(aParts), len(bParts)) {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		if result := comparePart(aPart, bPart); result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+// comparePart compares two parts run by run, numerically for runs of digits and
+// case-insensitively for text, so that beta2 < beta10.
+func comparePart(a, b string) int {
+	for a != "" || b != "" {
+		var aRun, bRun string
+		aRun, a = nextRun(a)
+		bRun, b = nextRun(b)
+
+		aNumber, aErr := strconv.Atoi(aRun)
+		bNumber, bErr := strconv.Atoi(bRun)
+		switch {
+		case aErr == nil && bErr == nil && aNumber != bNumber:
+			if aNumber < bNumber {
+				return -1
+			}
+			return 1
+		case aErr == nil && bErr == nil:
+			continue
+		case aErr == nil:
+			// Numbers sort after text and missing runs
+			return 1
+		case bErr == nil:
+			return -1
+		}
+		if result := strings.Compare(strings.ToLower(aRun), strings.ToLower(bRun)); result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+// nextRun splits off the leading run of digits or of other characters.
+func nextRun(part string) (run, rest string) {
+	if part == "" {
+		return "", ""
+	}
+
+	digits := isDigit(part[0])
+	end := 1
+	for end < len(part) && isDigit(part[end]) == digits {
+		end++
+	}
+
+	return part[:end], part[end:]
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// leadingNumber returns the leading digits of a part as a number, or -1 if there are none.
+func leadingNumber(part string) int {
+	run, _ := nextRun(part)
+	number, err := strconv.Atoi(run)
+	if err != nil {
+		return -1
+	}
+
+	return number
+}
+
+// Constraint is a set of version requirements that must all hold, parsed from
+// a comma-separated list such as ">=2.20, <3".
+type Constraint struct {
+	text        string
+	comparators []comparator
+}
+
+// comparator is a single requirement, e.g. ">= 2.20".
+type comparator struct {
+	op      string
+	version string
+	// release compares only the release part, so that "< 2.21" excludes 2.21-beta too
+	release bool
+}
+
+// operators are the supported comparison operators, longest first for parsing.
+var operators = []string{">=", "<=", "!=", "=", ">", "<", "~", "^"}
+
+// ParseConstraint parses a version constraint. Supported forms are exact versions ("2.20.1"
+// or "=2.20.1"), comparisons (">=2.20", "<3", "!=2.21"), tilde ranges ("~2.20" allows 2.20.x),
+// caret ranges ("^2.20" allows 2.x from 2.20) and "*" or an empty string for any version.
+func ParseConstraint(text string) (Constraint, error) {
+	constraint := Constraint{text: strings.TrimSpace(text)}
+	if constraint.text == "" || constraint.text == "*" {
+		return constraint, nil
+	}
+
+	for _, raw := range strings.Split(constraint.text, ",") {
+		raw = strings.TrimSpace(raw)
+		op := ""
+		for _, candidate := range operators {
+			if strings.HasPrefix(raw, candidate) {
+				op = candidate
+				break
+			}
+		}
+		version := strings.TrimSpace(strings.TrimPrefix(raw, op))
+		if version == "" || strings.ContainsAny(version, " <>=!~^*") {
+			return Constraint{}, errors.Newf("invalid version constraint %q", text)
+		}
+
+		switch op {
+		case "~", "^":
+			lower, upper := rangeBounds(op, version)
+			constraint.comparators = append(constraint.comparators,
+				comparator{op: ">=", version: lower}, comparator{op: "<", version: upper, release: true})
+		default:
+			constraint.comparators = append(constraint.comparators, comparator{op: op, version: version})
+		}
+	}
+
+	return constraint, nil
+}
+
+// rangeBounds returns the bounds of a tilde or caret range. The upper bound is a release
+// to compare with the release part of versions.
+func rangeBounds(op, version string) (lower, upper string) {
+	release, _ := splitVersion(version)
+	parts := strings.Split(release, ".")
+
+	// ~ bumps the minor version (or the major version if there is none);
+	// ^ bumps the first non-zero part
+	bump := 0
+	switch op {
+	case "~":
+		bump = min(1, len(parts)-1)
+	case "^":
+		for bump < len(parts)-1 {
+			if leadingNumber(parts[bump]) > 0 {
+				break
+			}
+			bump++
+		}
+	}
+
+	next := append(append([]string{}, parts[:bump]...), strconv.Itoa(max(leadingNumber(parts[bump]), 0)+1))
+
+	return version, strings.Join(next, ".")
+}
+
+// Any reports whether the constraint allows every version.
+func (c Constraint) Any() bool {
+	return len(c.comparators) == 0
+}
+
+// Allows reports whether version satisfies every requirement of the constraint.
+func (c Constraint) Allows(version string) bool {
+	for _, comparator := range c.comparators {
+		result := CompareVersions(version, comparator.version)
+		if comparator.release {
+			release, _ := splitVersion(version)
+			result = compareParts(release, comparator.version)
+		}
+		var ok bool
+		switch comparator.op {
+		case "", "=":
+			ok = result == 0
+		case "!=":
+			ok = result != 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		case "<":
+			ok = result < 0
+		case "<=":
+			ok = result <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the constraint as written.
+func (c Constraint) String() string {
+	if c.text == "" {
+		return "*"
+	}
+
+	return c.text
+}
@@ -0,0 +1,82 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/lexfrei/go-hangar/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.10", b: "1.9", want: 1},
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "v2.0", b: "2.0", want: 0},
+		{a: "2.0+build.5", b: "2.0", want: 0},
+		{a: "2.0-beta1", b: "2.0", want: -1},
+		{a: "2.0-beta2", b: "2.0-beta10", want: -1},
+		{a: "2.0-SNAPSHOT", b: "1.9", want: 1},
+		{a: "5.4.145", b: "5.4.99", want: 1},
+		{a: "1.0a", b: "1.0b", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, manifest.CompareVersions(tt.a, tt.b))
+			assert.Equal(t, -tt.want, manifest.CompareVersions(tt.b, tt.a))
+		})
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		constraint string
+		allowed    []string
+		denied     []string
+	}{
+		{constraint: "", allowed: []string{"0.1", "99-SNAPSHOT"}},
+		{constraint: "*", allowed: []string{"1.0"}},
+		{constraint: "2.20.1", allowed: []string{"2.20.1", "v2.20.1"}, denied: []string{"2.20.2", "2.20"}},
+		{constraint: "=2.20", allowed: []string{"2.20.0"}, denied: []string{"2.20.1"}},
+		{constraint: ">=2.20, <3", allowed: []string{"2.20", "2.99.1"}, denied: []string{"2.19.9", "3.0"}},
+		{constraint: ">1.0,<=1.2,!=1.1", allowed: []string{"1.0.1", "1.2"}, denied: []string{"1.0", "1.1", "1.2.1"}},
+		{constraint: "~2.20", allowed: []string{"2.20", "2.20.9"}, denied: []string{"2.21", "2.21-beta", "2.19"}},
+		{constraint: "~2", allowed: []string{"2.0", "2.9"}, denied: []string{"3.0"}},
+		{constraint: "^2.20", allowed: []string{"2.20", "2.99"}, denied: []string{"3.0-beta", "2.19"}},
+		{constraint: "^0.3.1", allowed: []string{"0.3.1", "0.3.9"}, denied: []string{"0.4.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			t.Parallel()
+
+			constraint, err := manifest.ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+			for _, version := range tt.allowed {
+				assert.True(t, constraint.Allows(version), "%s allows %s", tt.constraint, version)
+			}
+			for _, version := range tt.denied {
+				assert.False(t, constraint.Allows(version), "%s denies %s", tt.constraint, version)
+			}
+		})
+	}
+
+	for _, invalid := range []string{">=", "1.0,", ">= 1.0 <2", "~>1.0"} {
+		_, err := manifest.ParseConstraint(invalid)
+		require.Error(t, err, invalid)
+	}
+
+	constraint, err := manifest.ParseConstraint("")
+	require.NoError(t, err)
+	assert.True(t, constraint.Any())
+	assert.Equal(t, "*", constraint.String())
+}
@@ -0,0 +1,589 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// JSONPath is a parsed kubectl-style JSONPath template such as
+// `{.stats.downloads}` or `{range .result[*]}{.name}{"\n"}{end}`.
+//
+// Supported expressions: child fields (`.name`, `['name']`), recursive descent (`..name`),
+// wildcards (`.*`, `[*]`), indexes and slices (`[0]`, `[-1]`, `[1:3]`), filters
+// (`[?(@.stats.downloads > 100)]`), string literals and range/end blocks.
+// Paths inside a range block are relative to the current element; `$` refers to the root.
+type JSONPath struct {
+	nodes []jsonPathNode
+}
+
+// jsonPathNode is a piece of a template: literal text, a path expression or a range block.
+type jsonPathNode struct {
+	text  string
+	path  []pathSegment
+	body  []jsonPathNode
+	isRng bool
+	isExp bool
+}
+
+// pathSegment is a single step of a path.
+type pathSegment struct {
+	kind   segmentKind
+	name   string
+	index  int
+	start  *int
+	end    *int
+	filter *pathFilter
+}
+
+type segmentKind int
+
+const (
+	segmentField segmentKind = iota
+	segmentRecursive
+	segmentWildcard
+	segmentIndex
+	segmentSlice
+	segmentFilter
+	segmentRoot
+)
+
+// pathFilter is a `[?(@.path op value)]` predicate; an empty op tests for existence.
+type pathFilter struct {
+	path  []pathSegment
+	op    string
+	value any
+}
+
+// ParseJSONPath parses a JSONPath template.
+func ParseJSONPath(template string) (*JSONPath, error) {
+	nodes, _, err := parseJSONPathNodes(template, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid jsonpath")
+	}
+
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// Execute evaluates the template against data, which must be JSON-like
+// (maps, slices, strings, json.Number, bools and nil).
+func (j *JSONPath) Execute(w io.Writer, data any) error {
+	return executeJSONPath(w, j.nodes, data, data)
+}
+
+// parseJSONPathNodes parses until the end of input or, inside a range block, until {end}.
+// It returns the unparsed remainder after {end}.
+func parseJSONPathNodes(template string, inRange bool) ([]jsonPathNode, string, error) {
+	var nodes []jsonPathNode
+
+	for template != "" {
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{text: template})
+			template = ""
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{text: template[:open]})
+		}
+
+		closing, err := matchingBrace(template, open)
+		if err != nil {
+			return nil, "", err
+		}
+		expr := strings.TrimSpace(template[open+1 : closing])
+		template = template[closing+1:]
+
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, "", errors.New("{end} without {range}")
+			}
+			return nodes, template, nil
+		case strings.HasPrefix(expr, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			body, rest, err := parseJSONPathNodes(template, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{isRng: true, path: path, body: body})
+			template = rest
+		case strings.HasPrefix(expr, `"`) || strings.HasPrefix(expr, "'"):
+			text, err := unquote(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{text: text})
+		default:
+			path, err := parsePath(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{isExp: true, path: path})
+		}
+	}
+
+	if inRange {
+		return nil, "", errors.New("{range} without {end}")
+	}
+
+	return nodes, "", nil
+}
+
+// matchingBrace returns the index of the brace closing the one at open, skipping quoted strings.
+func matchingBrace(s string, open int) (int, error) {
+	depth := 0
+	var quote byte
+
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, errors.Newf("unclosed { at position %d", open)
+}
+
+// parsePath parses a path expression such as `.result[*].namespace.slug`.
+func parsePath(expr string) ([]pathSegment, error) {
+	var segments []pathSegment
+
+	switch {
+	case strings.HasPrefix(expr, "$"):
+		segments = append(segments, pathSegment{kind: segmentRoot})
+		expr = expr[1:]
+	case strings.HasPrefix(expr, "@"):
+		expr = expr[1:]
+	}
+
+	for expr != "" {
+		switch {
+		case strings.HasPrefix(expr, ".."):
+			name, rest := readName(expr[2:])
+			if name == "" {
+				return nil, errors.Newf("expected field name after .. in %q", expr)
+			}
+			segments = append(segments, pathSegment{kind: segmentRecursive, name: name})
+			expr = rest
+		case strings.HasPrefix(expr, ".*"):
+			segments = append(segments, pathSegment{kind: segmentWildcard})
+			expr = expr[2:]
+		case strings.HasPrefix(expr, "."):
+			name, rest := readName(expr[1:])
+			if name != "" {
+				segments = append(segments, pathSegment{kind: segmentField, name: name})
+			}
+			expr = rest
+		case strings.HasPrefix(expr, "["):
+			end := strings.IndexByte(expr, ']')
+			if strings.HasPrefix(expr, "[?(") {
+				end = strings.Index(expr, ")]") + 1
+			}
+			if end <= 0 {
+				return nil, errors.Newf("unclosed [ in %q", expr)
+			}
+			segment, err := parseBracket(expr[1:end])
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment)
+			expr = expr[end+1:]
+		default:
+			return nil, errors.Newf("unexpected %q in path", expr)
+		}
+	}
+
+	return segments, nil
+}
+
+// readName reads a field name up to the next separator.
+func readName(s string) (string, string) {
+	end := strings.IndexAny(s, ".[ ")
+	if end < 0 {
+		return s, ""
+	}
+
+	return s[:end], s[end:]
+}
+
+// parseBracket parses the content of a [...] segment.
+func parseBracket(content string) (pathSegment, error) {
+	content = strings.TrimSpace(content)
+
+	switch {
+	case content == "*":
+		return pathSegment{kind: segmentWildcard}, nil
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, `"`):
+		name, err := unquote(content)
+		if err != nil {
+			return pathSegment{}, err
+		}
+		return pathSegment{kind: segmentField, name: name}, nil
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		filter, err := parseFilter(strings.TrimSpace(content[2 : len(content)-1]))
+		if err != nil {
+			return pathSegment{}, err
+		}
+		return pathSegment{kind: segmentFilter, filter: filter}, nil
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 2)
+		segment := pathSegment{kind: segmentSlice}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return pathSegment{}, errors.Newf("invalid slice bound %q", part)
+			}
+			if i == 0 {
+				segment.start = &n
+			} else {
+				segment.end = &n
+			}
+		}
+		return segment, nil
+	default:
+		n, err := strconv.Atoi(content)
+		if err != nil {
+			return pathSegment{}, errors.Newf("invalid index %q", content)
+		}
+		return pathSegment{kind: segmentIndex, index: n}, nil
+	}
+}
+
+// filterOperators are checked longest first so that "<=" is not read as "<".
+var filterOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// parseFilter parses a filter predicate such as `@.stats.downloads > 100`.
+func parseFilter(expr string) (*pathFilter, error) {
+	for _, op := range filterOperators {
+		left, right, found := strings.Cut(expr, op)
+		if !found {
+			continue
+		}
+
+		path, err := parsePath(strings.TrimSpace(left))
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := parseLiteral(strings.TrimSpace(right))
+		if err != nil {
+			return nil, err
+		}
+
+		return &pathFilter{path: path, op: op, value: value}, nil
+	}
+
+	path, err := parsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pathFilter{path: path}, nil
+}
+
+// parseLiteral parses a quoted string, number, boolean or null.
+func parseLiteral(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		return unquote(s)
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null":
+		return nil, nil
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, errors.Newf("invalid literal %q", s)
+	}
+
+	return n, nil
+}
+
+// unquote removes single or double quotes, interpreting escape sequences.
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") && len(s) >= 2 {
+		s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+	}
+
+	text, err := strconv.Unquote(s)
+	if err != nil {
+		return "", errors.Newf("invalid string literal %s", s)
+	}
+
+	return text, nil
+}
+
+// executeJSONPath writes the nodes evaluated against the current element.
+func executeJSONPath(w io.Writer, nodes []jsonPathNode, root, current any) error {
+	for _, node := range nodes {
+		switch {
+		case node.isRng:
+			for _, item := range evaluatePath(node.path, root, current) {
+				if err := executeJSONPath(w, node.body, root, item); err != nil {
+					return err
+				}
+			}
+		case node.isExp:
+			values := evaluatePath(node.path, root, current)
+			texts := make([]string, len(values))
+			for i, value := range values {
+				texts[i] = jsonPathText(value)
+			}
+			if _, err := io.WriteString(w, strings.Join(texts, " ")); err != nil {
+				return errors.Wrap(err, "failed to write output")
+			}
+		default:
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return errors.Wrap(err, "failed to write output")
+			}
+		}
+	}
+
+	return nil
+}
+
+// evaluatePath returns all values matched by path. Missing fields yield no values.
+func evaluatePath(path []pathSegment, root, current any) []any {
+	values := []any{current}
+
+	for _, segment := range path {
+		var next []any
+		for _, value := range values {
+			next = append(next, applySegment(segment, root, value)...)
+		}
+		values = next
+	}
+
+	return values
+}
+
+// applySegment applies one path step to a single value.
+func applySegment(segment pathSegment, root, value any) []any {
+	switch segment.kind {
+	case segmentRoot:
+		return []any{root}
+	case segmentField:
+		if object, ok := value.(map[string]any); ok {
+			if child, ok := object[segment.name]; ok {
+				return []any{child}
+			}
+		}
+		return nil
+	case segmentRecursive:
+		return recursiveFields(value, segment.name)
+	case segmentWildcard:
+		return children(value)
+	case segmentIndex:
+		list, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		index := segment.index
+		if index < 0 {
+			index += len(list)
+		}
+		if index < 0 || index >= len(list) {
+			return nil
+		}
+		return []any{list[index]}
+	case segmentSlice:
+		list, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		start, end := sliceBounds(segment, len(list))
+		if start >= end {
+			return nil
+		}
+		return list[start:end]
+	case segmentFilter:
+		var matched []any
+		for _, child := range children(value) {
+			if segment.filter.matches(root, child) {
+				matched = append(matched, child)
+			}
+		}
+		return matched
+	default:
+		return nil
+	}
+}
+
+// sliceBounds resolves negative and missing slice bounds.
+func sliceBounds(segment pathSegment, length int) (int, int) {
+	resolve := func(bound *int, fallback int) int {
+		if bound == nil {
+			return fallback
+		}
+		n := *bound
+		if n < 0 {
+			n += length
+		}
+		return min(max(n, 0), length)
+	}
+
+	return resolve(segment.start, 0), resolve(segment.end, length)
+}
+
+// children returns the elements of a list or the values of an object in key order.
+func children(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		values := make([]any, len(keys))
+		for i, key := range keys {
+			values[i] = v[key]
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// recursiveFields returns the values of all fields named name at any depth.
+func recursiveFields(value any, name string) []any {
+	var found []any
+	if object, ok := value.(map[string]any); ok {
+		if child, ok := object[name]; ok {
+			found = append(found, child)
+		}
+	}
+
+	for _, child := range children(value) {
+		found = append(found, recursiveFields(child, name)...)
+	}
+
+	return found
+}
+
+// matches evaluates the filter against an element.
+func (f *pathFilter) matches(root, element any) bool {
+	values := evaluatePath(f.path, root, element)
+	if len(values) == 0 {
+		return false
+	}
+	if f.op == "" {
+		return true
+	}
+
+	left := values[0]
+	if number, ok := left.(json.Number); ok {
+		l, err := number.Float64()
+		r, isNumber := f.value.(float64)
+		if err != nil || !isNumber {
+			return f.op == "!="
+		}
+		return compareNumbers(l, r, f.op)
+	}
+
+	switch f.op {
+	case "==":
+		return fmt.Sprint(left) == fmt.Sprint(f.value)
+	case "!=":
+		return fmt.Sprint(left) != fmt.Sprint(f.value)
+	}
+
+	l, lok := left.(string)
+	r, rok := f.value.(string)
+	if !lok || !rok {
+		return false
+	}
+
+	return compareNumbers(float64(strings.Compare(l, r)), 0, f.op)
+}
+
+// compareNumbers applies a comparison operator.
+func compareNumbers(l, r float64, op string) bool {
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	default:
+		return false
+	}
+}
+
+// jsonPathText formats a matched value: scalars as plain text, objects and lists as compact JSON.
+func jsonPathText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		_ = encoder.Encode(v)
+		return strings.TrimSuffix(buf.String(), "\n")
+	}
+}
+
+// toJSONValue converts data to the generic representation used by JSONPath.
+func toJSONValue(data any) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode JSON")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, errors.Wrap(err, "failed to decode JSON")
+	}
+
+	return value, nil
+}
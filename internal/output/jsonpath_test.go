@@ -0,0 +1,86 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonPathProject struct {
+	Name      string            `json:"name"`
+	Namespace map[string]string `json:"namespace"`
+	Stats     map[string]int    `json:"stats"`
+	Tags      []string          `json:"tags"`
+}
+
+var jsonPathData = map[string]any{
+	"pagination": map[string]int{"count": 3},
+	"result": []jsonPathProject{
+		{Name: "Alpha", Namespace: map[string]string{"owner": "alice"}, Stats: map[string]int{"downloads": 500}, Tags: []string{"chat"}},
+		{Name: "Beta", Namespace: map[string]string{"owner": "bob"}, Stats: map[string]int{"downloads": 1500}},
+		{Name: "Gamma", Namespace: map[string]string{"owner": "alice"}, Stats: map[string]int{"downloads": 2500}},
+	},
+}
+
+func TestJSONPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "field", template: "{.pagination.count}", want: "3"},
+		{name: "root", template: "{$.result[0].name}", want: "Alpha"},
+		{name: "wildcard", template: "{.result[*].name}", want: "Alpha Beta Gamma"},
+		{name: "negative index", template: "{.result[-1].name}", want: "Gamma"},
+		{name: "slice", template: "{.result[0:2].name}", want: "Alpha Beta"},
+		{name: "bracket field", template: "{.result[1]['namespace'].owner}", want: "bob"},
+		{name: "recursive", template: "{..owner}", want: "alice bob alice"},
+		{name: "filter number", template: "{.result[?(@.stats.downloads > 1000)].name}", want: "Beta Gamma"},
+		{name: "filter string", template: `{.result[?(@.namespace.owner == "alice")].name}`, want: "Alpha Gamma"},
+		{name: "filter exists", template: "{.result[?(@.tags)].name}", want: "Alpha Beta Gamma"},
+		{name: "object", template: "{.result[0].stats}", want: `{"downloads":500}`},
+		{name: "null", template: "{.result[1].tags}", want: ""},
+		{name: "missing", template: "{.result[0].missing}", want: ""},
+		{
+			name:     "range",
+			template: `{range .result[*]}{.name}{"\t"}{.stats.downloads}{"\n"}{end}`,
+			want:     "Alpha\t500\nBeta\t1500\nGamma\t2500\n",
+		},
+		{name: "text", template: "count={.pagination.count}!", want: "count=3!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			err := output.Render(&buf,
+				output.Options{Format: output.FormatJSONPath, Template: tt.template},
+				output.View[jsonPathProject]{Data: jsonPathData})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestParseJSONPath_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, template := range []string{
+		"{.name",
+		"{range .result[*]}{.name}",
+		"{end}",
+		"{.result[abc]}",
+		"{.result[0}",
+		`{"unterminated}`,
+	} {
+		_, err := output.ParseJSONPath(template)
+		assert.Error(t, err, template)
+	}
+}
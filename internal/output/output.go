@@ -0,0 +1,396 @@
+// Package output renders command results as tables, JSON, YAML, CSV, TSV, JSON Lines,
+// go-templates or JSONPath templates.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"go.yaml.in/yaml/v3"
+)
+
+// Format is an output format selected with --output.
+type Format string
+
+const (
+	// FormatTable renders human-readable tables.
+	FormatTable Format = "table"
+	// FormatJSON renders indented JSON.
+	FormatJSON Format = "json"
+	// FormatYAML renders YAML with the same field names as JSON.
+	FormatYAML Format = "yaml"
+	// FormatCSV renders comma-separated values with a header row.
+	FormatCSV Format = "csv"
+	// FormatTSV renders tab-separated values with a header row.
+	FormatTSV Format = "tsv"
+	// FormatJSONL renders one compact JSON object per item and line.
+	FormatJSONL Format = "jsonl"
+	// FormatMarkdown renders the table as a Markdown table.
+	FormatMarkdown Format = "markdown"
+	// FormatGoTemplate executes a Go text/template against the result.
+	FormatGoTemplate Format = "go-template"
+	// FormatJSONPath evaluates a JSONPath template against the JSON representation of the result.
+	FormatJSONPath Format = "jsonpath"
+)
+
+// Formats lists the formats that need no argument, in the order shown in help texts.
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatCSV, FormatTSV, FormatJSONL, FormatMarkdown}
+
+// ParseFormat converts a string such as "yaml" into a Format.
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case FormatTable, FormatJSON, FormatYAML, FormatCSV, FormatTSV, FormatJSONL, FormatMarkdown, FormatGoTemplate, FormatJSONPath:
+		return format, nil
+	}
+
+	return "", errors.Newf("unsupported output format: %s (expected %s)", s, FormatNames())
+}
+
+// FormatNames returns the supported formats as a comma-separated list.
+func FormatNames() string {
+	names := make([]string, 0, len(Formats)+2)
+	for _, format := range Formats {
+		names = append(names, string(format))
+	}
+	names = append(names, string(FormatGoTemplate)+"=TEMPLATE", string(FormatJSONPath)+"=EXPR")
+
+	return strings.Join(names, ", ")
+}
+
+// Options selects the output format and the template of template formats.
+type Options struct {
+	// Format is the output format.
+	Format Format
+	// Template is the go-template or JSONPath template.
+	Template string
+	// Columns selects and orders the columns of row-based output by name or JSON field path.
+	Columns []string
+	// SortBy sorts the items by a column name or JSON field path.
+	SortBy string
+	// Reverse reverses the sort order.
+	Reverse bool
+	// Filter keeps only the items matching an expression, see package expr.
+	Filter string
+}
+
+// ParseOptions parses an --output value such as "json" or "jsonpath={.name}".
+// templateFile, if not empty, is read as the template; it implies go-template
+// unless the value selects jsonpath.
+func ParseOptions(value, templateFile string) (Options, error) {
+	name, text, hasText := strings.Cut(value, "=")
+
+	format, err := ParseFormat(name)
+	if err != nil {
+		return Options{}, err
+	}
+
+	isTemplate := format == FormatGoTemplate || format == FormatJSONPath
+	if hasText && !isTemplate {
+		return Options{}, errors.Newf("output format %s does not take a template", format)
+	}
+
+	if templateFile != "" {
+		if hasText {
+			return Options{}, errors.New("--template-file cannot be combined with an inline template")
+		}
+		if format == FormatTable {
+			format, isTemplate = FormatGoTemplate, true
+		}
+		if !isTemplate {
+			return Options{}, errors.Newf("--template-file requires go-template or jsonpath output, got %s", format)
+		}
+
+		raw, err := os.ReadFile(templateFile)
+		if err != nil {
+			return Options{}, errors.Wrap(err, "failed to read template file")
+		}
+		text = string(raw)
+	}
+
+	if isTemplate && text == "" {
+		return Options{}, errors.Newf("output format %s requires a template, e.g. -o %s=... or --template-file", format, format)
+	}
+
+	return Options{Format: format, Template: text}, nil
+}
+
+// Column declares a column of row-based output once for table, CSV and TSV rendering.
+type Column[T any] struct {
+	// Name is the field name used as CSV and TSV header, matching the JSON field name where one exists.
+	Name string
+	// Header is the table header. Defaults to Name.
+	Header string
+	// Value extracts the raw cell value from an item.
+	Value func(T) any
+	// Format optionally formats the cell for table output.
+	Format func(T) string
+}
+
+// View describes the result of a command.
+type View[T any] struct {
+	// Data is encoded by JSON and YAML output. Defaults to Items (or the single item of a Detail view).
+	Data any
+	// Items are the rows of table, CSV, TSV and JSON Lines output.
+	Items []T
+	// Columns declares the row-based representation of an item.
+	Columns []Column[T]
+	// Detail renders the table as field/value pairs of a single item instead of one row per item.
+	Detail bool
+	// Footer is printed below the table, separated by an empty line.
+	Footer string
+	// Text replaces the column-based table with custom human-readable output.
+	Text func(w io.Writer) error
+}
+
+// Render writes view to w in the format selected by opts.
+func Render[T any](w io.Writer, opts Options, view View[T]) error {
+	view, err := process(opts, view)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case FormatTable:
+		if view.Text != nil {
+			return view.Text(w)
+		}
+		writeTable(w, view, false)
+		return nil
+	case FormatMarkdown:
+		writeTable(w, view, true)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return errors.Wrap(encoder.Encode(view.data()), "failed to encode JSON")
+	case FormatYAML:
+		return writeYAML(w, view.data())
+	case FormatCSV:
+		return writeDelimited(w, view, ',')
+	case FormatTSV:
+		return writeDelimited(w, view, '\t')
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		for _, item := range view.Items {
+			if err := encoder.Encode(item); err != nil {
+				return errors.Wrap(err, "failed to encode JSON")
+			}
+		}
+		return nil
+	case FormatGoTemplate:
+		tmpl, err := ParseTemplate(opts.Template)
+		if err != nil {
+			return err
+		}
+		return errors.Wrap(tmpl.Execute(w, view.data()), "failed to execute go-template")
+	case FormatJSONPath:
+		path, err := ParseJSONPath(opts.Template)
+		if err != nil {
+			return err
+		}
+		data, err := toJSONValue(view.data())
+		if err != nil {
+			return err
+		}
+		return path.Execute(w, data)
+	default:
+		return errors.Newf("unsupported output format: %s", opts.Format)
+	}
+}
+
+// data returns the value for structured formats.
+func (v View[T]) data() any {
+	switch {
+	case v.Data != nil:
+		return v.Data
+	case v.Detail && len(v.Items) == 1:
+		return v.Items[0]
+	case v.Items == nil:
+		return []T{}
+	default:
+		return v.Items
+	}
+}
+
+// writeTable renders the items as a table, or as field/value pairs for detail views.
+// Markdown tables use the same cells as the human-readable table.
+func writeTable[T any](w io.Writer, view View[T], markdown bool) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+
+	if view.Detail {
+		t.AppendHeader(table.Row{"Field", "Value"})
+		for _, item := range view.Items {
+			for _, column := range view.Columns {
+				t.AppendRow(table.Row{column.header(), column.tableCell(item)})
+			}
+		}
+	} else {
+		header := make(table.Row, len(view.Columns))
+		for i, column := range view.Columns {
+			header[i] = column.header()
+		}
+		t.AppendHeader(header)
+
+		for _, item := range view.Items {
+			row := make(table.Row, len(view.Columns))
+			for i, column := range view.Columns {
+				row[i] = column.tableCell(item)
+			}
+			t.AppendRow(row)
+		}
+	}
+
+	if markdown {
+		t.RenderMarkdown()
+	} else {
+		t.Render()
+	}
+
+	if view.Footer != "" {
+		_, _ = fmt.Fprintf(w, "\n%s\n", view.Footer)
+	}
+}
+
+// writeDelimited renders the items as CSV or TSV with a header row of column names.
+func writeDelimited[T any](w io.Writer, view View[T], separator rune) error {
+	records := make([][]string, 0, len(view.Items)+1)
+
+	header := make([]string, len(view.Columns))
+	for i, column := range view.Columns {
+		header[i] = column.Name
+	}
+	records = append(records, header)
+
+	for _, item := range view.Items {
+		record := make([]string, len(view.Columns))
+		for i, column := range view.Columns {
+			record[i] = Cell(column.Value(item))
+		}
+		records = append(records, record)
+	}
+
+	if separator == '\t' {
+		for _, record := range records {
+			for i, field := range record {
+				record[i] = tsvEscaper.Replace(field)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(record, "\t")); err != nil {
+				return errors.Wrap(err, "failed to write TSV")
+			}
+		}
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(records); err != nil {
+		return errors.Wrap(err, "failed to write CSV")
+	}
+
+	return nil
+}
+
+// tsvEscaper escapes the characters that would break the TSV line structure.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// writeYAML encodes data as YAML. The value is converted through JSON first so that
+// YAML output uses the same field names and ordering as JSON output.
+func writeYAML(w io.Writer, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode YAML")
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return errors.Wrap(err, "failed to encode YAML")
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return errors.Wrap(err, "failed to encode YAML")
+	}
+
+	return errors.Wrap(encoder.Close(), "failed to encode YAML")
+}
+
+// blockStyle clears the flow and quoting styles inherited from JSON so the encoder picks idiomatic YAML.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// header returns the table header of the column.
+func (c Column[T]) header() string {
+	if c.Header != "" {
+		return c.Header
+	}
+
+	return c.Name
+}
+
+// tableCell returns the value shown in table output.
+func (c Column[T]) tableCell(item T) any {
+	if c.Format != nil {
+		return c.Format(item)
+	}
+
+	value := deref(c.Value(item))
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case time.Time:
+		return v.Format("2006-01-02")
+	default:
+		return v
+	}
+}
+
+// Cell formats a raw value for CSV and TSV output: nil becomes empty,
+// times use RFC 3339 and floats use the shortest exact representation.
+func Cell(value any) string {
+	switch v := deref(value).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// deref follows pointers, returning nil for nil pointers.
+func deref(value any) any {
+	if value == nil {
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	return rv.Interface()
+}
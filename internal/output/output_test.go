@@ -0,0 +1,308 @@
+package output_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+type plugin struct {
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner"`
+	Downloads int64     `json:"downloads"`
+	Rating    *float64  `json:"rating"`
+	Tags      []string  `json:"tags"`
+	Created   time.Time `json:"createdAt"`
+}
+
+func rating(v float64) *float64 {
+	return &v
+}
+
+var plugins = []plugin{
+	{
+		Name:      "Essentials",
+		Owner:     "alice",
+		Downloads: 12345,
+		Rating:    rating(4.5),
+		Tags:      []string{"admin", "chat"},
+		Created:   time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC),
+	},
+	{
+		Name:      "Tab\tSeparated, \"quoted\"",
+		Owner:     "bob",
+		Downloads: 7,
+		Tags:      []string{},
+		Created:   time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+var pluginColumns = []output.Column[plugin]{
+	{Name: "name", Header: "Name", Value: func(p plugin) any { return p.Name }},
+	{Name: "owner", Header: "Owner", Value: func(p plugin) any { return p.Owner }},
+	{Name: "downloads", Header: "Downloads", Value: func(p plugin) any { return p.Downloads }},
+	{Name: "rating", Header: "Rating", Value: func(p plugin) any { return p.Rating }},
+	{Name: "createdAt", Header: "Created", Value: func(p plugin) any { return p.Created }},
+}
+
+func TestRender_Golden(t *testing.T) {
+	t.Parallel()
+
+	list := output.View[plugin]{
+		Data:    map[string]any{"result": plugins, "count": len(plugins)},
+		Items:   plugins,
+		Columns: pluginColumns,
+		Footer:  "Total: 2 plugins",
+	}
+	detail := output.View[plugin]{
+		Items:   plugins[:1],
+		Columns: pluginColumns,
+		Detail:  true,
+	}
+
+	tests := []struct {
+		name string
+		view output.View[plugin]
+	}{
+		{name: "list", view: list},
+		{name: "detail", view: detail},
+		{name: "empty", view: output.View[plugin]{Columns: pluginColumns}},
+	}
+
+	for _, tt := range tests {
+		for _, format := range output.Formats {
+			t.Run(tt.name+"_"+string(format), func(t *testing.T) {
+				t.Parallel()
+
+				var buf bytes.Buffer
+				require.NoError(t, output.Render(&buf, output.Options{Format: format}, tt.view))
+
+				assertGolden(t, fmt.Sprintf("%s.%s.golden", tt.name, format), buf.Bytes())
+			})
+		}
+	}
+}
+
+func TestRender_Text(t *testing.T) {
+	t.Parallel()
+
+	view := output.View[plugin]{
+		Items:   plugins,
+		Columns: pluginColumns,
+		Text: func(w io.Writer) error {
+			_, err := fmt.Fprintln(w, "custom")
+			return err
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, output.Render(&buf, output.Options{Format: output.FormatTable}, view))
+	assert.Equal(t, "custom\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, output.Render(&buf, output.Options{Format: output.FormatCSV}, view))
+	assert.Contains(t, buf.String(), "name,owner,downloads,rating,createdAt\n")
+}
+
+func TestRender_TableFormat(t *testing.T) {
+	t.Parallel()
+
+	columns := []output.Column[plugin]{
+		{
+			Name:   "rating",
+			Value:  func(p plugin) any { return p.Rating },
+			Format: func(p plugin) string { return fmt.Sprintf("%.1f stars", *p.Rating) },
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, output.Render(&buf, output.Options{Format: output.FormatTable}, output.View[plugin]{Items: plugins[:1], Columns: columns}))
+
+	assert.Contains(t, buf.String(), "RATING")
+	assert.Contains(t, buf.String(), "4.5 stars")
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range output.Formats {
+		parsed, err := output.ParseFormat(string(format))
+		require.NoError(t, err)
+		assert.Equal(t, format, parsed)
+	}
+
+	_, err := output.ParseFormat("xml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported output format: xml")
+}
+
+func TestCell(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "nil", value: nil, want: ""},
+		{name: "nil pointer", value: (*float64)(nil), want: ""},
+		{name: "pointer", value: rating(2.25), want: "2.25"},
+		{name: "float", value: 1e6, want: "1000000"},
+		{name: "int", value: int64(42), want: "42"},
+		{name: "bool", value: true, want: "true"},
+		{name: "time", value: time.Date(2024, time.March, 2, 1, 0, 0, 0, time.UTC), want: "2024-03-02T01:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, output.Cell(tt.value))
+		})
+	}
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file missing, run go test ./internal/output -update")
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestRender_Process(t *testing.T) {
+	t.Parallel()
+
+	items := []plugin{
+		{Name: "c", Owner: "alice", Downloads: 10, Tags: []string{"chat"}},
+		{Name: "a", Owner: "bob", Downloads: 30, Tags: []string{"admin"}},
+		{Name: "b", Owner: "carol", Downloads: 10, Tags: []string{"chat", "admin"}},
+		{Name: "d", Owner: "dave", Downloads: 20},
+	}
+	view := output.View[plugin]{
+		Data:    map[string]any{"result": items},
+		Items:   items,
+		Columns: pluginColumns,
+		Text: func(w io.Writer) error {
+			_, err := fmt.Fprintln(w, "custom")
+			return err
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts output.Options
+		want string
+	}{
+		{
+			name: "filter",
+			opts: output.Options{Columns: []string{"name"}, Filter: `downloads >= 20 || tags == "chat"`},
+			want: "name\nc\na\nb\nd\n",
+		},
+		{
+			name: "filter json path",
+			opts: output.Options{Columns: []string{"name"}, Filter: `tags == "admin" && owner != "bob"`},
+			want: "name\nb\n",
+		},
+		{
+			name: "stable sort",
+			opts: output.Options{Columns: []string{"name", "downloads"}, SortBy: "downloads"},
+			want: "name,downloads\nc,10\nb,10\nd,20\na,30\n",
+		},
+		{
+			name: "stable reverse sort",
+			opts: output.Options{Columns: []string{"name", "downloads"}, SortBy: "downloads", Reverse: true},
+			want: "name,downloads\na,30\nd,20\nc,10\nb,10\n",
+		},
+		{
+			name: "columns by json path",
+			opts: output.Options{Columns: []string{"Owner", "tags"}, SortBy: "name"},
+			want: "owner,tags\nbob,[admin]\ncarol,[chat admin]\nalice,[chat]\ndave,\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.opts.Format = output.FormatCSV
+
+			var buf bytes.Buffer
+			require.NoError(t, output.Render(&buf, tt.opts, view))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestRender_ProcessStructured(t *testing.T) {
+	t.Parallel()
+
+	view := output.View[plugin]{
+		Data:    map[string]any{"result": plugins},
+		Items:   plugins,
+		Columns: pluginColumns,
+		Text: func(w io.Writer) error {
+			_, err := fmt.Fprintln(w, "custom")
+			return err
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, output.Render(&buf, output.Options{Format: output.FormatJSONPath, Template: "{.name}", Filter: "downloads < 100"}, view))
+	assert.Equal(t, "", buf.String())
+
+	buf.Reset()
+	require.NoError(t, output.Render(&buf, output.Options{Format: output.FormatJSONPath, Template: "{[*].owner}", SortBy: "owner", Reverse: true}, view))
+	assert.Equal(t, "bob alice", buf.String())
+
+	buf.Reset()
+	require.NoError(t, output.Render(&buf, output.Options{Format: output.FormatTable, Columns: []string{"name"}}, view))
+	assert.NotContains(t, buf.String(), "custom")
+	assert.Contains(t, buf.String(), "NAME")
+}
+
+func TestRender_ProcessErrors(t *testing.T) {
+	t.Parallel()
+
+	view := output.View[plugin]{Items: plugins, Columns: pluginColumns}
+
+	tests := []struct {
+		name string
+		opts output.Options
+		want string
+	}{
+		{name: "invalid filter", opts: output.Options{Filter: "downloads >"}, want: "invalid filter"},
+		{name: "unknown filter field", opts: output.Options{Filter: "stars > 1"}, want: `unknown field "stars"`},
+		{name: "unknown sort field", opts: output.Options{SortBy: "stats.stars"}, want: "invalid --sort-by"},
+		{name: "unknown column", opts: output.Options{Columns: []string{"name", "slug"}}, want: "invalid --columns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.opts.Format = output.FormatTable
+
+			err := output.Render(io.Discard, tt.opts, view)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/expr"
+)
+
+// process applies --filter, --sort-by, --reverse and --columns to a view.
+// Filtering or sorting replaces Data with the resulting items so that every format
+// shows the same selection; any of the options switches table output to columns.
+// Detail views only honor --columns.
+func process[T any](opts Options, view View[T]) (View[T], error) {
+	if view.Detail {
+		opts.Filter, opts.SortBy = "", ""
+	}
+	if opts.Filter == "" && opts.SortBy == "" && len(opts.Columns) == 0 {
+		return view, nil
+	}
+
+	fields := newFieldSet(view.Columns, view.Items)
+
+	if opts.Filter != "" {
+		filter, err := expr.Compile(opts.Filter)
+		if err != nil {
+			return view, err
+		}
+		for _, field := range filter.Fields() {
+			if err := fields.check(field); err != nil {
+				return view, errors.Wrap(err, "invalid --filter")
+			}
+		}
+
+		var matched []T
+		for i, item := range view.Items {
+			if filter.Match(fields.resolver(i)) {
+				matched = append(matched, item)
+			}
+		}
+		fields = newFieldSet(view.Columns, matched)
+		view.Items = matched
+		if view.Footer != "" {
+			view.Footer += fmt.Sprintf(" (%d shown)", len(matched))
+		}
+	}
+
+	if opts.SortBy != "" {
+		if err := fields.check(opts.SortBy); err != nil {
+			return view, errors.Wrap(err, "invalid --sort-by")
+		}
+
+		keys := make([]any, len(view.Items))
+		for i := range view.Items {
+			keys[i], _ = fields.resolver(i)(opts.SortBy)
+		}
+
+		order := make([]int, len(view.Items))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			if opts.Reverse {
+				return expr.Compare(keys[order[b]], keys[order[a]]) < 0
+			}
+			return expr.Compare(keys[order[a]], keys[order[b]]) < 0
+		})
+
+		sorted := make([]T, len(view.Items))
+		for i, index := range order {
+			sorted[i] = view.Items[index]
+		}
+		view.Items = sorted
+	}
+
+	if opts.Filter != "" || opts.SortBy != "" {
+		view.Data = nil
+	}
+
+	if len(opts.Columns) > 0 {
+		columns, err := selectColumns(view.Columns, opts.Columns, fields)
+		if err != nil {
+			return view, err
+		}
+		view.Columns = columns
+	}
+
+	view.Text = nil
+
+	return view, nil
+}
+
+// selectColumns returns the named columns in the given order. Names that are not
+// declared columns are treated as field paths of the JSON representation.
+func selectColumns[T any](columns []Column[T], names []string, fields *fieldSet[T]) ([]Column[T], error) {
+	selected := make([]Column[T], 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if column, ok := fields.column(name); ok {
+			selected = append(selected, column)
+			continue
+		}
+
+		if err := fields.check(name); err != nil {
+			return nil, errors.Wrap(err, "invalid --columns")
+		}
+
+		path := name
+		selected = append(selected, Column[T]{
+			Name: path,
+			Value: func(item T) any {
+				value, _ := lookupPath(toJSONLike(item), path)
+				return value
+			},
+		})
+	}
+
+	return selected, nil
+}
+
+// fieldSet resolves field names of items: declared column names first, then JSON field paths.
+type fieldSet[T any] struct {
+	columns []Column[T]
+	items   []T
+	json    []any
+}
+
+func newFieldSet[T any](columns []Column[T], items []T) *fieldSet[T] {
+	return &fieldSet[T]{columns: columns, items: items, json: make([]any, len(items))}
+}
+
+// column returns the declared column with the given name, ignoring case.
+func (f *fieldSet[T]) column(name string) (Column[T], bool) {
+	for _, column := range f.columns {
+		if strings.EqualFold(column.Name, name) {
+			return column, true
+		}
+	}
+
+	return Column[T]{}, false
+}
+
+// resolver returns the field lookup for the i-th item.
+func (f *fieldSet[T]) resolver(i int) expr.Resolver {
+	return func(path string) (any, bool) {
+		if column, ok := f.column(path); ok {
+			return column.Value(f.items[i]), true
+		}
+
+		if f.json[i] == nil {
+			f.json[i] = toJSONLike(f.items[i])
+		}
+
+		return lookupPath(f.json[i], path)
+	}
+}
+
+// check returns an error if name is neither a column nor a field of any item.
+func (f *fieldSet[T]) check(name string) error {
+	if _, ok := f.column(name); ok || len(f.items) == 0 {
+		return nil
+	}
+
+	for i := range f.items {
+		if _, ok := f.resolver(i)(name); ok {
+			return nil
+		}
+	}
+
+	names := make([]string, len(f.columns))
+	for i, column := range f.columns {
+		names[i] = column.Name
+	}
+
+	return errors.Newf("unknown field %q (columns: %s; or a JSON field path such as stats.downloads)",
+		name, strings.Join(names, ", "))
+}
+
+// toJSONLike converts a value to its generic JSON representation, or nil if it cannot be encoded.
+func toJSONLike(value any) any {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil
+	}
+
+	return generic
+}
+
+// lookupPath follows a dotted path through objects. Lists are traversed element-wise,
+// so `roles.name` yields the names of all roles.
+func lookupPath(value any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]any:
+			child, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = child
+		case []any:
+			var values []any
+			for _, element := range v {
+				if child, ok := lookupPath(element, key); ok {
+					values = append(values, child)
+				}
+			}
+			value = values
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
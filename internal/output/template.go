@@ -0,0 +1,199 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// TemplateFuncs are the helper functions available in go-template output:
+//
+//	date LAYOUT TIME    formats a time, e.g. {{date "2006-01-02" .CreatedAt}}
+//	ago TIME            relative time, e.g. "3 days ago"
+//	humanize NUMBER     short number, e.g. 12345 -> "12.3k"
+//	comma NUMBER        thousands separators, e.g. 12345 -> "12,345"
+//	join SEP LIST       joins list elements, e.g. {{join ", " .GameVersions}}
+//	json VALUE          compact JSON encoding
+//	upper/lower STRING  changes the case
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"date":     formatDate,
+		"ago":      ago,
+		"humanize": humanize,
+		"comma":    comma,
+		"join":     join,
+		"json":     toJSON,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+	}
+}
+
+// ParseTemplate parses a go-template with TemplateFuncs.
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid go-template")
+	}
+
+	return tmpl, nil
+}
+
+// toTime accepts time.Time, *time.Time and RFC 3339 strings.
+func toTime(value any) (time.Time, error) {
+	switch v := deref(value).(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, errors.Newf("cannot parse %q as time", v)
+		}
+		return t, nil
+	case nil:
+		return time.Time{}, nil
+	default:
+		return time.Time{}, errors.Newf("cannot use %T as time", value)
+	}
+}
+
+// formatDate formats a time with a Go layout.
+func formatDate(layout string, value any) (string, error) {
+	t, err := toTime(value)
+	if err != nil || t.IsZero() {
+		return "", err
+	}
+
+	return t.Format(layout), nil
+}
+
+// ago returns how long ago value was in the largest fitting unit.
+func ago(value any) (string, error) {
+	t, err := toTime(value)
+	if err != nil || t.IsZero() {
+		return "", err
+	}
+
+	elapsed := time.Since(t)
+	suffix := "ago"
+	if elapsed < 0 {
+		elapsed, suffix = -elapsed, "from now"
+	}
+
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, unit := range units {
+		if n := int(elapsed / unit.size); n > 0 {
+			if n > 1 {
+				return fmt.Sprintf("%d %ss %s", n, unit.name, suffix), nil
+			}
+			return fmt.Sprintf("1 %s %s", unit.name, suffix), nil
+		}
+	}
+
+	return "just now", nil
+}
+
+// toFloat converts any numeric value (or numeric string) to float64.
+func toFloat(value any) (float64, error) {
+	v := reflect.ValueOf(deref(value))
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		n, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return 0, errors.Newf("cannot use %q as number", v.String())
+		}
+		return n, nil
+	default:
+		return 0, errors.Newf("cannot use %T as number", value)
+	}
+}
+
+// humanize shortens large numbers with k, M and B suffixes.
+func humanize(value any) (string, error) {
+	n, err := toFloat(value)
+	if err != nil {
+		return "", err
+	}
+
+	for _, unit := range []struct {
+		suffix string
+		size   float64
+	}{{"B", 1e9}, {"M", 1e6}, {"k", 1e3}} {
+		if math.Abs(n) >= unit.size {
+			return strconv.FormatFloat(math.Round(n/unit.size*10)/10, 'f', -1, 64) + unit.suffix, nil
+		}
+	}
+
+	return strconv.FormatFloat(n, 'f', -1, 64), nil
+}
+
+// comma formats the integer part of a number with thousands separators.
+func comma(value any) (string, error) {
+	n, err := toFloat(value)
+	if err != nil {
+		return "", err
+	}
+
+	digits := strconv.FormatInt(int64(math.Abs(n)), 10)
+	var b strings.Builder
+	if n < 0 {
+		b.WriteByte('-')
+	}
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+
+	return b.String(), nil
+}
+
+// join joins the elements of a slice with sep.
+func join(sep string, list any) (string, error) {
+	v := reflect.ValueOf(deref(list))
+	if !v.IsValid() {
+		return "", nil
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", errors.Newf("cannot join %T", list)
+	}
+
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+
+	return strings.Join(parts, sep), nil
+}
+
+// toJSON encodes value as compact JSON.
+func toJSON(value any) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode JSON")
+	}
+
+	return string(raw), nil
+}
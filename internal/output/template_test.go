@@ -0,0 +1,147 @@
+package output_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "field", template: "{{.Owner}}", want: "alice"},
+		{name: "date", template: `{{date "2006-01-02" .Created}}`, want: "2024-01-15"},
+		{name: "humanize", template: "{{humanize .Downloads}}", want: "12.3k"},
+		{name: "comma", template: "{{comma .Downloads}}", want: "12,345"},
+		{name: "join", template: `{{join ", " .Tags}}`, want: "admin, chat"},
+		{name: "json", template: "{{json .Tags}}", want: `["admin","chat"]`},
+		{name: "upper", template: "{{upper .Name}}", want: "ESSENTIALS"},
+		{name: "pointer", template: "{{humanize .Rating}}", want: "4.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			err := output.Render(&buf,
+				output.Options{Format: output.FormatGoTemplate, Template: tt.template},
+				output.View[plugin]{Items: plugins[:1], Detail: true})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestTemplateFuncs_Humanize(t *testing.T) {
+	t.Parallel()
+
+	humanize := output.TemplateFuncs()["humanize"].(func(any) (string, error))
+
+	for value, want := range map[any]string{
+		999:           "999",
+		1000:          "1k",
+		1_250_000:     "1.3M",
+		int64(3e9):    "3B",
+		-4200:         "-4.2k",
+		"12":          "12",
+		float32(0.25): "0.25",
+	} {
+		got, err := humanize(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "%v", value)
+	}
+
+	_, err := humanize([]int{1})
+	assert.Error(t, err)
+}
+
+func TestTemplateFuncs_Ago(t *testing.T) {
+	t.Parallel()
+
+	ago := output.TemplateFuncs()["ago"].(func(any) (string, error))
+
+	got, err := ago(time.Now().Add(-50 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "2 days ago", got)
+
+	got, err = ago(time.Now().Add(-90 * time.Minute).Format(time.RFC3339))
+	require.NoError(t, err)
+	assert.Equal(t, "1 hour ago", got)
+
+	got, err = ago(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "just now", got)
+}
+
+func TestParseOptions(t *testing.T) {
+	t.Parallel()
+
+	templateFile := filepath.Join(t.TempDir(), "owner.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte("{{.Owner}}\n"), 0o600))
+
+	tests := []struct {
+		name         string
+		value        string
+		templateFile string
+		want         output.Options
+		wantErr      string
+	}{
+		{name: "plain", value: "yaml", want: output.Options{Format: output.FormatYAML}},
+		{
+			name:  "go-template",
+			value: "go-template={{.Name}}",
+			want:  output.Options{Format: output.FormatGoTemplate, Template: "{{.Name}}"},
+		},
+		{
+			name:  "jsonpath with equals sign",
+			value: `jsonpath={.result[?(@.name=="a")].id}`,
+			want:  output.Options{Format: output.FormatJSONPath, Template: `{.result[?(@.name=="a")].id}`},
+		},
+		{
+			name:         "template file implies go-template",
+			value:        "table",
+			templateFile: templateFile,
+			want:         output.Options{Format: output.FormatGoTemplate, Template: "{{.Owner}}\n"},
+		},
+		{
+			name:         "template file with jsonpath",
+			value:        "jsonpath",
+			templateFile: templateFile,
+			want:         output.Options{Format: output.FormatJSONPath, Template: "{{.Owner}}\n"},
+		},
+		{name: "missing template", value: "go-template", wantErr: "requires a template"},
+		{name: "template on plain format", value: "json={.a}", wantErr: "does not take a template"},
+		{name: "template file with json", value: "json", templateFile: templateFile, wantErr: "requires go-template or jsonpath"},
+		{name: "unknown", value: "xml", wantErr: "unsupported output format"},
+		{name: "unreadable file", value: "go-template", templateFile: filepath.Join(t.TempDir(), "missing"), wantErr: "failed to read template file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := output.ParseOptions(tt.value, tt.templateFile)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
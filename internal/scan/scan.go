@@ -0,0 +1,321 @@
+// Package scan identifies the plugin jars of a server directory as Hangar project versions
+// by their SHA-256 checksums.
+package scan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/jarinfo"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultConcurrency is the number of jars resolved at the same time by default.
+const DefaultConcurrency = 4
+
+// Ways a jar was identified.
+const (
+	// MatchedByHash means Hangar published a file with the jar's checksum.
+	MatchedByHash = "hash"
+	// MatchedByMetadata means the jar's plugin descriptor matched a Hangar project.
+	MatchedByMetadata = "metadata"
+)
+
+// Client is the part of the Hangar API used to identify jars.
+type Client interface {
+	jarinfo.Client
+
+	GetVersionByHash(ctx context.Context, hash string) (*hangar.Version, error)
+	GetProject(ctx context.Context, slug string) (*hangar.Project, error)
+	GetLatestVersion(ctx context.Context, slug, channel, platform, minecraftVersion string) (*hangar.Version, error)
+}
+
+// Options configures a scan.
+type Options struct {
+	// Concurrency is the number of jars resolved at the same time (defaults to DefaultConcurrency).
+	Concurrency int
+	// HashOnly disables the fallback to the plugin descriptor for jars with unknown checksums.
+	HashOnly bool
+	// MinConfidence is the confidence a descriptor match needs (defaults to jarinfo.DefaultMinConfidence).
+	MinConfidence float64
+}
+
+// Jar is a jar file of the scanned directory.
+type Jar struct {
+	// File is the file name relative to the scanned directory.
+	File string `json:"file"`
+	// SHA256 is the hex-encoded SHA-256 checksum of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// Plugin is a jar identified as a version of a Hangar project.
+type Plugin struct {
+	Jar
+
+	// Slug is the project slug.
+	Slug string `json:"slug"`
+	// Owner is the project owner.
+	Owner string `json:"owner"`
+	// Name is the project display name.
+	Name string `json:"name"`
+	// Version is the name of the installed version.
+	Version string `json:"version"`
+	// Channel is the release channel of the installed version, e.g. "Release".
+	Channel string `json:"channel"`
+	// ReleasedAt is when the installed version was published, if it was.
+	ReleasedAt time.Time `json:"releasedAt,omitzero"`
+	// Platform is the platform the jar was published for, e.g. "PAPER".
+	Platform string `json:"platform"`
+	// Latest is the newest version in the same channel and platform, if known.
+	Latest string `json:"latest,omitempty"`
+	// UpdateAvailable reports whether Latest is newer than the installed version.
+	UpdateAvailable bool `json:"updateAvailable"`
+	// MatchedBy is how the jar was identified, MatchedByHash or MatchedByMetadata.
+	MatchedBy string `json:"matchedBy"`
+	// Confidence is the confidence of the match from 0 to 1 (always 1 for checksum matches).
+	Confidence float64 `json:"confidence"`
+}
+
+// Report is the result of a scan. Both lists are sorted by file name.
+type Report struct {
+	// Dir is the scanned directory.
+	Dir string `json:"dir"`
+	// Plugins are the jars identified as Hangar versions.
+	Plugins []Plugin `json:"plugins"`
+	// Unknown are the jars whose checksum matches no Hangar version.
+	Unknown []Jar `json:"unknown"`
+}
+
+// Updates returns the number of plugins with a newer version available.
+func (r *Report) Updates() int {
+	count := 0
+	for _, plugin := range r.Plugins {
+		if plugin.UpdateAvailable {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Jars returns the names of the jar files in dir, sorted. Subdirectories are not scanned.
+func Jars(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read plugin directory")
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.EqualFold(filepath.Ext(entry.Name()), ".jar") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// HashFile returns the hex-encoded SHA-256 checksum of a file.
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open file")
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", errors.Wrapf(err, "failed to hash %s", path)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Scan hashes every jar in dir and resolves it concurrently against the Hangar API.
+// Jars unknown to Hangar are reported in Report.Unknown; any other API error aborts the scan.
+func Scan(ctx context.Context, client Client, dir string, opts Options) (*Report, error) {
+	names, err := Jars(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	minConfidence := opts.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = jarinfo.DefaultMinConfidence
+	}
+
+	// Results keep the file order; nil marks an unknown jar
+	jars := make([]Jar, len(names))
+	plugins := make([]*Plugin, len(names))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for i, name := range names {
+		group.Go(func() error {
+			hash, err := HashFile(filepath.Join(dir, name))
+			if err != nil {
+				return err
+			}
+			jars[i] = Jar{File: name, SHA256: hash}
+
+			plugin, err := Identify(groupCtx, client, jars[i])
+			if err == nil && plugin == nil && !opts.HashOnly {
+				plugin, err = IdentifyByMetadata(groupCtx, client, filepath.Join(dir, name), jars[i], minConfidence)
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to identify %s", name)
+			}
+			plugins[i] = plugin
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Dir: dir, Plugins: []Plugin{}, Unknown: []Jar{}}
+	for i, plugin := range plugins {
+		if plugin == nil {
+			report.Unknown = append(report.Unknown, jars[i])
+			continue
+		}
+		report.Plugins = append(report.Plugins, *plugin)
+	}
+
+	return report, nil
+}
+
+// Identify resolves a jar to its Hangar project and version and checks for a newer version
+// in the same channel. It returns nil without error if Hangar does not know the checksum.
+func Identify(ctx context.Context, client Client, jar Jar) (*Plugin, error) {
+	version, err := client.GetVersionByHash(ctx, jar.SHA256)
+	if hangar.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := client.GetProject(ctx, strconv.FormatInt(version.ProjectID, 10))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get project")
+	}
+
+	plugin := &Plugin{
+		Jar:        jar,
+		Slug:       project.Namespace.Slug,
+		Owner:      project.Namespace.Owner,
+		Name:       project.Name,
+		Version:    version.Name,
+		Channel:    version.Channel.Name,
+		ReleasedAt: version.CreatedAt,
+		Platform:   Platform(version, jar.SHA256),
+		MatchedBy:  MatchedByHash,
+		Confidence: 1,
+	}
+
+	if err := checkLatest(ctx, client, plugin, version); err != nil {
+		return nil, err
+	}
+
+	return plugin, nil
+}
+
+// IdentifyByMetadata reads the plugin descriptor of the jar at path and matches it to a
+// Hangar project, for jars that were rebuilt or patched. It returns nil without error if
+// the jar has no readable descriptor or no candidate reaches minConfidence.
+func IdentifyByMetadata(ctx context.Context, client Client, path string, jar Jar, minConfidence float64) (*Plugin, error) {
+	meta, err := jarinfo.Read(path)
+	if err != nil {
+		// Jars without a readable descriptor stay unknown
+		return nil, nil
+	}
+
+	candidates, err := jarinfo.Match(ctx, client, meta)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 || candidates[0].Confidence < minConfidence {
+		return nil, nil
+	}
+	best := candidates[0]
+
+	plugin := &Plugin{
+		Jar:        jar,
+		Slug:       best.Project.Namespace.Slug,
+		Owner:      best.Project.Namespace.Owner,
+		Name:       best.Project.Name,
+		Version:    meta.Version,
+		Platform:   meta.Platform,
+		MatchedBy:  MatchedByMetadata,
+		Confidence: best.Confidence,
+	}
+	if best.Version != nil {
+		plugin.Channel = best.Version.Channel.Name
+		plugin.ReleasedAt = best.Version.CreatedAt
+	}
+
+	if err := checkLatest(ctx, client, plugin, best.Version); err != nil {
+		return nil, err
+	}
+
+	return plugin, nil
+}
+
+// checkLatest looks up the newest version in the channel of the plugin (Release if unknown).
+// Without the installed version, whether it is newer cannot be told and no update is reported.
+func checkLatest(ctx context.Context, client Client, plugin *Plugin, installed *hangar.Version) error {
+	channel := plugin.Channel
+	if channel == "" {
+		channel = "Release"
+	}
+
+	latest, err := client.GetLatestVersion(ctx, plugin.Slug, channel, plugin.Platform, "")
+	switch {
+	case hangar.IsNotFound(err):
+		// The channel has no version for the platform any more
+		return nil
+	case err != nil:
+		return errors.Wrap(err, "failed to get latest version")
+	}
+
+	plugin.Latest = latest.Name
+	if installed != nil {
+		plugin.UpdateAvailable = latest.ID != installed.ID && latest.CreatedAt.After(installed.CreatedAt)
+	}
+
+	return nil
+}
+
+// Platform returns the platform whose download has the given checksum, falling back to
+// the first platform of the version in alphabetical order.
+func Platform(version *hangar.Version, hash string) string {
+	platforms := make([]string, 0, len(version.Downloads))
+	for platform, download := range version.Downloads {
+		if download.FileInfo != nil && strings.EqualFold(download.FileInfo.SHA256Hash, hash) {
+			return platform
+		}
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	if len(platforms) == 0 {
+		return ""
+	}
+
+	return platforms[0]
+}
@@ -0,0 +1,331 @@
+package scan_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/scan"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient serves versions by checksum, the latest version per project and search results.
+type fakeClient struct {
+	versions map[string]*hangar.Version
+	projects map[string]*hangar.Project
+	latest   map[string]*hangar.Version
+	search   []hangar.Project
+	named    map[string]*hangar.Version
+	err      error
+}
+
+func (f *fakeClient) GetVersionByHash(_ context.Context, hash string) (*hangar.Version, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if version, ok := f.versions[hash]; ok {
+		return version, nil
+	}
+	return nil, errors.WithStack(&hangar.APIError{StatusCode: http.StatusNotFound})
+}
+
+func (f *fakeClient) GetProject(_ context.Context, slug string) (*hangar.Project, error) {
+	return f.projects[slug], nil
+}
+
+func (f *fakeClient) GetLatestVersion(_ context.Context, slug, channel, platform, _ string) (*hangar.Version, error) {
+	if version, ok := f.latest[slug+"/"+channel+"/"+platform]; ok {
+		return version, nil
+	}
+	return nil, errors.WithStack(&hangar.APIError{StatusCode: http.StatusNotFound})
+}
+
+func (f *fakeClient) ListProjects(_ context.Context, _ hangar.ListOptions) (*hangar.ProjectsList, error) {
+	return &hangar.ProjectsList{Result: f.search}, nil
+}
+
+func (f *fakeClient) GetVersion(_ context.Context, slug, version string) (*hangar.Version, error) {
+	if found, ok := f.named[slug+"/"+version]; ok {
+		return found, nil
+	}
+	return nil, errors.WithStack(&hangar.APIError{StatusCode: http.StatusNotFound})
+}
+
+// writeJar writes a file to dir and returns its SHA-256 checksum.
+func writeJar(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	sum := sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestScan(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	oldHash := writeJar(t, dir, "Alpha-1.0.jar", "alpha 1.0")
+	betaHash := writeJar(t, dir, "beta.JAR", "beta 2.0")
+	writeJar(t, dir, "custom.jar", "built locally")
+	writeJar(t, dir, "notes.txt", "not a plugin")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "Alpha"), 0o700))
+
+	released := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{
+		versions: map[string]*hangar.Version{
+			oldHash: {
+				ID: 1, ProjectID: 10, Name: "1.0", CreatedAt: released,
+				Channel: hangar.Channel{Name: "Release"},
+				Downloads: map[string]hangar.DownloadInfo{
+					"PAPER":    {FileInfo: &hangar.FileInfo{SHA256Hash: oldHash}},
+					"VELOCITY": {FileInfo: &hangar.FileInfo{SHA256Hash: "other"}},
+				},
+			},
+			betaHash: {
+				ID: 5, ProjectID: 20, Name: "2.0", CreatedAt: released,
+				Channel:   hangar.Channel{Name: "Beta"},
+				Downloads: map[string]hangar.DownloadInfo{"PAPER": {ExternalURL: "https://example.com/beta.jar"}},
+			},
+		},
+		projects: map[string]*hangar.Project{
+			"10": {ID: 10, Name: "Alpha", Namespace: hangar.Namespace{Owner: "alice", Slug: "alpha"}},
+			"20": {ID: 20, Name: "Beta", Namespace: hangar.Namespace{Owner: "bob", Slug: "beta"}},
+		},
+		latest: map[string]*hangar.Version{
+			"alpha/Release/PAPER": {ID: 2, Name: "1.1", CreatedAt: released.AddDate(0, 1, 0)},
+			"beta/Beta/PAPER":     {ID: 5, Name: "2.0", CreatedAt: released},
+		},
+	}
+
+	report, err := scan.Scan(context.Background(), client, dir, scan.Options{Concurrency: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, dir, report.Dir)
+	require.Len(t, report.Plugins, 2)
+
+	alpha := report.Plugins[0]
+	assert.Equal(t, "Alpha-1.0.jar", alpha.File)
+	assert.Equal(t, "alpha", alpha.Slug)
+	assert.Equal(t, "alice", alpha.Owner)
+	assert.Equal(t, "1.0", alpha.Version)
+	assert.Equal(t, "Release", alpha.Channel)
+	assert.Equal(t, "PAPER", alpha.Platform)
+	assert.Equal(t, "1.1", alpha.Latest)
+	assert.True(t, alpha.UpdateAvailable)
+	assert.Equal(t, scan.MatchedByHash, alpha.MatchedBy)
+	assert.InDelta(t, 1.0, alpha.Confidence, 0.001)
+
+	beta := report.Plugins[1]
+	assert.Equal(t, "beta.JAR", beta.File)
+	assert.Equal(t, "PAPER", beta.Platform, "falls back to the only platform")
+	assert.Equal(t, "2.0", beta.Latest)
+	assert.False(t, beta.UpdateAvailable)
+
+	require.Len(t, report.Unknown, 1)
+	assert.Equal(t, "custom.jar", report.Unknown[0].File)
+	assert.Equal(t, 1, report.Updates())
+}
+
+func TestScan_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := scan.Scan(context.Background(), &fakeClient{}, filepath.Join(t.TempDir(), "missing"), scan.Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read plugin directory")
+
+	dir := t.TempDir()
+	writeJar(t, dir, "plugin.jar", "content")
+
+	_, err = scan.Scan(context.Background(), &fakeClient{err: errors.New("connection refused")}, dir, scan.Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to identify plugin.jar")
+}
+
+func TestIdentify_NoLatestVersion(t *testing.T) {
+	t.Parallel()
+
+	jar := scan.Jar{File: "gamma.jar", SHA256: "abc"}
+	client := &fakeClient{
+		versions: map[string]*hangar.Version{"abc": {ProjectID: 30, Name: "0.1", Channel: hangar.Channel{Name: "Alpha"}}},
+		projects: map[string]*hangar.Project{"30": {Namespace: hangar.Namespace{Slug: "gamma"}}},
+	}
+
+	plugin, err := scan.Identify(context.Background(), client, jar)
+	require.NoError(t, err)
+	assert.Equal(t, "gamma", plugin.Slug)
+	assert.Empty(t, plugin.Latest)
+	assert.False(t, plugin.UpdateAvailable)
+}
+
+func TestHashFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	want := writeJar(t, dir, "plugin.jar", "content")
+
+	got, err := scan.HashFile(filepath.Join(dir, "plugin.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = scan.HashFile(filepath.Join(dir, "missing.jar"))
+	require.Error(t, err)
+}
+
+// pluginJar returns a jar containing a plugin.yml.
+func pluginJar(t *testing.T, descriptor string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	file, err := writer.Create("plugin.yml")
+	require.NoError(t, err)
+	_, err = file.Write([]byte(descriptor))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buf.String()
+}
+
+func TestScan_MetadataFallback(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeJar(t, dir, "alpha-patched.jar", pluginJar(t, "name: Alpha\nversion: '1.0'\nauthor: alice\n"))
+	writeJar(t, dir, "stranger.jar", pluginJar(t, "name: Alpha\nversion: '9.9'\nauthor: mallory\n"))
+	writeJar(t, dir, "not-a-zip.jar", "plain bytes")
+
+	released := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{
+		search: []hangar.Project{{Name: "Alpha", Namespace: hangar.Namespace{Owner: "alice", Slug: "alpha"}}},
+		named: map[string]*hangar.Version{
+			"alpha/1.0": {
+				ID: 1, Name: "1.0", CreatedAt: released, Channel: hangar.Channel{Name: "Release"},
+				Downloads: map[string]hangar.DownloadInfo{"PAPER": {}},
+			},
+		},
+		latest: map[string]*hangar.Version{
+			"alpha/Release/PAPER": {ID: 2, Name: "1.1", CreatedAt: released.AddDate(0, 1, 0)},
+		},
+	}
+
+	report, err := scan.Scan(context.Background(), client, dir, scan.Options{})
+	require.NoError(t, err)
+
+	require.Len(t, report.Plugins, 1)
+	alpha := report.Plugins[0]
+	assert.Equal(t, "alpha-patched.jar", alpha.File)
+	assert.Equal(t, "alpha", alpha.Slug)
+	assert.Equal(t, "1.0", alpha.Version)
+	assert.Equal(t, "Release", alpha.Channel)
+	assert.Equal(t, "1.1", alpha.Latest)
+	assert.True(t, alpha.UpdateAvailable)
+	assert.Equal(t, scan.MatchedByMetadata, alpha.MatchedBy)
+	assert.InDelta(t, 1.0, alpha.Confidence, 0.001)
+
+	// The name alone is below the default confidence
+	require.Len(t, report.Unknown, 2)
+	assert.Equal(t, "not-a-zip.jar", report.Unknown[0].File)
+	assert.Equal(t, "stranger.jar", report.Unknown[1].File)
+
+	report, err = scan.Scan(context.Background(), client, dir, scan.Options{MinConfidence: 0.5})
+	require.NoError(t, err)
+	assert.Len(t, report.Plugins, 2)
+
+	report, err = scan.Scan(context.Background(), client, dir, scan.Options{HashOnly: true})
+	require.NoError(t, err)
+	assert.Empty(t, report.Plugins)
+	assert.Len(t, report.Unknown, 3)
+}
+
+func TestCheckUpdates(t *testing.T) {
+	t.Parallel()
+
+	installed := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	version := func(name, channel string, released time.Time) *hangar.Version {
+		return &hangar.Version{
+			Name: name, CreatedAt: released, Channel: hangar.Channel{Name: channel},
+			Description: "## " + name + "\n\n- Fixed a crash\n- Faster startup\n- New command\n",
+		}
+	}
+	client := &fakeClient{
+		latest: map[string]*hangar.Version{
+			"alpha/Release/PAPER": version("1.1", "Release", installed.AddDate(0, 1, 0)),
+			"alpha/Beta/PAPER":    version("1.2-beta", "Beta", installed.AddDate(0, 2, 0)),
+			"beta/Release/PAPER":  version("2.0", "Release", installed),
+			"dev/Release/PAPER":   version("3.0", "Release", installed.AddDate(0, 1, 0)),
+		},
+	}
+	plugins := []scan.Plugin{
+		{Jar: scan.Jar{File: "alpha.jar"}, Slug: "alpha", Version: "1.0", Platform: "PAPER", ReleasedAt: installed},
+		{Jar: scan.Jar{File: "beta.jar"}, Slug: "beta", Version: "2.0", Platform: "PAPER", ReleasedAt: installed},
+		{Jar: scan.Jar{File: "dev.jar"}, Slug: "dev", Version: "3.0-SNAPSHOT", Platform: "PAPER"},
+		{Jar: scan.Jar{File: "gone.jar"}, Slug: "gone", Version: "1.0", Platform: "PAPER", ReleasedAt: installed},
+	}
+
+	tests := []struct {
+		name     string
+		channels []string
+		want     []scan.Update
+	}{
+		{
+			name: "release only",
+			want: []scan.Update{
+				{
+					File: "alpha.jar", Slug: "alpha", Current: "1.0", Latest: "1.1", Channel: "Release",
+					ReleasedAt: installed.AddDate(0, 1, 0), Outdated: true, Changelog: "1.1\n- Fixed a crash\n…",
+				},
+				{File: "beta.jar", Slug: "beta", Current: "2.0", Latest: "2.0", Channel: "Release", ReleasedAt: installed},
+				{File: "dev.jar", Slug: "dev", Current: "3.0-SNAPSHOT", Latest: "3.0", Channel: "Release", ReleasedAt: installed.AddDate(0, 1, 0)},
+				{File: "gone.jar", Slug: "gone", Current: "1.0"},
+			},
+		},
+		{
+			name:     "release and beta",
+			channels: []string{"Release", "Beta"},
+			want: []scan.Update{
+				{
+					File: "alpha.jar", Slug: "alpha", Current: "1.0", Latest: "1.2-beta", Channel: "Beta",
+					ReleasedAt: installed.AddDate(0, 2, 0), Outdated: true, Changelog: "1.2-beta\n- Fixed a crash\n…",
+				},
+				{File: "beta.jar", Slug: "beta", Current: "2.0", Latest: "2.0", Channel: "Release", ReleasedAt: installed},
+				{File: "dev.jar", Slug: "dev", Current: "3.0-SNAPSHOT", Latest: "3.0", Channel: "Release", ReleasedAt: installed.AddDate(0, 1, 0)},
+				{File: "gone.jar", Slug: "gone", Current: "1.0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			updates, err := scan.CheckUpdates(context.Background(), client, plugins, scan.UpdateOptions{
+				Channels:       tt.channels,
+				ChangelogLines: 2,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, updates)
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	t.Parallel()
+
+	changelog := "# Changes\n\n* one\n\n* two\n"
+
+	assert.Equal(t, "Changes\n* one\n* two", scan.Excerpt(changelog, 3))
+	assert.Equal(t, "Changes\n…", scan.Excerpt(changelog, 1))
+	assert.Empty(t, scan.Excerpt(changelog, 0))
+	assert.Empty(t, scan.Excerpt("", 3))
+}
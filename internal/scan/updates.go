@@ -0,0 +1,142 @@
+package scan
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultChannels are the release channels updates are taken from by default.
+var DefaultChannels = []string{"Release"}
+
+// UpdateOptions configures an update check.
+type UpdateOptions struct {
+	// Platform is the server platform, e.g. "PAPER" (defaults to the platform of each plugin).
+	Platform string
+	// MinecraftVersion limits updates to versions supporting the game version, e.g. "1.21.1".
+	MinecraftVersion string
+	// Channels are the accepted release channels (defaults to DefaultChannels).
+	Channels []string
+	// ChangelogLines is the number of changelog lines in the excerpt (0 omits the changelog).
+	ChangelogLines int
+	// Concurrency is the number of plugins checked at the same time (defaults to DefaultConcurrency).
+	Concurrency int
+}
+
+// Update compares an installed plugin with the newest version in the accepted channels.
+type Update struct {
+	// File is the jar file name.
+	File string `json:"file"`
+	// Slug is the project slug.
+	Slug string `json:"slug"`
+	// Current is the installed version.
+	Current string `json:"current"`
+	// Latest is the newest accepted version, empty if no version matches the options.
+	Latest string `json:"latest,omitempty"`
+	// Channel is the release channel of Latest.
+	Channel string `json:"channel,omitempty"`
+	// ReleasedAt is when Latest was published.
+	ReleasedAt time.Time `json:"releasedAt,omitzero"`
+	// Outdated reports whether Latest is newer than the installed version.
+	Outdated bool `json:"outdated"`
+	// Changelog is an excerpt of the description of Latest, if it is newer.
+	Changelog string `json:"changelog,omitempty"`
+}
+
+// CheckUpdates looks up the newest version of every plugin in each accepted channel for the
+// platform and Minecraft version of the options and compares it with the installed version.
+// Plugins whose installed version is not published on Hangar are never reported as outdated,
+// as their age is unknown. Updates are returned in the order of plugins.
+func CheckUpdates(ctx context.Context, client Client, plugins []Plugin, opts UpdateOptions) ([]Update, error) {
+	channels := opts.Channels
+	if len(channels) == 0 {
+		channels = DefaultChannels
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	updates := make([]Update, len(plugins))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for i, plugin := range plugins {
+		group.Go(func() error {
+			platform := opts.Platform
+			if platform == "" {
+				platform = plugin.Platform
+			}
+
+			latest, err := newestVersion(groupCtx, client, plugin.Slug, channels, platform, opts.MinecraftVersion)
+			if err != nil {
+				return errors.Wrapf(err, "failed to check %s for updates", plugin.Slug)
+			}
+
+			update := Update{File: plugin.File, Slug: plugin.Slug, Current: plugin.Version}
+			if latest != nil {
+				update.Latest = latest.Name
+				update.Channel = latest.Channel.Name
+				update.ReleasedAt = latest.CreatedAt
+				update.Outdated = latest.Name != plugin.Version &&
+					!plugin.ReleasedAt.IsZero() && latest.CreatedAt.After(plugin.ReleasedAt)
+				if update.Outdated {
+					update.Changelog = Excerpt(latest.Description, opts.ChangelogLines)
+				}
+			}
+			updates[i] = update
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+// newestVersion returns the most recently published of the latest versions of the channels,
+// or nil if no channel has a version for the platform and Minecraft version.
+func newestVersion(ctx context.Context, client Client, slug string, channels []string, platform, minecraftVersion string) (*hangar.Version, error) {
+	var newest *hangar.Version
+	for _, channel := range channels {
+		version, err := client.GetLatestVersion(ctx, slug, channel, platform, minecraftVersion)
+		if hangar.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if newest == nil || version.CreatedAt.After(newest.CreatedAt) {
+			newest = version
+		}
+	}
+
+	return newest, nil
+}
+
+// Excerpt returns the first lines of a changelog that carry text, without Markdown heading
+// markers. A truncated excerpt ends with an ellipsis line.
+func Excerpt(changelog string, lines int) string {
+	if lines <= 0 {
+		return ""
+	}
+
+	var excerpt []string
+	for _, line := range strings.Split(changelog, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		if line == "" {
+			continue
+		}
+		if len(excerpt) == lines {
+			excerpt = append(excerpt, "…")
+			break
+		}
+		excerpt = append(excerpt, line)
+	}
+
+	return strings.Join(excerpt, "\n")
+}
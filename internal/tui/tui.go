@@ -0,0 +1,548 @@
+// Package tui implements the interactive terminal browser started by `hangar tui`:
+// a searchable project list with category filters and a project view with versions,
+// the rendered README and recent statistics.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+)
+
+const (
+	// pageSize is the number of projects requested per page.
+	pageSize = 50
+	// statsDays is the number of days shown in the statistics pane.
+	statsDays = 30
+)
+
+// Source is the part of the Hangar client used by the browser.
+type Source interface {
+	ListProjects(ctx context.Context, opts hangar.ListOptions) (*hangar.ProjectsList, error)
+	GetProject(ctx context.Context, slug string) (*hangar.Project, error)
+	ListVersions(ctx context.Context, owner, slug string, opts hangar.ListOptions) (*hangar.VersionsList, error)
+	GetProjectMainPage(ctx context.Context, slug string) (*hangar.Page, error)
+	GetProjectStats(ctx context.Context, slug, fromDate, toDate string) (hangar.ProjectStats, error)
+	Download(ctx context.Context, downloadURL string, w io.Writer) (int64, error)
+}
+
+// Options configures the browser.
+type Options struct {
+	// Query is the initial search query.
+	Query string
+	// Category is the initial category filter.
+	Category string
+	// Platform is the platform whose file is downloaded (defaults to PAPER).
+	Platform string
+	// DownloadDir is the directory downloads are saved to (defaults to the working directory).
+	DownloadDir string
+	// MarkdownStyle is the glamour style of the README pane ("dark", "light", "notty", ...).
+	// Defaults to detecting the terminal background.
+	MarkdownStyle string
+}
+
+// Run starts the browser and blocks until the user quits or ctx is canceled.
+func Run(ctx context.Context, source Source, opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	// Detect the background before the program takes over the terminal input
+	if opts.MarkdownStyle == "" {
+		opts.MarkdownStyle = "light"
+		if lipgloss.HasDarkBackground() {
+			opts.MarkdownStyle = "dark"
+		}
+	}
+
+	program := tea.NewProgram(New(ctx, source, opts), tea.WithAltScreen(), tea.WithContext(ctx))
+
+	_, err := program.Run()
+	if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+		return nil
+	}
+
+	return errors.Wrap(err, "failed to run TUI")
+}
+
+// Validate reports an unknown category or platform. Both are matched case-insensitively.
+func (o Options) Validate() error {
+	if o.Category != "" && indexFold(hangar.ProjectCategories, o.Category) < 0 {
+		return errors.Newf("unknown category %q (expected one of %s)",
+			o.Category, strings.Join(hangar.ProjectCategories, ", "))
+	}
+
+	if o.Platform != "" && indexFold(hangar.KnownPlatforms, o.Platform) < 0 {
+		return errors.Newf("unknown platform %q (expected one of %s)",
+			o.Platform, strings.Join(hangar.KnownPlatforms, ", "))
+	}
+
+	return nil
+}
+
+// indexFold returns the index of the first element of values equal to value ignoring case, or -1.
+func indexFold(values []string, value string) int {
+	for i, v := range values {
+		if strings.EqualFold(v, value) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+type screen int
+
+const (
+	screenProjects screen = iota
+	screenProject
+)
+
+type tab int
+
+const (
+	tabVersions tab = iota
+	tabReadme
+	tabStats
+)
+
+// tabNames are the titles of the project tabs in order.
+var tabNames = []string{"Versions", "README", "Stats"}
+
+// Model is the bubbletea model of the browser.
+type Model struct {
+	ctx    context.Context
+	source Source
+	opts   Options
+
+	width, height int
+	screen        screen
+	status        string
+	err           error
+
+	// Project list
+	search    textinput.Model
+	searching bool
+	query     string
+	category  int // index into hangar.ProjectCategories, -1 for all
+	offset    int
+	total     int64
+	projects  []hangar.Project
+	list      table.Model
+	request   int
+
+	// Project view
+	project  hangar.Project
+	tab      tab
+	versions []hangar.Version
+	table    table.Model
+	readme   viewport.Model
+	markdown string
+	stats    hangar.StatsSeries
+	platform int // index into hangar.KnownPlatforms
+}
+
+// New returns the initial model. Requests use ctx.
+func New(ctx context.Context, source Source, opts Options) Model {
+	search := textinput.New()
+	search.Prompt = "/ "
+	search.Placeholder = "search projects"
+	search.SetValue(opts.Query)
+
+	m := Model{
+		ctx:      ctx,
+		source:   source,
+		opts:     opts,
+		width:    100,
+		height:   30,
+		search:   search,
+		query:    opts.Query,
+		category: -1,
+		list:     table.New(table.WithFocused(true)),
+		table:    table.New(table.WithFocused(true)),
+		readme:   viewport.New(100, 20),
+		status:   "Loading projects…",
+	}
+
+	if opts.Category != "" {
+		m.category = indexFold(hangar.ProjectCategories, opts.Category)
+	}
+
+	if opts.Platform != "" {
+		m.platform = max(indexFold(hangar.KnownPlatforms, opts.Platform), 0)
+	}
+
+	m.resize()
+
+	return m
+}
+
+// Init loads the first page of projects.
+func (m Model) Init() tea.Cmd {
+	return m.fetchProjects()
+}
+
+// Messages carrying request results. Project messages carry the slug
+// so results arriving after the user moved on are dropped.
+type (
+	projectsMsg struct {
+		request int
+		list    *hangar.ProjectsList
+		err     error
+	}
+	projectMsg struct {
+		slug    string
+		project *hangar.Project
+		err     error
+	}
+	versionsMsg struct {
+		slug     string
+		versions []hangar.Version
+		err      error
+	}
+	readmeMsg struct {
+		slug     string
+		markdown string
+		err      error
+	}
+	statsMsg struct {
+		slug  string
+		stats hangar.StatsSeries
+		err   error
+	}
+	downloadMsg struct {
+		path string
+		size int64
+		err  error
+	}
+)
+
+// Update handles input and request results.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	case projectsMsg:
+		if msg.request != m.request {
+			return m, nil
+		}
+		if msg.err != nil {
+			return m.fail(msg.err), nil
+		}
+		m.projects, m.total = msg.list.Result, msg.list.Pagination.Count
+		m.list.SetRows(projectRows(m.projects))
+		m.list.SetCursor(0)
+		m.status = fmt.Sprintf("%d projects", m.total)
+		return m, nil
+	case projectMsg:
+		if msg.slug == m.project.Namespace.Slug {
+			if msg.err != nil {
+				return m.fail(msg.err), nil
+			}
+			m.project = *msg.project
+		}
+		return m, nil
+	case versionsMsg:
+		if msg.slug == m.project.Namespace.Slug {
+			if msg.err != nil {
+				return m.fail(msg.err), nil
+			}
+			m.versions = msg.versions
+			m.table.SetRows(versionRows(m.versions))
+			m.table.SetCursor(0)
+			m.status = fmt.Sprintf("%d versions", len(m.versions))
+		}
+		return m, nil
+	case readmeMsg:
+		if msg.slug == m.project.Namespace.Slug {
+			if msg.err != nil {
+				return m.fail(msg.err), nil
+			}
+			m.markdown = msg.markdown
+			m.readme.SetContent(m.renderMarkdown())
+			m.readme.GotoTop()
+		}
+		return m, nil
+	case statsMsg:
+		if msg.slug == m.project.Namespace.Slug {
+			if msg.err != nil {
+				return m.fail(msg.err), nil
+			}
+			m.stats = msg.stats
+		}
+		return m, nil
+	case downloadMsg:
+		if msg.err != nil {
+			return m.fail(msg.err), nil
+		}
+		m.err = nil
+		m.status = fmt.Sprintf("Saved %s (%s)", msg.path, formatBytes(msg.size))
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleKey dispatches key presses; keys not used by the browser go to the focused component.
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	if m.searching {
+		switch msg.String() {
+		case "enter":
+			m.searching = false
+			m.search.Blur()
+			m.query, m.offset = m.search.Value(), 0
+			return m, m.loadProjects()
+		case "esc":
+			m.searching = false
+			m.search.Blur()
+			m.search.SetValue(m.query)
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.search, cmd = m.search.Update(msg)
+		return m, cmd
+	}
+
+	if msg.String() == "q" {
+		return m, tea.Quit
+	}
+
+	if m.screen == screenProjects {
+		return m.handleProjectsKey(msg)
+	}
+
+	return m.handleProjectKey(msg)
+}
+
+func (m Model) handleProjectsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "/":
+		m.searching = true
+		return m, m.search.Focus()
+	case "c":
+		m.category++
+		if m.category >= len(hangar.ProjectCategories) {
+			m.category = -1
+		}
+		m.offset = 0
+		return m, m.loadProjects()
+	case "C":
+		m.category--
+		if m.category < -1 {
+			m.category = len(hangar.ProjectCategories) - 1
+		}
+		m.offset = 0
+		return m, m.loadProjects()
+	case "]":
+		if int64(m.offset+pageSize) < m.total {
+			m.offset += pageSize
+			return m, m.loadProjects()
+		}
+		return m, nil
+	case "[":
+		if m.offset > 0 {
+			m.offset = max(0, m.offset-pageSize)
+			return m, m.loadProjects()
+		}
+		return m, nil
+	case "r":
+		return m, m.loadProjects()
+	case "enter":
+		if len(m.projects) == 0 {
+			return m, nil
+		}
+		return m.openProject(m.projects[m.list.Cursor()])
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleProjectKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "backspace":
+		m.screen = screenProjects
+		m.err = nil
+		m.status = fmt.Sprintf("%d projects", m.total)
+		return m, nil
+	case "tab":
+		m.tab = (m.tab + 1) % tab(len(tabNames))
+		return m, nil
+	case "shift+tab":
+		m.tab = (m.tab + tab(len(tabNames)) - 1) % tab(len(tabNames))
+		return m, nil
+	case "1", "2", "3":
+		m.tab = tab(msg.String()[0] - '1')
+		return m, nil
+	case "p":
+		m.platform = (m.platform + 1) % len(hangar.KnownPlatforms)
+		m.status = "Download platform: " + hangar.KnownPlatforms[m.platform]
+		return m, nil
+	case "d":
+		if m.tab == tabVersions {
+			return m.download()
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.tab {
+	case tabVersions:
+		m.table, cmd = m.table.Update(msg)
+	case tabReadme:
+		m.readme, cmd = m.readme.Update(msg)
+	case tabStats:
+	}
+
+	return m, cmd
+}
+
+// openProject switches to the project view and loads its details, versions, README and statistics.
+func (m Model) openProject(project hangar.Project) (tea.Model, tea.Cmd) {
+	m.screen, m.tab = screenProject, tabVersions
+	m.project = project
+	m.versions, m.stats, m.markdown = nil, nil, ""
+	m.table.SetRows(nil)
+	m.readme.SetContent("Loading…")
+	m.err = nil
+	m.status = "Loading " + project.Namespace.Slug + "…"
+
+	ctx, source := m.ctx, m.source
+	slug, owner := project.Namespace.Slug, project.Namespace.Owner
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, 1-statsDays)
+
+	return m, tea.Batch(
+		func() tea.Msg {
+			project, err := source.GetProject(ctx, slug)
+			return projectMsg{slug: slug, project: project, err: err}
+		},
+		func() tea.Msg {
+			list, err := source.ListVersions(ctx, owner, slug, hangar.ListOptions{Limit: hangar.MaxLimit})
+			if err != nil {
+				return versionsMsg{slug: slug, err: err}
+			}
+			return versionsMsg{slug: slug, versions: list.Result}
+		},
+		func() tea.Msg {
+			page, err := source.GetProjectMainPage(ctx, slug)
+			if err != nil {
+				return readmeMsg{slug: slug, err: err}
+			}
+			return readmeMsg{slug: slug, markdown: page.Contents}
+		},
+		func() tea.Msg {
+			stats, err := source.GetProjectStats(ctx, slug, from.Format(time.DateOnly), to.Format(time.DateOnly))
+			if err != nil {
+				return statsMsg{slug: slug, err: err}
+			}
+			series, err := stats.Series()
+			return statsMsg{slug: slug, stats: series.Fill(), err: err}
+		},
+	)
+}
+
+// download saves the selected version for the selected platform to the download directory.
+func (m Model) download() (tea.Model, tea.Cmd) {
+	if len(m.versions) == 0 {
+		return m, nil
+	}
+
+	version := m.versions[m.table.Cursor()]
+	platform := hangar.KnownPlatforms[m.platform]
+
+	downloadURL, ok := version.DownloadURL(platform)
+	if !ok {
+		return m.fail(errors.Newf("version %s has no %s download", version.Name, platform)), nil
+	}
+
+	name := version.FileName(m.project.Namespace.Slug, platform, downloadURL)
+	target := filepath.Join(m.opts.DownloadDir, name)
+	m.err = nil
+	m.status = "Downloading " + name + "…"
+
+	ctx, source := m.ctx, m.source
+
+	return m, func() tea.Msg {
+		size, err := saveFile(target, func(w io.Writer) (int64, error) {
+			return source.Download(ctx, downloadURL, w)
+		})
+		return downloadMsg{path: target, size: size, err: err}
+	}
+}
+
+// loadProjects requests the current page of projects. Earlier requests still in flight are ignored.
+func (m *Model) loadProjects() tea.Cmd {
+	m.request++
+	m.err = nil
+	m.status = "Loading projects…"
+
+	return m.fetchProjects()
+}
+
+// fetchProjects returns the command requesting the current page of projects.
+func (m Model) fetchProjects() tea.Cmd {
+	opts := hangar.ListOptions{Limit: pageSize, Offset: m.offset, Query: m.query}
+	if m.category >= 0 {
+		opts.Category = hangar.ProjectCategories[m.category]
+	}
+
+	ctx, source, request := m.ctx, m.source, m.request
+
+	return func() tea.Msg {
+		list, err := source.ListProjects(ctx, opts)
+		return projectsMsg{request: request, list: list, err: err}
+	}
+}
+
+// fail shows err in the status line.
+func (m Model) fail(err error) Model {
+	m.err = err
+	m.status = ""
+	return m
+}
+
+// saveFile writes the output of write to target through a temporary file,
+// so a failed download never leaves a truncated file behind.
+func saveFile(target string, write func(w io.Writer) (int64, error)) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".hangar-download-*")
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create file")
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	size, err := write(tmp)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = errors.Wrap(closeErr, "failed to write file")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return 0, errors.Wrap(err, "failed to save file")
+	}
+
+	return size, nil
+}
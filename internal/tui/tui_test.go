@@ -0,0 +1,345 @@
+package tui_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lexfrei/go-hangar/internal/tui"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource serves canned data and records list requests.
+// The project count of each list response is 100 plus the number of requests so far.
+type fakeSource struct {
+	mu       sync.Mutex
+	requests []hangar.ListOptions
+	owners   []string
+	versions []hangar.Version // served instead of the default version when set
+}
+
+var essentials = hangar.Project{
+	Name:        "EssentialsX",
+	Namespace:   hangar.Namespace{Owner: "EssentialsX", Slug: "Essentials"},
+	Category:    "admin_tools",
+	Description: "The essential plugin suite",
+}
+
+func (f *fakeSource) ListProjects(_ context.Context, opts hangar.ListOptions) (*hangar.ProjectsList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, opts)
+
+	return &hangar.ProjectsList{
+		Pagination: hangar.Pagination{Count: int64(100 + len(f.requests)), Limit: opts.Limit, Offset: opts.Offset},
+		Result:     []hangar.Project{essentials, {Name: "Chatty", Namespace: hangar.Namespace{Owner: "bob", Slug: "chatty"}}},
+	}, nil
+}
+
+func (f *fakeSource) GetProject(_ context.Context, slug string) (*hangar.Project, error) {
+	project := essentials
+	project.Description = "Fetched " + slug
+	return &project, nil
+}
+
+func (f *fakeSource) ListVersions(_ context.Context, owner, _ string, _ hangar.ListOptions) (*hangar.VersionsList, error) {
+	f.mu.Lock()
+	f.owners = append(f.owners, owner)
+	f.mu.Unlock()
+
+	if f.versions != nil {
+		return &hangar.VersionsList{Result: f.versions}, nil
+	}
+
+	return &hangar.VersionsList{Result: []hangar.Version{
+		{
+			Name:      "2.21.0",
+			CreatedAt: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+			Channel:   hangar.Channel{Name: "Release"},
+			Downloads: map[string]hangar.DownloadInfo{
+				"PAPER":    {DownloadURL: "https://hangar.example/files/paper", FileInfo: &hangar.FileInfo{Name: "EssentialsX-2.21.0.jar"}},
+				"VELOCITY": {ExternalURL: "https://example.com/releases/EssentialsX-velocity.jar"},
+			},
+		},
+	}}, nil
+}
+
+func (f *fakeSource) GetProjectMainPage(context.Context, string) (*hangar.Page, error) {
+	return &hangar.Page{Contents: "# Essentials\n\nEverything a server needs."}, nil
+}
+
+func (f *fakeSource) GetProjectStats(_ context.Context, _, fromDate, _ string) (hangar.ProjectStats, error) {
+	return hangar.ProjectStats{
+		fromDate:     {Downloads: 10, Views: 100},
+		"2099-01-01": {Downloads: 0, Views: 0},
+	}, nil
+}
+
+func (f *fakeSource) Download(_ context.Context, downloadURL string, w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, "jar from "+downloadURL)
+	return int64(n), err
+}
+
+func (f *fakeSource) lastRequest() hangar.ListOptions {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[len(f.requests)-1]
+}
+
+// run executes cmd and feeds the resulting messages back into the model.
+func run(t *testing.T, model tea.Model, cmd tea.Cmd) tui.Model {
+	t.Helper()
+
+	if cmd != nil {
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				model = run(t, model, c)
+			}
+		case nil:
+		default:
+			model, cmd = model.Update(msg)
+			model = run(t, model, cmd)
+		}
+	}
+
+	m, ok := model.(tui.Model)
+	require.True(t, ok)
+	return m
+}
+
+// press sends a key and runs the resulting command.
+func press(t *testing.T, m tui.Model, key tea.KeyMsg) tui.Model {
+	t.Helper()
+
+	model, cmd := m.Update(key)
+	return run(t, model, cmd)
+}
+
+func keys(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func start(t *testing.T, source *fakeSource, opts tui.Options) tui.Model {
+	t.Helper()
+
+	m := tui.New(context.Background(), source, opts)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	return run(t, model, m.Init())
+}
+
+func TestModel_ProjectList(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{}
+	m := start(t, source, tui.Options{Query: "ess", Category: "chat"})
+
+	assert.Equal(t, hangar.ListOptions{Limit: 50, Query: "ess", Category: "chat"}, source.lastRequest())
+	view := m.View()
+	assert.Contains(t, view, "EssentialsX")
+	assert.Contains(t, view, "1-2 of 101")
+	assert.Contains(t, view, "category: chat")
+
+	m = press(t, m, keys("c"))
+	assert.Equal(t, "dev_tools", source.lastRequest().Category)
+
+	m = press(t, m, keys("C"))
+	m = press(t, m, keys("C"))
+	m = press(t, m, keys("C"))
+	assert.Empty(t, source.lastRequest().Category)
+	assert.Contains(t, m.View(), "category: all")
+
+	m = press(t, m, keys("]"))
+	assert.Equal(t, 50, source.lastRequest().Offset)
+	m = press(t, m, keys("["))
+	assert.Equal(t, 0, source.lastRequest().Offset)
+	requests := len(source.requests)
+	_ = press(t, m, keys("["))
+	assert.Len(t, source.requests, requests, "no page before the first")
+}
+
+func TestModel_OptionsIgnoreCase(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := &fakeSource{}
+	m := start(t, source, tui.Options{Category: "CHAT", Platform: "velocity", DownloadDir: dir})
+	assert.Equal(t, "chat", source.lastRequest().Category)
+
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = press(t, m, keys("d"))
+	assert.Contains(t, m.View(), "Saved "+filepath.Join(dir, "EssentialsX-velocity.jar"))
+}
+
+func TestOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    tui.Options
+		wantErr string
+	}{
+		{name: "defaults"},
+		{name: "known values", opts: tui.Options{Category: "World_Management", Platform: "waterfall"}},
+		{name: "unknown category", opts: tui.Options{Category: "minigames"}, wantErr: `unknown category "minigames"`},
+		{name: "unknown platform", opts: tui.Options{Platform: "folia"}, wantErr: `unknown platform "folia"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.opts.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRun_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{}
+	err := tui.Run(context.Background(), source, tui.Options{Platform: "folia"})
+	require.ErrorContains(t, err, "unknown platform")
+	assert.Empty(t, source.requests)
+}
+
+func TestModel_Search(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{}
+	m := start(t, source, tui.Options{})
+
+	model, _ := m.Update(keys("/"))
+	for _, r := range "chat" {
+		model, _ = model.Update(keys(string(r)))
+	}
+	m = run(t, model, nil)
+	assert.Len(t, source.requests, 1, "typing does not search")
+
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "chat", source.lastRequest().Query)
+
+	// Escape discards an edited query
+	model, _ = m.Update(keys("/"))
+	model, _ = model.Update(keys("x"))
+	m = press(t, run(t, model, nil), tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Len(t, source.requests, 2)
+	assert.Contains(t, m.View(), "chat")
+	assert.NotContains(t, m.View(), "chatx")
+}
+
+func TestModel_StaleProjects(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{}
+	m := start(t, source, tui.Options{})
+
+	model, first := m.Update(keys("c"))
+	model, second := model.Update(keys("c"))
+
+	// The response to the first request arrives last and is dropped
+	model, _ = model.Update(second())
+	model, _ = model.Update(first())
+
+	assert.Contains(t, model.View(), "category: chat")
+	assert.Contains(t, model.View(), "1-2 of 102")
+}
+
+func TestModel_Project(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{}
+	m := start(t, source, tui.Options{MarkdownStyle: "notty"})
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, []string{"EssentialsX"}, source.owners)
+	view := m.View()
+	assert.Contains(t, view, "Fetched Essentials")
+	assert.Contains(t, view, "2.21.0")
+	assert.Contains(t, view, "PAPER, VELOCITY")
+
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyTab})
+	assert.Contains(t, m.View(), "Everything a server needs.")
+
+	m = press(t, m, keys("3"))
+	assert.Contains(t, m.View(), "total 10")
+	assert.Contains(t, m.View(), "Downloads")
+
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyShiftTab})
+	assert.Contains(t, m.View(), "Everything a server needs.")
+
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Contains(t, m.View(), "Hangar projects")
+}
+
+func TestModel_Download(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := &fakeSource{}
+	m := start(t, source, tui.Options{DownloadDir: dir})
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	m = press(t, m, keys("d"))
+	assert.Contains(t, m.View(), "Saved "+filepath.Join(dir, "EssentialsX-2.21.0.jar"))
+
+	content, err := os.ReadFile(filepath.Join(dir, "EssentialsX-2.21.0.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, "jar from https://hangar.example/files/paper", string(content))
+
+	m = press(t, m, keys("p"))
+	m = press(t, m, keys("d"))
+	_, err = os.Stat(filepath.Join(dir, "EssentialsX-velocity.jar"))
+	require.NoError(t, err)
+
+	m = press(t, m, keys("p"))
+	m = press(t, m, keys("d"))
+	assert.Contains(t, m.View(), "has no WATERFALL download")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "no temporary files are left behind")
+}
+
+func TestModel_DownloadHostileNames(t *testing.T) {
+	t.Parallel()
+
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "plugins")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+
+	source := &fakeSource{versions: []hangar.Version{
+		{
+			Name: "../../1.0",
+			Downloads: map[string]hangar.DownloadInfo{
+				"PAPER":    {DownloadURL: "https://hangar.example/files/paper", FileInfo: &hangar.FileInfo{Name: ".."}},
+				"VELOCITY": {DownloadURL: "https://hangar.example/files/velocity", FileInfo: &hangar.FileInfo{Name: `..\..\evil.jar`}},
+			},
+		},
+	}}
+	m := start(t, source, tui.Options{DownloadDir: dir})
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	m = press(t, m, keys("d"))
+	assert.Contains(t, m.View(), "Saved "+filepath.Join(dir, "Essentials-.._.._1.0.jar"))
+
+	m = press(t, m, keys("p"))
+	m = press(t, m, keys("d"))
+	assert.Contains(t, m.View(), "Saved "+filepath.Join(dir, "evil.jar"))
+
+	entries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "nothing is written outside the download directory")
+}
@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lexfrei/go-hangar/internal/chart"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+)
+
+var (
+	titleStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	activeTabStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+	tabStyle       = lipgloss.NewStyle().Faint(true)
+	helpStyle      = lipgloss.NewStyle().Faint(true)
+	errorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// chromeLines is the number of lines used by the header, status and help lines.
+const chromeLines = 5
+
+// View renders the current screen.
+func (m Model) View() string {
+	var body, help string
+
+	switch m.screen {
+	case screenProjects:
+		body = m.list.View()
+		help = "/ search  c/C category  [/] page  enter open  r reload  q quit"
+	case screenProject:
+		body = m.projectView()
+		help = "tab switch  d download  p platform  esc back  q quit"
+	}
+
+	status := m.status
+	if m.err != nil {
+		status = errorStyle.Render("Error: " + m.err.Error())
+	}
+
+	return strings.Join([]string{m.header(), body, status, helpStyle.Render(help)}, "\n")
+}
+
+// header renders the title line and, on the project list, the search and category line.
+func (m Model) header() string {
+	if m.screen == screenProject {
+		title := titleStyle.Render(m.project.Name) + "  " + m.project.Namespace.Owner + "/" + m.project.Namespace.Slug
+		return title + "\n" + truncate(m.project.Description, m.width)
+	}
+
+	category := "all"
+	if m.category >= 0 {
+		category = hangar.ProjectCategories[m.category]
+	}
+
+	search := m.search.View()
+	if !m.searching && m.query == "" {
+		search = helpStyle.Render("/ search")
+	}
+
+	pages := ""
+	if m.total > 0 {
+		pages = fmt.Sprintf("  %d-%d of %d", m.offset+1, m.offset+len(m.projects), m.total)
+	}
+
+	return titleStyle.Render("Hangar projects") + pages + "\n" + search + "  category: " + category
+}
+
+// projectView renders the tab bar and the selected tab.
+func (m Model) projectView() string {
+	tabs := make([]string, len(tabNames))
+	for i, name := range tabNames {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		if tab(i) == m.tab {
+			tabs[i] = activeTabStyle.Render(label)
+		} else {
+			tabs[i] = tabStyle.Render(label)
+		}
+	}
+	bar := strings.Join(tabs, "  ")
+
+	switch m.tab {
+	case tabReadme:
+		return bar + "\n" + m.readme.View()
+	case tabStats:
+		return bar + "\n" + m.statsView()
+	default:
+		return bar + fmt.Sprintf("  (platform %s)", hangar.KnownPlatforms[m.platform]) + "\n" + m.table.View()
+	}
+}
+
+// statsView renders totals and sparklines of the recent daily statistics.
+func (m Model) statsView() string {
+	if m.stats == nil {
+		return "Loading statistics…"
+	}
+	if len(m.stats) == 0 {
+		return "No statistics in the last " + strconv.Itoa(statsDays) + " days."
+	}
+
+	downloads := make([]float64, len(m.stats))
+	views := make([]float64, len(m.stats))
+	for i, point := range m.stats {
+		downloads[i], views[i] = float64(point.Downloads), float64(point.Views)
+	}
+
+	totals := m.stats.Totals()
+	width := max(10, m.width-12)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s to %s\n\n", m.stats[0].Period, m.stats[len(m.stats)-1].Period)
+	fmt.Fprintf(&sb, "Downloads  %s\n", chart.Sparkline(downloads, width, false))
+	fmt.Fprintf(&sb, "           total %d  %s\n\n", totals.Downloads, chart.Summarize(downloads))
+	fmt.Fprintf(&sb, "Views      %s\n", chart.Sparkline(views, width, false))
+	fmt.Fprintf(&sb, "           total %d  %s\n\n", totals.Views, chart.Summarize(views))
+	fmt.Fprintf(&sb, "All time   %d downloads, %d views, %d stars, %d watchers",
+		m.project.Stats.Downloads, m.project.Stats.Views, m.project.Stats.Stars, m.project.Stats.Watchers)
+
+	return sb.String()
+}
+
+// renderMarkdown renders the README for the current width, falling back to the raw text.
+func (m Model) renderMarkdown() string {
+	if m.markdown == "" {
+		return "This project has no README."
+	}
+
+	style := m.opts.MarkdownStyle
+	if style == "" {
+		style = "dark"
+	}
+
+	renderer, err := glamour.NewTermRenderer(glamour.WithStandardStyle(style), glamour.WithWordWrap(max(20, m.width-4)))
+	if err != nil {
+		return m.markdown
+	}
+
+	rendered, err := renderer.Render(m.markdown)
+	if err != nil {
+		return m.markdown
+	}
+
+	return rendered
+}
+
+// resize fits the tables and the README pane to the window.
+func (m *Model) resize() {
+	height := max(3, m.height-chromeLines-1)
+
+	m.list.SetColumns(projectTableColumns(m.width))
+	m.list.SetWidth(m.width)
+	m.list.SetHeight(height)
+
+	m.table.SetColumns(versionTableColumns(m.width))
+	m.table.SetWidth(m.width)
+	m.table.SetHeight(height)
+
+	m.readme.Width, m.readme.Height = m.width, height
+	if m.markdown != "" {
+		m.readme.SetContent(m.renderMarkdown())
+	}
+}
+
+// projectTableColumns sizes the project columns, giving the remaining width to the description.
+func projectTableColumns(width int) []table.Column {
+	columns := []table.Column{
+		{Title: "Name", Width: 24},
+		{Title: "Owner", Width: 16},
+		{Title: "Category", Width: 16},
+		{Title: "Downloads", Width: 10},
+		{Title: "Stars", Width: 6},
+	}
+
+	return append(columns, table.Column{Title: "Description", Width: remaining(width, columns)})
+}
+
+// versionTableColumns sizes the version columns, giving the remaining width to the game versions.
+func versionTableColumns(width int) []table.Column {
+	columns := []table.Column{
+		{Title: "Version", Width: 20},
+		{Title: "Channel", Width: 10},
+		{Title: "Released", Width: 10},
+		{Title: "Downloads", Width: 10},
+		{Title: "Platforms", Width: 24},
+	}
+
+	return append(columns, table.Column{Title: "Game Versions", Width: remaining(width, columns)})
+}
+
+// remaining returns the width left for a last column, accounting for cell padding.
+func remaining(width int, columns []table.Column) int {
+	used := 2
+	for _, column := range columns {
+		used += column.Width + 2
+	}
+
+	return max(10, width-used)
+}
+
+// projectRows converts projects into table rows.
+func projectRows(projects []hangar.Project) []table.Row {
+	rows := make([]table.Row, len(projects))
+	for i, p := range projects {
+		rows[i] = table.Row{
+			p.Name, p.Namespace.Owner, p.Category,
+			strconv.FormatInt(p.Stats.Downloads, 10), strconv.FormatInt(p.Stats.Stars, 10),
+			p.Description,
+		}
+	}
+
+	return rows
+}
+
+// versionRows converts versions into table rows.
+func versionRows(versions []hangar.Version) []table.Row {
+	rows := make([]table.Row, len(versions))
+	for i, v := range versions {
+		platforms := make([]string, 0, len(v.Downloads))
+		for _, platform := range hangar.KnownPlatforms {
+			if _, ok := v.Downloads[platform]; ok {
+				platforms = append(platforms, platform)
+			}
+		}
+
+		rows[i] = table.Row{
+			v.Name, v.Channel.Name, v.CreatedAt.Format("2006-01-02"),
+			strconv.FormatInt(v.Stats.TotalDownloads, 10),
+			strings.Join(platforms, ", "), strings.Join(v.GameVersions, ", "),
+		}
+	}
+
+	return rows
+}
+
+// truncate shortens s to the first line and at most width characters.
+func truncate(s string, width int) string {
+	s, _, _ = strings.Cut(s, "\n")
+	if runes := []rune(s); width > 1 && len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+
+	return s
+}
+
+// formatBytes formats a file size with a binary unit.
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
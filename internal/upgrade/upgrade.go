@@ -0,0 +1,244 @@
+// Package upgrade plans Minecraft version upgrades by checking which plugins have versions
+// supporting the target game version.
+package upgrade
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/manifest"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultConcurrency is the number of projects checked at the same time by default.
+const DefaultConcurrency = 4
+
+// Upgrade statuses of a plugin.
+const (
+	// StatusReady means the installed version already supports the target.
+	StatusReady = "ready"
+	// StatusUpdate means a newer version supports the target, but the installed one does not.
+	StatusUpdate = "update"
+	// StatusCompatible means a version supports the target; used when the installed version is unknown.
+	StatusCompatible = "compatible"
+	// StatusBlocked means no version supports the target.
+	StatusBlocked = "blocked"
+)
+
+// Client is the part of the Hangar API used to plan upgrades.
+type Client interface {
+	GetProject(ctx context.Context, slug string) (*hangar.Project, error)
+	ListVersions(ctx context.Context, owner, slug string, opts hangar.ListOptions) (*hangar.VersionsList, error)
+}
+
+// Plugin is a plugin to check.
+type Plugin struct {
+	// Slug is the project slug.
+	Slug string
+	// Platform is the server platform (defaults to manifest.DefaultPlatform).
+	Platform string
+	// Current is the installed version, if known.
+	Current string
+}
+
+// Options configure an upgrade check.
+type Options struct {
+	// Target is the Minecraft version to upgrade to, e.g. "1.21.1".
+	Target string
+	// Channels limits the upgrade targets to these release channels; all channels if empty.
+	// The installed version is ready if it supports the target, whatever its channel.
+	Channels []string
+	// Concurrency is the number of projects checked at the same time.
+	Concurrency int
+}
+
+// Result is the upgrade outlook of a plugin.
+type Result struct {
+	// Slug is the project slug.
+	Slug string `json:"slug"`
+	// Owner is the project owner.
+	Owner string `json:"owner"`
+	// Platform is the platform that was checked.
+	Platform string `json:"platform"`
+	// Current is the installed version, if known.
+	Current string `json:"current,omitempty"`
+	// Status is one of the Status constants.
+	Status string `json:"status"`
+	// Earliest is the first published version supporting the target.
+	Earliest string `json:"earliest,omitempty"`
+	// EarliestAt is when Earliest was published.
+	EarliestAt time.Time `json:"earliestAt,omitzero"`
+	// Latest is the last published version supporting the target.
+	Latest string `json:"latest,omitempty"`
+	// LatestAt is when Latest was published.
+	LatestAt time.Time `json:"latestAt,omitzero"`
+	// LastSupported is the newest Minecraft version supported by any version of a blocked plugin.
+	LastSupported string `json:"lastSupported,omitempty"`
+}
+
+// Plan checks every plugin for versions supporting the target Minecraft version. Results
+// are returned in the order of plugins.
+func Plan(ctx context.Context, client Client, plugins []Plugin, opts Options) ([]Result, error) {
+	if opts.Target == "" {
+		return nil, errors.New("target Minecraft version cannot be empty")
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+
+	results := make([]Result, len(plugins))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(opts.Concurrency)
+	for i, plugin := range plugins {
+		group.Go(func() error {
+			result, err := check(groupCtx, client, plugin, opts)
+			if err != nil {
+				return errors.Wrapf(err, "failed to check %s", plugin.Slug)
+			}
+			results[i] = *result
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Blocked returns the number of blocked plugins.
+func Blocked(results []Result) int {
+	blocked := 0
+	for _, result := range results {
+		if result.Status == StatusBlocked {
+			blocked++
+		}
+	}
+
+	return blocked
+}
+
+// check plans the upgrade of a single plugin.
+func check(ctx context.Context, client Client, plugin Plugin, opts Options) (*Result, error) {
+	platform := strings.ToUpper(plugin.Platform)
+	if platform == "" {
+		platform = manifest.DefaultPlatform
+	}
+
+	project, err := client.GetProject(ctx, plugin.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Slug:     project.Namespace.Slug,
+		Owner:    project.Namespace.Owner,
+		Platform: platform,
+		Current:  plugin.Current,
+		Status:   StatusBlocked,
+	}
+
+	// The API filters by platform version; the platform dependencies are checked again so
+	// the result does not depend on how the filter matches versions
+	candidates, err := listVersions(ctx, client, project, hangar.ListOptions{Platform: platform, PlatformVersion: opts.Target})
+	if err != nil {
+		return nil, err
+	}
+
+	// The installed version is ready whatever its channel; the channels only limit the upgrade targets
+	var earliest, latest *hangar.Version
+	for i := range candidates {
+		version := &candidates[i]
+		if !manifest.Supports(version, platform, opts.Target) {
+			continue
+		}
+		if version.Name == plugin.Current {
+			result.Status = StatusReady
+		}
+		if !eligible(version, platform, opts.Channels) {
+			continue
+		}
+
+		if earliest == nil || version.CreatedAt.Before(earliest.CreatedAt) {
+			earliest = version
+		}
+		if latest == nil || version.CreatedAt.After(latest.CreatedAt) {
+			latest = version
+		}
+	}
+
+	switch {
+	case latest == nil && result.Status == StatusReady:
+		return result, nil
+	case latest == nil:
+		result.LastSupported, err = lastSupported(ctx, client, project, platform, opts.Channels)
+		return result, err
+	}
+
+	result.Earliest, result.EarliestAt = earliest.Name, earliest.CreatedAt
+	result.Latest, result.LatestAt = latest.Name, latest.CreatedAt
+	switch {
+	case result.Status == StatusReady:
+	case plugin.Current == "":
+		result.Status = StatusCompatible
+	default:
+		result.Status = StatusUpdate
+	}
+
+	return result, nil
+}
+
+// lastSupported returns the newest Minecraft version supported by any version of the project.
+func lastSupported(ctx context.Context, client Client, project *hangar.Project, platform string, channels []string) (string, error) {
+	versions, err := listVersions(ctx, client, project, hangar.ListOptions{Platform: platform})
+	if err != nil {
+		return "", err
+	}
+
+	newest := ""
+	for i := range versions {
+		if !eligible(&versions[i], platform, channels) {
+			continue
+		}
+		for _, minecraftVersion := range versions[i].PlatformDependencies[platform] {
+			if newest == "" || manifest.CompareVersions(minecraftVersion, newest) > 0 {
+				newest = minecraftVersion
+			}
+		}
+	}
+
+	return newest, nil
+}
+
+// eligible reports whether a version has a download for the platform and is in one of the channels.
+func eligible(version *hangar.Version, platform string, channels []string) bool {
+	if _, ok := version.DownloadURL(platform); !ok {
+		return false
+	}
+	if len(channels) == 0 {
+		return true
+	}
+	for _, channel := range channels {
+		if strings.EqualFold(version.Channel.Name, channel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listVersions fetches every version of the project matching the filters.
+func listVersions(ctx context.Context, client Client, project *hangar.Project, opts hangar.ListOptions) ([]hangar.Version, error) {
+	versions, _, err := hangar.CollectAll(ctx, opts, func(ctx context.Context, opts hangar.ListOptions) ([]hangar.Version, hangar.Pagination, error) {
+		page, err := client.ListVersions(ctx, project.Namespace.Owner, project.Namespace.Slug, opts)
+		if err != nil {
+			return nil, hangar.Pagination{}, err
+		}
+		return page.Result, page.Pagination, nil
+	})
+
+	return versions, err
+}
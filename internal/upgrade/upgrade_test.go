@@ -0,0 +1,137 @@
+package upgrade_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/internal/upgrade"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient serves versions from memory, ignoring the filters like an older API would,
+// and records the filters it was called with.
+type fakeClient struct {
+	versions map[string][]hangar.Version
+
+	mu      sync.Mutex
+	filters []string
+}
+
+func (f *fakeClient) GetProject(_ context.Context, slug string) (*hangar.Project, error) {
+	if _, ok := f.versions[slug]; !ok {
+		return nil, errors.Newf("project %s not found", slug)
+	}
+	return &hangar.Project{Namespace: hangar.Namespace{Owner: "owner", Slug: slug}}, nil
+}
+
+func (f *fakeClient) ListVersions(_ context.Context, _, slug string, opts hangar.ListOptions) (*hangar.VersionsList, error) {
+	f.mu.Lock()
+	f.filters = append(f.filters, slug+":"+opts.Platform+":"+opts.PlatformVersion)
+	f.mu.Unlock()
+
+	versions := f.versions[slug]
+	return &hangar.VersionsList{
+		Pagination: hangar.Pagination{Count: int64(len(versions))},
+		Result:     versions,
+	}, nil
+}
+
+func version(name, channel string, day int, minecraft ...string) hangar.Version {
+	return hangar.Version{
+		Name:                 name,
+		CreatedAt:            time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+		Channel:              hangar.Channel{Name: channel},
+		Downloads:            map[string]hangar.DownloadInfo{"PAPER": {DownloadURL: "https://hangar.test/" + name}},
+		PlatformDependencies: map[string][]string{"PAPER": minecraft},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{versions: map[string][]hangar.Version{
+		"alpha": {
+			version("3.0-beta", "Beta", 20, "1.21.1"),
+			version("2.1", "Release", 15, "1.21", "1.21.1"),
+			version("2.0", "Release", 10, "1.21.1"),
+			version("1.0", "Release", 1, "1.20.4"),
+		},
+		"beta": {
+			version("1.2", "Release", 5, "1.20.2", "1.20.10", "1.20.4"),
+			version("1.1", "Release", 3, "1.20"),
+		},
+		"gamma": {version("5.0", "Release", 8, "1.21.1")},
+	}}
+
+	results, err := upgrade.Plan(context.Background(), client, []upgrade.Plugin{
+		{Slug: "alpha", Current: "1.0"},
+		{Slug: "beta", Current: "1.2"},
+		{Slug: "gamma", Current: "5.0"},
+		{Slug: "alpha"},
+	}, upgrade.Options{Target: "1.21.1", Channels: []string{"release"}, Concurrency: 2})
+	require.NoError(t, err)
+
+	day := func(day int) time.Time { return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC) }
+	assert.Equal(t, []upgrade.Result{
+		{
+			Slug: "alpha", Owner: "owner", Platform: "PAPER", Current: "1.0", Status: upgrade.StatusUpdate,
+			Earliest: "2.0", EarliestAt: day(10), Latest: "2.1", LatestAt: day(15),
+		},
+		{
+			Slug: "beta", Owner: "owner", Platform: "PAPER", Current: "1.2", Status: upgrade.StatusBlocked,
+			LastSupported: "1.20.10",
+		},
+		{
+			Slug: "gamma", Owner: "owner", Platform: "PAPER", Current: "5.0", Status: upgrade.StatusReady,
+			Earliest: "5.0", EarliestAt: day(8), Latest: "5.0", LatestAt: day(8),
+		},
+		{
+			Slug: "alpha", Owner: "owner", Platform: "PAPER", Status: upgrade.StatusCompatible,
+			Earliest: "2.0", EarliestAt: day(10), Latest: "2.1", LatestAt: day(15),
+		},
+	}, results)
+	assert.Equal(t, 1, upgrade.Blocked(results))
+	assert.Contains(t, client.filters, "beta:PAPER:1.21.1")
+	assert.Contains(t, client.filters, "beta:PAPER:", "blocked plugins are checked for the last supported version")
+
+	_, err = upgrade.Plan(context.Background(), client, []upgrade.Plugin{{Slug: "alpha"}}, upgrade.Options{})
+	require.Error(t, err)
+}
+
+func TestPlan_CurrentOutsideChannels(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{versions: map[string][]hangar.Version{
+		"alpha": {
+			version("3.0-beta", "Beta", 20, "1.21.1"),
+			version("2.1", "Release", 15, "1.21.1"),
+		},
+		"beta": {
+			version("2.0-beta", "Beta", 12, "1.21.1"),
+			version("1.0", "Release", 1, "1.20.4"),
+		},
+	}}
+
+	results, err := upgrade.Plan(context.Background(), client, []upgrade.Plugin{
+		{Slug: "alpha", Current: "3.0-beta"},
+		{Slug: "beta", Current: "2.0-beta"},
+	}, upgrade.Options{Target: "1.21.1", Channels: []string{"Release"}})
+	require.NoError(t, err)
+
+	day := func(day int) time.Time { return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC) }
+	assert.Equal(t, []upgrade.Result{
+		{
+			Slug: "alpha", Owner: "owner", Platform: "PAPER", Current: "3.0-beta", Status: upgrade.StatusReady,
+			Earliest: "2.1", EarliestAt: day(15), Latest: "2.1", LatestAt: day(15),
+		},
+		{
+			Slug: "beta", Owner: "owner", Platform: "PAPER", Current: "2.0-beta", Status: upgrade.StatusReady,
+		},
+	}, results, "an installed version supporting the target is ready whatever its channel")
+	assert.Zero(t, upgrade.Blocked(results))
+}
@@ -0,0 +1,216 @@
+package hangar
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// KnownPlatforms lists the Hangar platforms in display order.
+var KnownPlatforms = []string{"PAPER", "VELOCITY", "WATERFALL"}
+
+// VersionAdoption describes how a single version was adopted.
+type VersionAdoption struct {
+	// Version is the version name.
+	Version string `json:"version"`
+	// Channel is the release channel name.
+	Channel string `json:"channel"`
+	// ReleasedAt is when the version was created.
+	ReleasedAt time.Time `json:"releasedAt"`
+	// Downloads is the number of downloads within the analyzed date range.
+	Downloads int64 `json:"downloads"`
+	// TotalDownloads is the all-time download count.
+	TotalDownloads int64 `json:"totalDownloads"`
+	// PlatformDownloads is the all-time download count per platform.
+	PlatformDownloads map[string]int64 `json:"platformDownloads"`
+	// PeakShare is the highest daily share of the project's downloads in percent.
+	PeakShare float64 `json:"peakShare"`
+	// DaysToThreshold is the number of days from release until the daily share first
+	// reached the adoption threshold (nil if it never did within the range).
+	DaysToThreshold *int `json:"daysToThreshold"`
+}
+
+// AdoptionPeriod contains the download share of every version in a period.
+type AdoptionPeriod struct {
+	// Period is the period label.
+	Period string `json:"period"`
+	// Downloads is the number of downloads of the project in the period.
+	Downloads int64 `json:"downloads"`
+	// Shares is the share of downloads per version name in percent.
+	Shares map[string]float64 `json:"shares"`
+}
+
+// AdoptionReport combines per-version statistics into adoption metrics.
+type AdoptionReport struct {
+	// Threshold is the share in percent a version must reach to count as adopted.
+	Threshold float64 `json:"threshold"`
+	// Versions lists the versions, newest first.
+	Versions []VersionAdoption `json:"versions"`
+	// Periods contains the download share per version over time.
+	Periods []AdoptionPeriod `json:"periods"`
+	// PlatformDownloads is the all-time download count per platform across the versions.
+	PlatformDownloads map[string]int64 `json:"platformDownloads"`
+	// ProjectTotals reports whether shares are relative to the downloads of the whole project
+	// (true) or only of the analyzed versions (false).
+	ProjectTotals bool `json:"projectTotals"`
+}
+
+// BuildAdoption computes adoption metrics from versions and their daily statistics keyed by version name.
+// threshold is the daily share in percent (e.g. 50) used for DaysToThreshold.
+//
+// Shares are relative to the daily downloads of the whole project in projectStats, so versions
+// that are not analyzed still count towards the total. Without projectStats the shares only
+// cover the analyzed versions.
+func BuildAdoption(versions []Version, stats map[string]VersionStatsData, projectStats ProjectStats, period StatsPeriod, threshold float64) (*AdoptionReport, error) {
+	if threshold <= 0 || threshold > 100 {
+		return nil, errors.Newf("adoption threshold must be in (0, 100], got %g", threshold)
+	}
+
+	report := &AdoptionReport{
+		Threshold:         threshold,
+		PlatformDownloads: make(map[string]int64),
+	}
+
+	// Daily downloads of every version on a common timeline
+	daily := make(map[string]map[string]int64, len(versions))
+	dates := make(map[string]DailyStats)
+	for _, version := range versions {
+		series, err := stats[version.Name].Series()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse stats for version %s", version.Name)
+		}
+
+		daily[version.Name] = make(map[string]int64, len(series))
+		for _, point := range series {
+			daily[version.Name][point.Period] = point.Downloads
+			dates[point.Period] = DailyStats{}
+		}
+	}
+
+	projectSeries, err := projectStats.Series()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse project stats")
+	}
+	for _, point := range projectSeries {
+		dates[point.Period] = DailyStats{}
+	}
+
+	timeline, err := NewStatsSeries(dates)
+	if err != nil {
+		return nil, err
+	}
+	timeline = timeline.Fill()
+
+	totals := make(map[string]int64, len(timeline))
+	for _, day := range timeline {
+		for _, version := range versions {
+			totals[day.Period] += daily[version.Name][day.Period]
+		}
+	}
+	// Project and version statistics are counted separately, so the analyzed versions can
+	// exceed the project total on a day; the larger value keeps shares within 100%
+	for _, point := range projectSeries {
+		totals[point.Period] = max(totals[point.Period], point.Downloads)
+	}
+	report.ProjectTotals = len(projectSeries) > 0
+
+	for _, version := range versions {
+		adoption := VersionAdoption{
+			Version:           version.Name,
+			Channel:           version.Channel.Name,
+			ReleasedAt:        version.CreatedAt,
+			TotalDownloads:    version.Stats.TotalDownloads,
+			PlatformDownloads: version.Stats.PlatformDownloads,
+		}
+		released := PeriodDay.Start(version.CreatedAt)
+
+		for _, day := range timeline {
+			downloads := daily[version.Name][day.Period]
+			adoption.Downloads += downloads
+			if totals[day.Period] == 0 {
+				continue
+			}
+
+			share := float64(downloads) / float64(totals[day.Period]) * 100
+			adoption.PeakShare = math.Max(adoption.PeakShare, share)
+
+			if adoption.DaysToThreshold == nil && share >= threshold && !day.Start.Before(released) {
+				days := int(day.Start.Sub(released).Hours() / 24)
+				adoption.DaysToThreshold = &days
+			}
+		}
+
+		for platform, downloads := range version.Stats.PlatformDownloads {
+			report.PlatformDownloads[platform] += downloads
+		}
+
+		report.Versions = append(report.Versions, adoption)
+	}
+
+	sort.SliceStable(report.Versions, func(i, j int) bool {
+		return report.Versions[i].ReleasedAt.After(report.Versions[j].ReleasedAt)
+	})
+
+	report.Periods = adoptionPeriods(versions, daily, totals, timeline, period)
+
+	return report, nil
+}
+
+// adoptionPeriods groups daily downloads per version into periods and converts them to shares
+// of the daily totals.
+func adoptionPeriods(versions []Version, daily map[string]map[string]int64, totals map[string]int64, timeline StatsSeries, period StatsPeriod) []AdoptionPeriod {
+	var periods []AdoptionPeriod
+	index := make(map[string]int)
+	perVersion := make(map[string]map[string]int64)
+
+	for _, day := range timeline {
+		label := period.Label(day.Start)
+		if _, ok := index[label]; !ok {
+			index[label] = len(periods)
+			periods = append(periods, AdoptionPeriod{Period: label, Shares: make(map[string]float64)})
+			perVersion[label] = make(map[string]int64)
+		}
+
+		periods[index[label]].Downloads += totals[day.Period]
+		for _, version := range versions {
+			perVersion[label][version.Name] += daily[version.Name][day.Period]
+		}
+	}
+
+	for i := range periods {
+		if periods[i].Downloads == 0 {
+			continue
+		}
+		for name, downloads := range perVersion[periods[i].Period] {
+			periods[i].Shares[name] = float64(downloads) / float64(periods[i].Downloads) * 100
+		}
+	}
+
+	return periods
+}
+
+// SortPlatforms returns the platform names of downloads with known platforms first.
+func SortPlatforms(downloads map[string]int64) []string {
+	platforms := make([]string, 0, len(downloads))
+	for _, platform := range KnownPlatforms {
+		if _, ok := downloads[platform]; ok {
+			platforms = append(platforms, platform)
+		}
+	}
+
+	var others []string
+	for platform := range downloads {
+		known := false
+		for _, k := range KnownPlatforms {
+			known = known || k == platform
+		}
+		if !known {
+			others = append(others, platform)
+		}
+	}
+	sort.Strings(others)
+
+	return append(platforms, others...)
+}
@@ -0,0 +1,147 @@
+package hangar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAdoption(t *testing.T) {
+	t.Parallel()
+
+	versions := []hangar.Version{
+		{
+			Name:      "1.0",
+			CreatedAt: time.Date(2023, time.December, 1, 10, 0, 0, 0, time.UTC),
+			Channel:   hangar.Channel{Name: "Release"},
+			Stats: hangar.VersionStats{
+				TotalDownloads:    500,
+				PlatformDownloads: map[string]int64{"PAPER": 400, "VELOCITY": 100},
+			},
+		},
+		{
+			Name:      "2.0",
+			CreatedAt: time.Date(2024, time.January, 2, 15, 0, 0, 0, time.UTC),
+			Channel:   hangar.Channel{Name: "Release"},
+			Stats: hangar.VersionStats{
+				TotalDownloads:    90,
+				PlatformDownloads: map[string]int64{"PAPER": 80, "WATERFALL": 10},
+			},
+		},
+	}
+
+	stats := map[string]hangar.VersionStatsData{
+		"1.0": {
+			"2024-01-01": {Downloads: 10},
+			"2024-01-02": {Downloads: 9},
+			"2024-01-03": {Downloads: 6},
+			"2024-01-04": {Downloads: 2},
+		},
+		"2.0": {
+			"2024-01-02": {Downloads: 1},
+			"2024-01-03": {Downloads: 4},
+			"2024-01-04": {Downloads: 8},
+		},
+	}
+
+	report, err := hangar.BuildAdoption(versions, stats, nil, hangar.PeriodDay, 50)
+	require.NoError(t, err)
+
+	require.Len(t, report.Versions, 2)
+	newest := report.Versions[0]
+	assert.Equal(t, "2.0", newest.Version)
+	assert.Equal(t, int64(13), newest.Downloads)
+	require.NotNil(t, newest.DaysToThreshold)
+	assert.Equal(t, 2, *newest.DaysToThreshold)
+	assert.InDelta(t, 80.0, newest.PeakShare, 0.001)
+
+	oldest := report.Versions[1]
+	assert.Equal(t, "1.0", oldest.Version)
+	require.NotNil(t, oldest.DaysToThreshold)
+	assert.Equal(t, 31, *oldest.DaysToThreshold)
+
+	assert.Equal(t, map[string]int64{"PAPER": 480, "VELOCITY": 100, "WATERFALL": 10}, report.PlatformDownloads)
+	assert.False(t, report.ProjectTotals)
+
+	require.Len(t, report.Periods, 4)
+	assert.Equal(t, int64(10), report.Periods[1].Downloads)
+	assert.InDelta(t, 90.0, report.Periods[1].Shares["1.0"], 0.001)
+	assert.InDelta(t, 10.0, report.Periods[1].Shares["2.0"], 0.001)
+}
+
+func TestBuildAdoption_ProjectTotals(t *testing.T) {
+	t.Parallel()
+
+	// Only the newest version is analyzed; older versions still make up most downloads
+	versions := []hangar.Version{
+		{Name: "2.0", CreatedAt: time.Date(2024, time.January, 2, 15, 0, 0, 0, time.UTC)},
+	}
+	stats := map[string]hangar.VersionStatsData{
+		"2.0": {
+			"2024-01-02": {Downloads: 1},
+			"2024-01-03": {Downloads: 4},
+			"2024-01-04": {Downloads: 8},
+		},
+	}
+	project := hangar.ProjectStats{
+		"2024-01-01": {Downloads: 10},
+		"2024-01-02": {Downloads: 10},
+		"2024-01-03": {Downloads: 10},
+		"2024-01-04": {Downloads: 5},
+	}
+
+	report, err := hangar.BuildAdoption(versions, stats, project, hangar.PeriodDay, 50)
+	require.NoError(t, err)
+
+	assert.True(t, report.ProjectTotals)
+	require.Len(t, report.Periods, 4)
+	assert.Equal(t, "2024-01-01", report.Periods[0].Period)
+	assert.Equal(t, int64(10), report.Periods[1].Downloads)
+	assert.InDelta(t, 10.0, report.Periods[1].Shares["2.0"], 0.001)
+	assert.InDelta(t, 40.0, report.Periods[2].Shares["2.0"], 0.001)
+	assert.Equal(t, int64(8), report.Periods[3].Downloads, "version downloads above the project total count instead")
+	assert.InDelta(t, 100.0, report.Periods[3].Shares["2.0"], 0.001)
+
+	require.NotNil(t, report.Versions[0].DaysToThreshold)
+	assert.Equal(t, 2, *report.Versions[0].DaysToThreshold)
+}
+
+func TestBuildAdoption_GroupedAndNeverAdopted(t *testing.T) {
+	t.Parallel()
+
+	versions := []hangar.Version{
+		{Name: "1.0", CreatedAt: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "1.1-beta", CreatedAt: time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	stats := map[string]hangar.VersionStatsData{
+		"1.0":      {"2024-01-10": {Downloads: 30}, "2024-02-01": {Downloads: 30}},
+		"1.1-beta": {"2024-01-10": {Downloads: 10}, "2024-02-01": {Downloads: 10}},
+	}
+
+	report, err := hangar.BuildAdoption(versions, stats, nil, hangar.PeriodMonth, 50)
+	require.NoError(t, err)
+
+	assert.Nil(t, report.Versions[0].DaysToThreshold)
+	require.Len(t, report.Periods, 2)
+	assert.Equal(t, "2024-01", report.Periods[0].Period)
+	assert.InDelta(t, 25.0, report.Periods[0].Shares["1.1-beta"], 0.001)
+}
+
+func TestBuildAdoption_InvalidThreshold(t *testing.T) {
+	t.Parallel()
+
+	_, err := hangar.BuildAdoption(nil, nil, nil, hangar.PeriodDay, 0)
+
+	assert.Error(t, err)
+}
+
+func TestSortPlatforms(t *testing.T) {
+	t.Parallel()
+
+	platforms := hangar.SortPlatforms(map[string]int64{"WATERFALL": 1, "FOLIA": 2, "PAPER": 3})
+
+	assert.Equal(t, []string{"PAPER", "WATERFALL", "FOLIA"}, platforms)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -21,6 +22,8 @@ const (
 	DefaultTimeout = 30 * time.Second
 	// DefaultLimit is the default pagination limit.
 	DefaultLimit = 25
+	// MaxLimit is the maximum page size accepted by the API.
+	MaxLimit = 100
 )
 
 // Client is the Hangar API client.
@@ -28,6 +31,9 @@ type Client struct {
 	baseURL    string
 	token      string
 	httpClient *http.Client
+	logger     *slog.Logger
+	// err is an invalid transport configuration, returned by every request.
+	err error
 }
 
 // Config contains configuration for the Hangar client.
@@ -38,16 +44,24 @@ type Config struct {
 	Token string
 	// Timeout is the HTTP client timeout (defaults to DefaultTimeout).
 	Timeout time.Duration
-	// HTTPClient is an optional custom HTTP client.
+	// HTTPClient is an optional custom HTTP client. Timeout and Transport are ignored if set.
 	HTTPClient *http.Client
+	// Transport configures proxies, TLS and connection pools of the default HTTP client.
+	Transport TransportConfig
+	// Logger receives debug logs of requests and responses with credentials redacted
+	// (defaults to slog.Default()).
+	Logger *slog.Logger
 }
 
 // NewClient creates a new Hangar API client.
+// If the transport configuration is invalid, every request returns the error;
+// use TransportConfig.Build to check it beforehand.
 func NewClient(cfg Config) *Client {
 	if cfg.BaseURL == "" {
 		cfg.BaseURL = DefaultBaseURL
 	}
 
+	var transportErr error
 	httpClient := cfg.HTTPClient
 	if httpClient == nil {
 		timeout := cfg.Timeout
@@ -57,12 +71,28 @@ func NewClient(cfg Config) *Client {
 		httpClient = &http.Client{
 			Timeout: timeout,
 		}
+
+		if cfg.Transport != (TransportConfig{}) {
+			transport, err := cfg.Transport.Build()
+			if err != nil {
+				transportErr = errors.Wrap(err, "invalid transport configuration")
+			} else {
+				httpClient.Transport = transport
+			}
+		}
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
 	}
 
 	return &Client{
 		baseURL:    cfg.BaseURL,
 		token:      cfg.Token,
 		httpClient: httpClient,
+		logger:     logger,
+		err:        transportErr,
 	}
 }
 
@@ -74,6 +104,12 @@ type ListOptions struct {
 	Offset int
 	// Category filters projects by category (optional).
 	Category string
+	// Query searches projects by name (optional).
+	Query string
+	// Platform filters versions by platform, e.g. "PAPER" (optional).
+	Platform string
+	// PlatformVersion filters versions by supported platform version, e.g. "1.21.1" (optional).
+	PlatformVersion string
 }
 
 // GetProject retrieves information about a specific project.
@@ -108,6 +144,9 @@ func (c *Client) ListProjects(ctx context.Context, opts ListOptions) (*ProjectsL
 	if opts.Category != "" {
 		params.Set("category", opts.Category)
 	}
+	if opts.Query != "" {
+		params.Set("q", opts.Query)
+	}
 
 	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
 
@@ -141,6 +180,13 @@ func (c *Client) ListVersions(ctx context.Context, owner, slug string, opts List
 	params.Set("limit", strconv.Itoa(limit))
 	params.Set("offset", strconv.Itoa(opts.Offset))
 
+	if opts.Platform != "" {
+		params.Set("platform", opts.Platform)
+	}
+	if opts.PlatformVersion != "" {
+		params.Set("platformVersion", opts.PlatformVersion)
+	}
+
 	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
 
 	var list VersionsList
@@ -151,6 +197,20 @@ func (c *Client) ListVersions(ctx context.Context, owner, slug string, opts List
 	return &list, nil
 }
 
+// ListAllVersions retrieves every version of a project by following pagination.
+// owner is the project owner username, slug is the project identifier.
+func (c *Client) ListAllVersions(ctx context.Context, owner, slug string) ([]Version, error) {
+	versions, _, err := CollectAll(ctx, ListOptions{}, func(ctx context.Context, opts ListOptions) ([]Version, Pagination, error) {
+		page, err := c.ListVersions(ctx, owner, slug, opts)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return page.Result, page.Pagination, nil
+	})
+
+	return versions, err
+}
+
 // GetVersion retrieves a specific version of a project by version name or ID.
 func (c *Client) GetVersion(ctx context.Context, slug, versionNameOrID string) (*Version, error) {
 	if slug == "" {
@@ -197,14 +257,8 @@ func (c *Client) GetDownloadURL(ctx context.Context, owner, slug, version, platf
 	// Find matching version
 	for _, v := range versions.Result {
 		if v.Name == version {
-			if downloadInfo, ok := v.Downloads[platform]; ok {
-				// Prefer downloadUrl, fallback to externalUrl
-				if downloadInfo.DownloadURL != "" {
-					return downloadInfo.DownloadURL, nil
-				}
-				if downloadInfo.ExternalURL != "" {
-					return downloadInfo.ExternalURL, nil
-				}
+			if downloadURL, ok := v.DownloadURL(platform); ok {
+				return downloadURL, nil
 			}
 			return "", errors.Newf("no download URL found for platform %s", platform)
 		}
@@ -213,6 +267,50 @@ func (c *Client) GetDownloadURL(ctx context.Context, owner, slug, version, platf
 	return "", errors.Newf("version %s not found", version)
 }
 
+// Download streams the file at downloadURL to w and returns the number of bytes written.
+// The API token is only sent when the file is hosted on the API host.
+func (c *Client) Download(ctx context.Context, downloadURL string, w io.Writer) (int64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return 0, errors.Wrap(redactError(err), "failed to create request")
+	}
+
+	req.Header.Set("User-Agent", "go-hangar/1.0")
+
+	if base, err := url.Parse(c.baseURL); err == nil && c.token != "" && base.Host == req.URL.Host {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	}
+
+	c.logger.DebugContext(ctx, "downloading file", "url", RedactURL(downloadURL))
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(redactError(err), "HTTP request failed")
+	}
+	c.logResponse(ctx, req, resp, start)
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			c.logger.WarnContext(ctx, "failed to close response body", "error", closeErr)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, errors.Newf("download failed with status %d", resp.StatusCode)
+	}
+
+	written, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return written, errors.Wrap(err, "failed to download file")
+	}
+
+	return written, nil
+}
+
 // ListUsers retrieves a paginated list of users matching a query.
 func (c *Client) ListUsers(ctx context.Context, query string, opts ListOptions) (*UserList, error) {
 	endpoint := fmt.Sprintf("%s/users", c.baseURL)
@@ -361,6 +459,24 @@ func (c *Client) ListStaff(ctx context.Context) ([]StaffMember, error) {
 	return staff, nil
 }
 
+// Authenticate exchanges an API key for a session, which also validates the key.
+func (c *Client) Authenticate(ctx context.Context, apiKey string) (*Session, error) {
+	if apiKey == "" {
+		return nil, errors.New("apiKey cannot be empty")
+	}
+
+	params := url.Values{}
+	params.Set("apiKey", apiKey)
+	endpoint := fmt.Sprintf("%s/authenticate?%s", c.baseURL, params.Encode())
+
+	var session Session
+	if err := c.doRequest(ctx, http.MethodPost, endpoint, nil, &session); err != nil {
+		return nil, errors.Wrap(err, "failed to authenticate")
+	}
+
+	return &session, nil
+}
+
 // GetVersionByID retrieves a version by its unique ID.
 func (c *Client) GetVersionByID(ctx context.Context, versionID int64) (*Version, error) {
 	if versionID <= 0 {
@@ -668,39 +784,58 @@ func (c *Client) GetLatestReleaseVersion(ctx context.Context, slug string) (*Ver
 	return c.GetVersion(ctx, slug, versionName)
 }
 
-// doRequest performs an HTTP request with proper error handling.
-func (c *Client) doRequest(ctx context.Context, method, url string, body io.Reader, result interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
-	if err != nil {
-		return errors.Wrap(err, "failed to create request")
+// Do sends a request to an API endpoint with the authentication, headers and error handling
+// of the typed methods, for endpoints the client does not wrap yet.
+//
+// path is relative to the base URL, e.g. "/projects/Essentials/watchers", and may contain a
+// query string. query may be nil; its parameters replace those of the same name in path.
+// A non-nil body is sent as JSON. The response is discarded if out is nil, copied if out is
+// an io.Writer and decoded as JSON into out otherwise.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body io.Reader, out any) error {
+	if method == "" {
+		return errors.New("method cannot be empty")
 	}
 
-	// Set headers
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "go-hangar/1.0")
+	// path may carry its own query string; parameters in query replace those of the same name
+	ref, err := url.Parse("/" + strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return errors.Wrapf(err, "invalid path %q", path)
+	}
+	merged := ref.Query()
+	for key, values := range query {
+		merged[key] = values
+	}
 
-	if c.token != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	endpoint := strings.TrimSuffix(c.baseURL, "/") + ref.EscapedPath()
+	if len(merged) > 0 {
+		endpoint += "?" + merged.Encode()
 	}
 
-	slog.DebugContext(ctx, "making API request",
-		"method", method,
-		"url", url)
+	w, ok := out.(io.Writer)
+	if !ok {
+		return c.doRequest(ctx, method, endpoint, body, out)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(ctx, method, endpoint, body, "application/json, text/plain, */*")
 	if err != nil {
-		return errors.Wrap(err, "HTTP request failed")
+		return err
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			slog.WarnContext(ctx, "failed to close response body", "error", closeErr)
-		}
-	}()
+	defer c.closeBody(ctx, resp)
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return errors.Newf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+
+	return nil
+}
+
+// doRequest performs an HTTP request with proper error handling.
+func (c *Client) doRequest(ctx context.Context, method, url string, body io.Reader, result interface{}) error {
+	resp, err := c.send(ctx, method, url, body, "application/json")
+	if err != nil {
+		return err
 	}
+	defer c.closeBody(ctx, resp)
 
 	if result != nil {
 		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
@@ -714,44 +849,82 @@ func (c *Client) doRequest(ctx context.Context, method, url string, body io.Read
 // doRawRequest performs an HTTP request and returns the response body as a string.
 // Used for endpoints that return plain text instead of JSON.
 func (c *Client) doRawRequest(ctx context.Context, method, url string, result *string) error {
-	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	resp, err := c.send(ctx, method, url, nil, "text/plain, */*")
 	if err != nil {
-		return errors.Wrap(err, "failed to create request")
+		return err
+	}
+	defer c.closeBody(ctx, resp)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+
+	*result = string(bodyBytes)
+
+	return nil
+}
+
+// send performs an HTTP request and returns the response if the status is successful.
+// The caller closes the response body.
+func (c *Client) send(ctx context.Context, method, url string, body io.Reader, accept string) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, errors.Wrap(redactError(err), "failed to create request")
 	}
 
 	// Set headers
-	req.Header.Set("Accept", "text/plain, */*")
+	req.Header.Set("Accept", accept)
 	req.Header.Set("User-Agent", "go-hangar/1.0")
 
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
 	if c.token != "" {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
 	}
 
-	slog.DebugContext(ctx, "making API request",
+	c.logger.DebugContext(ctx, "making API request",
 		"method", method,
-		"url", url)
+		"url", RedactURL(url),
+		"headers", RedactHeader(req.Header))
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return errors.Wrap(err, "HTTP request failed")
+		return nil, errors.Wrap(redactError(err), "HTTP request failed")
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			slog.WarnContext(ctx, "failed to close response body", "error", closeErr)
-		}
-	}()
+	c.logResponse(ctx, req, resp, start)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer c.closeBody(ctx, resp)
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return errors.Newf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, errors.WithStack(&APIError{StatusCode: resp.StatusCode, Body: string(bodyBytes)})
 	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return errors.Wrap(err, "failed to read response body")
-	}
+	return resp, nil
+}
 
-	*result = string(bodyBytes)
+// logResponse logs the metadata of a response and passes it to the response hook of ctx.
+func (c *Client) logResponse(ctx context.Context, req *http.Request, resp *http.Response, start time.Time) {
+	c.logger.DebugContext(ctx, "received API response",
+		"method", req.Method,
+		"url", RedactURL(effectiveURL(req, resp)),
+		"status", resp.StatusCode,
+		"elapsed", time.Since(start),
+		"headers", RedactHeader(resp.Header))
 
-	return nil
+	recordResponse(ctx, req, resp, start)
+}
+
+// closeBody closes a response body, logging failures.
+func (c *Client) closeBody(ctx context.Context, resp *http.Response) {
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		c.logger.WarnContext(ctx, "failed to close response body", "error", closeErr)
+	}
 }
@@ -1,11 +1,16 @@
 package hangar_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -87,6 +92,12 @@ func TestClient_GetProject_NotFound(t *testing.T) {
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "404")
+	assert.True(t, hangar.IsNotFound(err))
+
+	var apiErr *hangar.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, `{"error": "project not found"}`, apiErr.Body)
+	assert.False(t, hangar.IsNotFound(errors.New("connection refused")))
 }
 
 func TestClient_GetProject_ContextCanceled(t *testing.T) {
@@ -180,6 +191,33 @@ func TestClient_ListProjects_WithCategory(t *testing.T) {
 	assert.Len(t, list.Result, 1)
 }
 
+func TestClient_ListProjects_WithQuery(t *testing.T) {
+	t.Parallel()
+
+	testData, err := os.ReadFile(filepath.Join("../../testdata", "projects_list_response.json"))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "fancy", query.Get("q"))
+		assert.False(t, query.Has("category"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(testData)
+	}))
+	defer server.Close()
+
+	client := hangar.NewClient(hangar.Config{
+		BaseURL: server.URL,
+	})
+
+	list, err := client.ListProjects(context.Background(), hangar.ListOptions{Query: "fancy"})
+
+	require.NoError(t, err)
+	assert.Len(t, list.Result, 1)
+}
+
 func TestClient_ListVersions_Success(t *testing.T) {
 	t.Parallel()
 
@@ -241,6 +279,29 @@ func TestClient_ListVersions_Success(t *testing.T) {
 	assert.Equal(t, []string{"1.19", "1.20", "1.21"}, versions.Result[0].GameVersions)
 }
 
+func TestClient_ListVersions_PlatformFilter(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PAPER", r.URL.Query().Get("platform"))
+		assert.Equal(t, "1.21.1", r.URL.Query().Get("platformVersion"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"pagination": {"count": 0, "limit": 25, "offset": 0}, "result": []}`))
+	}))
+	defer server.Close()
+
+	client := hangar.NewClient(hangar.Config{BaseURL: server.URL})
+
+	versions, err := client.ListVersions(context.Background(), "testowner", "testplugin", hangar.ListOptions{
+		Platform:        "PAPER",
+		PlatformVersion: "1.21.1",
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, versions.Result)
+}
+
 func TestClient_GetDownloadURL_Success(t *testing.T) {
 	t.Parallel()
 
@@ -562,6 +623,102 @@ func TestClient_ListStaff_Success(t *testing.T) {
 	assert.Equal(t, "staffmember", staff[0].Name)
 }
 
+func TestClient_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/authenticate", r.URL.Path)
+
+		if r.URL.Query().Get("apiKey") != "valid-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Api key missing or invalid"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"header.payload.signature","expiresIn":10800000}`))
+	}))
+	defer server.Close()
+
+	client := hangar.NewClient(hangar.Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	session, err := client.Authenticate(ctx, "valid-key")
+	require.NoError(t, err)
+	assert.Equal(t, "header.payload.signature", session.Token)
+	assert.Equal(t, 3*time.Hour, session.Lifetime())
+
+	_, err = client.Authenticate(ctx, "wrong-key")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+
+	_, err = client.Authenticate(ctx, "")
+	require.Error(t, err)
+}
+
+func TestClient_Do(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/api/v1/projects/Essentials/watchers":
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "5", r.URL.Query().Get("limit"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"pagination":{"count":1},"result":[{"name":"alice"}]}`))
+		case "/api/v1/pages/edit":
+			assert.Equal(t, http.MethodPatch, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"content":"hello"}`, string(body))
+			_, _ = w.Write([]byte("updated"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := hangar.NewClient(hangar.Config{BaseURL: server.URL + "/api/v1", Token: "secret"})
+	ctx := context.Background()
+
+	var list hangar.UserList
+	err := client.Do(ctx, http.MethodGet, "/projects/Essentials/watchers", url.Values{"limit": {"5"}}, nil, &list)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", list.Result[0].Name)
+
+	var buf bytes.Buffer
+	err = client.Do(ctx, http.MethodPatch, "pages/edit", nil, strings.NewReader(`{"content":"hello"}`), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", buf.String())
+
+	err = client.Do(ctx, http.MethodGet, "/missing", nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `status 404: {"message":"not found"}`)
+}
+
+func TestClient_Do_PathWithQuery(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/projects", r.URL.Path)
+		assert.Equal(t, url.Values{"q": {"x"}, "limit": {"5"}, "offset": {"10"}}, r.URL.Query())
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := hangar.NewClient(hangar.Config{BaseURL: server.URL + "/api/v1"})
+
+	var buf bytes.Buffer
+	err := client.Do(context.Background(), http.MethodGet, "/projects?q=x&offset=0",
+		url.Values{"limit": {"5"}, "offset": {"10"}}, nil, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", buf.String())
+}
+
 // Test Version utilities
 
 func TestClient_GetVersionByID_Success(t *testing.T) {
@@ -991,3 +1148,82 @@ func TestClient_GetLatestReleaseVersion_Success(t *testing.T) {
 	assert.Equal(t, int64(88888), version.ID)
 	assert.Equal(t, "2.5.0", version.Name)
 }
+
+func TestClient_ListAllVersions_Paginates(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/projects/testowner/testplugin/versions", r.URL.Path)
+		assert.Equal(t, "100", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			_, _ = w.Write([]byte(`{"pagination": {"count": 3, "limit": 100, "offset": 0},
+				"result": [{"id": 3, "name": "3.0"}, {"id": 2, "name": "2.0"}]}`))
+		case "2":
+			_, _ = w.Write([]byte(`{"pagination": {"count": 3, "limit": 100, "offset": 2},
+				"result": [{"id": 1, "name": "1.0"}]}`))
+		default:
+			t.Errorf("Unexpected offset %s", r.URL.Query().Get("offset"))
+		}
+	}))
+	defer server.Close()
+
+	client := hangar.NewClient(hangar.Config{BaseURL: server.URL})
+
+	versions, err := client.ListAllVersions(context.Background(), "testowner", "testplugin")
+
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, "1.0", versions[2].Name)
+	assert.Equal(t, 2, requests)
+}
+
+func TestClient_Download(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		if r.URL.Path == "/missing.jar" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("jar contents"))
+	}))
+	defer server.Close()
+
+	client := hangar.NewClient(hangar.Config{BaseURL: server.URL + "/api/v1", Token: "secret"})
+
+	var buf bytes.Buffer
+	written, err := client.Download(context.Background(), server.URL+"/plugin.jar", &buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), written)
+	assert.Equal(t, "jar contents", buf.String())
+
+	_, err = client.Download(context.Background(), server.URL+"/missing.jar", &buf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
+
+func TestClient_Download_ExternalHostWithoutToken(t *testing.T) {
+	t.Parallel()
+
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("external"))
+	}))
+	defer external.Close()
+
+	client := hangar.NewClient(hangar.Config{BaseURL: "https://hangar.papermc.io/api/v1", Token: "secret"})
+
+	var buf bytes.Buffer
+	_, err := client.Download(context.Background(), external.URL+"/plugin.jar", &buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, "external", buf.String())
+}
@@ -0,0 +1,29 @@
+package hangar
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+)
+
+// APIError is returned when the API responds with an unsuccessful status code.
+type APIError struct {
+	// StatusCode is the HTTP status code, e.g. 404.
+	StatusCode int
+	// Body is the response body, usually a JSON error message.
+	Body string
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is caused by an API response with status 404 Not Found,
+// e.g. for an unknown project, version or file hash.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,208 @@
+package hangar
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Download kinds of a matrix cell.
+const (
+	// DownloadHosted means the file is hosted on Hangar.
+	DownloadHosted = "hosted"
+	// DownloadExternal means the file is only available from an external URL.
+	DownloadExternal = "external"
+	// DownloadNone means the version declares the platform but has no download for it.
+	DownloadNone = "none"
+)
+
+// MatrixCell describes the support of a single version for a platform.
+type MatrixCell struct {
+	// GameVersions lists the supported game versions, oldest first.
+	GameVersions []string `json:"gameVersions"`
+	// Ranges are the game versions with consecutive patch versions collapsed, e.g. "1.20-1.20.4".
+	Ranges []string `json:"ranges"`
+	// Download is one of the Download constants.
+	Download string `json:"download"`
+}
+
+// MatrixRow is the platform support of a single version.
+type MatrixRow struct {
+	// Version is the version name.
+	Version string `json:"version"`
+	// Channel is the release channel name.
+	Channel string `json:"channel"`
+	// CreatedAt is when the version was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// Platforms maps the supported platforms to their cells.
+	Platforms map[string]MatrixCell `json:"platforms"`
+}
+
+// CompatibilityMatrix is a grid of versions, platforms and game versions.
+type CompatibilityMatrix struct {
+	// Platforms lists the platforms supported by any version, known platforms first.
+	Platforms []string `json:"platforms"`
+	// GameVersions lists the game versions supported by any version per platform, oldest first.
+	GameVersions map[string][]string `json:"gameVersions"`
+	// Rows lists the versions, newest first.
+	Rows []MatrixRow `json:"rows"`
+}
+
+// BuildCompatibilityMatrix builds the compatibility matrix of versions from their platform
+// dependencies and downloads. Consecutive patch versions are collapsed into ranges with
+// CollapseGameVersions.
+func BuildCompatibilityMatrix(versions []Version) *CompatibilityMatrix {
+	matrix := &CompatibilityMatrix{GameVersions: make(map[string][]string)}
+
+	seen := make(map[string]int64)
+	for _, version := range versions {
+		for platform, gameVersions := range version.PlatformDependencies {
+			seen[platform] = 0
+			matrix.GameVersions[platform] = append(matrix.GameVersions[platform], gameVersions...)
+		}
+		for platform := range version.Downloads {
+			seen[platform] = 0
+		}
+	}
+
+	matrix.Platforms = SortPlatforms(seen)
+	for platform, gameVersions := range matrix.GameVersions {
+		slices.SortFunc(gameVersions, CompareGameVersions)
+		matrix.GameVersions[platform] = slices.Compact(gameVersions)
+	}
+
+	for _, version := range versions {
+		row := MatrixRow{
+			Version:   version.Name,
+			Channel:   version.Channel.Name,
+			CreatedAt: version.CreatedAt,
+			Platforms: make(map[string]MatrixCell),
+		}
+
+		for _, platform := range matrix.Platforms {
+			gameVersions, declared := version.PlatformDependencies[platform]
+			download := downloadKind(version, platform)
+			if !declared && download == DownloadNone {
+				continue
+			}
+
+			gameVersions = slices.Clone(gameVersions)
+			slices.SortFunc(gameVersions, CompareGameVersions)
+			gameVersions = slices.Compact(gameVersions)
+
+			row.Platforms[platform] = MatrixCell{
+				GameVersions: gameVersions,
+				Ranges:       CollapseGameVersions(gameVersions),
+				Download:     download,
+			}
+		}
+
+		matrix.Rows = append(matrix.Rows, row)
+	}
+
+	slices.SortStableFunc(matrix.Rows, func(a, b MatrixRow) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return matrix
+}
+
+// downloadKind returns how the version can be downloaded for the platform.
+func downloadKind(version Version, platform string) string {
+	info, ok := version.Downloads[platform]
+	switch {
+	case !ok:
+		return DownloadNone
+	case info.DownloadURL != "":
+		return DownloadHosted
+	case info.ExternalURL != "":
+		return DownloadExternal
+	default:
+		return DownloadNone
+	}
+}
+
+// CollapseGameVersions collapses sorted game versions into ranges of consecutive patch
+// versions of the same minor version, e.g. "1.20", "1.20.1" and "1.20.2" into "1.20-1.20.2".
+// Gaps such as a missing "1.20.3" split a range, and versions of different minor versions
+// are never joined because the last patch of a minor version is not known.
+func CollapseGameVersions(gameVersions []string) []string {
+	var ranges []string
+	for i := 0; i < len(gameVersions); {
+		j := i
+		for j+1 < len(gameVersions) && nextPatch(gameVersions[j], gameVersions[j+1]) {
+			j++
+		}
+
+		if j == i {
+			ranges = append(ranges, gameVersions[i])
+		} else {
+			ranges = append(ranges, gameVersions[i]+"-"+gameVersions[j])
+		}
+		i = j + 1
+	}
+
+	return ranges
+}
+
+// nextPatch reports whether b is the patch release directly following a, like "1.20" and
+// "1.20.1" or "1.20.1" and "1.20.2".
+func nextPatch(a, b string) bool {
+	aMinor, aPatch, ok := splitPatch(a)
+	if !ok {
+		return false
+	}
+	bMinor, bPatch, ok := splitPatch(b)
+
+	return ok && aMinor == bMinor && bPatch == aPatch+1
+}
+
+// splitPatch splits a numeric game version into its "major.minor" part and patch number,
+// which is zero when the version has no patch part.
+func splitPatch(version string) (minor string, patch int, ok bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", 0, false
+	}
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return "", 0, false
+		}
+	}
+	if len(parts) == 3 {
+		patch, _ = strconv.Atoi(parts[2])
+	}
+
+	return parts[0] + "." + parts[1], patch, true
+}
+
+// CompareGameVersions compares dotted game versions like "1.20.4" numerically and returns
+// -1, 0 or 1. Parts that are not numbers are compared as strings; missing parts count as zero.
+func CompareGameVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(aParts), len(bParts)) {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+
+		aNumber, aErr := strconv.Atoi(aPart)
+		bNumber, bErr := strconv.Atoi(bPart)
+		var result int
+		if aErr == nil && bErr == nil {
+			result = cmp.Compare(aNumber, bNumber)
+		} else {
+			result = strings.Compare(aPart, bPart)
+		}
+		if result != 0 {
+			return result
+		}
+	}
+
+	return strings.Compare(a, b)
+}
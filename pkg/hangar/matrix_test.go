@@ -0,0 +1,129 @@
+package hangar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCompatibilityMatrix(t *testing.T) {
+	t.Parallel()
+
+	versions := []hangar.Version{
+		{
+			Name:      "1.0",
+			CreatedAt: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Channel:   hangar.Channel{Name: "Release"},
+			Downloads: map[string]hangar.DownloadInfo{
+				"PAPER":    {DownloadURL: "https://hangar.example/1.0.jar"},
+				"VELOCITY": {ExternalURL: "https://example.com/1.0.jar"},
+			},
+			PlatformDependencies: map[string][]string{
+				"PAPER":    {"1.20.4", "1.20", "1.20.1", "1.20.2"},
+				"VELOCITY": {"3.3"},
+			},
+		},
+		{
+			Name:      "2.0",
+			CreatedAt: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+			Channel:   hangar.Channel{Name: "Beta"},
+			Downloads: map[string]hangar.DownloadInfo{
+				"PAPER": {DownloadURL: "https://hangar.example/2.0.jar"},
+			},
+			PlatformDependencies: map[string][]string{
+				"PAPER":     {"1.20.4", "1.21", "1.21.1"},
+				"WATERFALL": {"1.20"},
+			},
+		},
+	}
+
+	matrix := hangar.BuildCompatibilityMatrix(versions)
+
+	assert.Equal(t, []string{"PAPER", "VELOCITY", "WATERFALL"}, matrix.Platforms)
+	assert.Equal(t, []string{"1.20", "1.20.1", "1.20.2", "1.20.4", "1.21", "1.21.1"}, matrix.GameVersions["PAPER"])
+
+	require.Len(t, matrix.Rows, 2)
+
+	newest := matrix.Rows[0]
+	assert.Equal(t, "2.0", newest.Version)
+	assert.Equal(t, "Beta", newest.Channel)
+	assert.Equal(t, hangar.MatrixCell{
+		GameVersions: []string{"1.20.4", "1.21", "1.21.1"},
+		Ranges:       []string{"1.20.4", "1.21-1.21.1"},
+		Download:     hangar.DownloadHosted,
+	}, newest.Platforms["PAPER"])
+	assert.Equal(t, hangar.DownloadNone, newest.Platforms["WATERFALL"].Download)
+	assert.NotContains(t, newest.Platforms, "VELOCITY")
+
+	oldest := matrix.Rows[1]
+	assert.Equal(t, "1.0", oldest.Version)
+	assert.Equal(t, []string{"1.20-1.20.2", "1.20.4"}, oldest.Platforms["PAPER"].Ranges)
+	assert.Equal(t, hangar.DownloadExternal, oldest.Platforms["VELOCITY"].Download)
+	assert.Equal(t, []string{"3.3"}, oldest.Platforms["VELOCITY"].Ranges)
+}
+
+func TestBuildCompatibilityMatrix_Empty(t *testing.T) {
+	t.Parallel()
+
+	matrix := hangar.BuildCompatibilityMatrix(nil)
+
+	assert.Empty(t, matrix.Platforms)
+	assert.Empty(t, matrix.Rows)
+}
+
+func TestCollapseGameVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		gameVersions []string
+		want         []string
+	}{
+		{name: "empty", gameVersions: nil, want: nil},
+		{name: "single", gameVersions: []string{"1.20.1"}, want: []string{"1.20.1"}},
+		{name: "consecutive patches", gameVersions: []string{"1.20", "1.20.1", "1.20.2"}, want: []string{"1.20-1.20.2"}},
+		{name: "gap", gameVersions: []string{"1.20", "1.20.1", "1.20.2", "1.20.4"}, want: []string{"1.20-1.20.2", "1.20.4"}},
+		{name: "distant versions", gameVersions: []string{"1.8", "1.21"}, want: []string{"1.8", "1.21"}},
+		{
+			name:         "minor boundary",
+			gameVersions: []string{"1.20.5", "1.20.6", "1.21", "1.21.1"},
+			want:         []string{"1.20.5-1.20.6", "1.21-1.21.1"},
+		},
+		{name: "snapshot", gameVersions: []string{"1.21", "24w14a"}, want: []string{"1.21", "24w14a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, hangar.CollapseGameVersions(tt.gameVersions))
+		})
+	}
+}
+
+func TestCompareGameVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.20.4", b: "1.21", want: -1},
+		{a: "1.21", b: "1.20.4", want: 1},
+		{a: "1.9", b: "1.10", want: -1},
+		{a: "1.21.1", b: "1.21.1", want: 0},
+		{a: "1.20", b: "1.20.0", want: -1},
+		{a: "3.3", b: "3.x", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, hangar.CompareGameVersions(tt.a, tt.b))
+		})
+	}
+}
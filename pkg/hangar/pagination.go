@@ -0,0 +1,31 @@
+package hangar
+
+import "context"
+
+// PageFunc fetches one page of a paginated list.
+type PageFunc[T any] func(ctx context.Context, opts ListOptions) ([]T, Pagination, error)
+
+// CollectAll fetches every page of a paginated list starting at opts.Offset, using the
+// largest page size the API accepts. The returned pagination describes the combined result.
+func CollectAll[T any](ctx context.Context, opts ListOptions, fetch PageFunc[T]) ([]T, Pagination, error) {
+	var items []T
+	pagination := Pagination{Offset: opts.Offset}
+
+	for {
+		opts.Limit = MaxLimit
+		opts.Offset = pagination.Offset + len(items)
+
+		page, meta, err := fetch(ctx, opts)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+
+		items = append(items, page...)
+		pagination.Count = meta.Count
+
+		if len(page) == 0 || int64(opts.Offset+len(page)) >= meta.Count {
+			pagination.Limit = len(items)
+			return items, pagination, nil
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package hangar_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectAll(t *testing.T) {
+	t.Parallel()
+
+	all := make([]int, 250)
+	for i := range all {
+		all[i] = i
+	}
+
+	tests := []struct {
+		name     string
+		offset   int
+		items    []int
+		wantLen  int
+		wantReqs int
+	}{
+		{name: "several pages", items: all, wantLen: 250, wantReqs: 3},
+		{name: "exact page", items: all[:100], wantLen: 100, wantReqs: 1},
+		{name: "from offset", offset: 120, items: all, wantLen: 130, wantReqs: 2},
+		{name: "empty", items: nil, wantLen: 0, wantReqs: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int
+			fetch := func(_ context.Context, opts hangar.ListOptions) ([]int, hangar.Pagination, error) {
+				requests++
+				assert.Equal(t, hangar.MaxLimit, opts.Limit)
+				assert.Equal(t, "chat", opts.Category)
+
+				end := min(opts.Offset+opts.Limit, len(tt.items))
+				start := min(opts.Offset, end)
+				return tt.items[start:end], hangar.Pagination{Count: int64(len(tt.items)), Limit: opts.Limit, Offset: opts.Offset}, nil
+			}
+
+			items, pagination, err := hangar.CollectAll(context.Background(), hangar.ListOptions{Offset: tt.offset, Category: "chat"}, fetch)
+
+			require.NoError(t, err)
+			assert.Len(t, items, tt.wantLen)
+			assert.Equal(t, tt.wantReqs, requests)
+			assert.Equal(t, int64(len(tt.items)), pagination.Count)
+			assert.Equal(t, tt.offset, pagination.Offset)
+			if tt.wantLen > 0 {
+				assert.Equal(t, tt.offset, items[0])
+				assert.Equal(t, tt.items[len(tt.items)-1], items[len(items)-1])
+			}
+		})
+	}
+}
+
+func TestCollectAll_Error(t *testing.T) {
+	t.Parallel()
+
+	fetch := func(_ context.Context, opts hangar.ListOptions) ([]int, hangar.Pagination, error) {
+		if opts.Offset > 0 {
+			return nil, hangar.Pagination{}, errors.New("boom")
+		}
+		return make([]int, hangar.MaxLimit), hangar.Pagination{Count: 500}, nil
+	}
+
+	items, _, err := hangar.CollectAll(context.Background(), hangar.ListOptions{}, fetch)
+
+	require.Error(t, err)
+	assert.Nil(t, items)
+}
@@ -0,0 +1,73 @@
+package hangar
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// redacted replaces secret values in logs and diagnostics.
+const redacted = "REDACTED"
+
+// secretParams are query parameters carrying credentials, compared case-insensitively.
+var secretParams = []string{"apikey", "token", "access_token", "password"}
+
+// secretHeaders are headers carrying credentials.
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// RedactURL masks credentials in a URL: the password of the user info and the values
+// of query parameters such as apiKey. Unparsable URLs are returned unchanged.
+func RedactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	changed := false
+	if _, ok := parsed.User.Password(); ok {
+		parsed.User = url.UserPassword(parsed.User.Username(), redacted)
+		changed = true
+	}
+
+	query := parsed.Query()
+	for name := range query {
+		for _, secret := range secretParams {
+			if strings.EqualFold(name, secret) {
+				query[name] = []string{redacted}
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return rawURL
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// RedactHeader returns a copy of header with the values of credential headers such as
+// Authorization masked.
+func RedactHeader(header http.Header) http.Header {
+	clone := header.Clone()
+	for _, name := range secretHeaders {
+		if values := clone.Values(name); len(values) > 0 {
+			clone[http.CanonicalHeaderKey(name)] = []string{redacted}
+		}
+	}
+
+	return clone
+}
+
+// redactError masks credentials in the request URL that net/http includes in the messages
+// of *url.Error, e.g. the apiKey of a failed authentication request.
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = RedactURL(urlErr.URL)
+	}
+
+	return err
+}
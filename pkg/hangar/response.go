@@ -0,0 +1,83 @@
+package hangar
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ResponseInfo is the metadata of an HTTP response received by the client.
+type ResponseInfo struct {
+	// Method is the request method.
+	Method string
+	// URL is the requested URL.
+	URL string
+	// EffectiveURL is the URL of the final request after following redirects.
+	EffectiveURL string
+	// StatusCode is the HTTP status code, e.g. 200.
+	StatusCode int
+	// Status is the HTTP status line, e.g. "200 OK".
+	Status string
+	// Header contains the response headers.
+	Header http.Header
+	// Elapsed is the time until the response headers were received, including redirects.
+	Elapsed time.Duration
+}
+
+// Redirected reports whether the request was redirected.
+func (r ResponseInfo) Redirected() bool {
+	return r.EffectiveURL != r.URL
+}
+
+// responseHookKey is the context key of the response hook.
+type responseHookKey struct{}
+
+// WithResponseHook returns a context that makes the client call hook with the metadata
+// of every response received with it, including unsuccessful ones.
+// The hook may be called concurrently by concurrent requests.
+func WithResponseHook(ctx context.Context, hook func(ResponseInfo)) context.Context {
+	if previous, ok := ctx.Value(responseHookKey{}).(func(ResponseInfo)); ok {
+		next := hook
+		hook = func(info ResponseInfo) {
+			previous(info)
+			next(info)
+		}
+	}
+
+	return context.WithValue(ctx, responseHookKey{}, hook)
+}
+
+// WithResponseInfo returns a context that makes the client store the metadata of
+// the last response received with it in info. Use it for one request at a time.
+func WithResponseInfo(ctx context.Context, info *ResponseInfo) context.Context {
+	return WithResponseHook(ctx, func(received ResponseInfo) {
+		*info = received
+	})
+}
+
+// recordResponse passes the metadata of a response to the hook of ctx, if any.
+func recordResponse(ctx context.Context, req *http.Request, resp *http.Response, start time.Time) {
+	hook, ok := ctx.Value(responseHookKey{}).(func(ResponseInfo))
+	if !ok {
+		return
+	}
+
+	hook(ResponseInfo{
+		Method:       req.Method,
+		URL:          req.URL.String(),
+		EffectiveURL: effectiveURL(req, resp),
+		StatusCode:   resp.StatusCode,
+		Status:       resp.Status,
+		Header:       resp.Header.Clone(),
+		Elapsed:      time.Since(start),
+	})
+}
+
+// effectiveURL returns the URL of the request that produced resp after following redirects.
+func effectiveURL(req *http.Request, resp *http.Response) string {
+	if resp.Request != nil {
+		return resp.Request.URL.String()
+	}
+
+	return req.URL.String()
+}
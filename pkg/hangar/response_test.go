@@ -0,0 +1,92 @@
+package hangar_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithResponseInfo(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/projects/fancyglow":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Request-Id", "abc")
+			_, _ = w.Write([]byte(`{"name":"FancyGlow"}`))
+		case "/api/v1/projects/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := hangar.NewClient(hangar.Config{BaseURL: server.URL + "/api/v1"})
+
+	var info hangar.ResponseInfo
+	ctx := hangar.WithResponseInfo(context.Background(), &info)
+
+	_, err := client.GetProject(ctx, "fancyglow")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodGet, info.Method)
+	assert.Equal(t, server.URL+"/api/v1/projects/fancyglow", info.URL)
+	assert.Equal(t, info.URL, info.EffectiveURL)
+	assert.False(t, info.Redirected())
+	assert.Equal(t, http.StatusOK, info.StatusCode)
+	assert.Equal(t, "200 OK", info.Status)
+	assert.Equal(t, "abc", info.Header.Get("X-Request-Id"))
+	assert.Positive(t, info.Elapsed)
+
+	// Unsuccessful responses are recorded too
+	_, err = client.GetProject(ctx, "missing")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, info.StatusCode)
+}
+
+func TestWithResponseHook_Redirect(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/versions/1/PAPER/download", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/cdn/plugin.jar", http.StatusFound)
+	})
+	mux.HandleFunc("/cdn/plugin.jar", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("jar"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := hangar.NewClient(hangar.Config{BaseURL: server.URL + "/api/v1"})
+
+	var (
+		mu        sync.Mutex
+		responses []hangar.ResponseInfo
+	)
+	record := func(info hangar.ResponseInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		responses = append(responses, info)
+	}
+
+	// Hooks of enclosing contexts keep being called
+	var last hangar.ResponseInfo
+	ctx := hangar.WithResponseInfo(hangar.WithResponseHook(context.Background(), record), &last)
+
+	var buf bytes.Buffer
+	_, err := client.Download(ctx, server.URL+"/api/v1/versions/1/PAPER/download", &buf)
+	require.NoError(t, err)
+
+	require.Len(t, responses, 1)
+	assert.Equal(t, responses[0], last)
+	assert.True(t, last.Redirected())
+	assert.Equal(t, server.URL+"/cdn/plugin.jar", last.EffectiveURL)
+	assert.Equal(t, http.StatusOK, last.StatusCode)
+}
@@ -0,0 +1,333 @@
+package hangar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// StatsPeriod is a calendar period used to group daily statistics.
+type StatsPeriod string
+
+const (
+	// PeriodDay keeps statistics at daily resolution.
+	PeriodDay StatsPeriod = "day"
+	// PeriodWeek groups statistics by ISO 8601 week (Monday to Sunday).
+	PeriodWeek StatsPeriod = "week"
+	// PeriodMonth groups statistics by calendar month.
+	PeriodMonth StatsPeriod = "month"
+	// PeriodYear groups statistics by calendar year.
+	PeriodYear StatsPeriod = "year"
+)
+
+// statsDateLayout is the date format used for stats map keys.
+const statsDateLayout = "2006-01-02"
+
+// ParseStatsPeriod converts a string such as "week" into a StatsPeriod.
+func ParseStatsPeriod(s string) (StatsPeriod, error) {
+	switch period := StatsPeriod(s); period {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodYear:
+		return period, nil
+	case "":
+		return PeriodDay, nil
+	default:
+		return "", errors.Newf("unknown stats period %q (expected day, week, month or year)", s)
+	}
+}
+
+// Label returns the display label of the period containing t,
+// e.g. "2024-01-15", "2024-W03", "2024-01" or "2024".
+func (p StatsPeriod) Label(t time.Time) string {
+	switch p {
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case PeriodMonth:
+		return t.Format("2006-01")
+	case PeriodYear:
+		return t.Format("2006")
+	default:
+		return t.Format(statsDateLayout)
+	}
+}
+
+// Start returns the first day of the period containing t.
+func (p StatsPeriod) Start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch p {
+	case PeriodWeek:
+		// ISO weeks start on Monday; time.Weekday starts on Sunday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case PeriodMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case PeriodYear:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// StatsPoint contains the statistics of a single period.
+type StatsPoint struct {
+	// Period is the period label (see StatsPeriod.Label).
+	Period string `json:"period"`
+	// Start is the first day of the period.
+	Start time.Time `json:"start"`
+	// Downloads is the number of downloads in the period.
+	Downloads int64 `json:"downloads"`
+	// Views is the number of views in the period.
+	Views int64 `json:"views"`
+}
+
+// StatsSeries is a chronologically ordered list of statistics points.
+type StatsSeries []StatsPoint
+
+// NewStatsSeries converts a date-keyed statistics map into a daily series sorted by date.
+// Keys may be plain dates (YYYY-MM-DD) or RFC 3339 timestamps.
+func NewStatsSeries(stats map[string]DailyStats) (StatsSeries, error) {
+	series := make(StatsSeries, 0, len(stats))
+
+	for key, daily := range stats {
+		date, err := parseStatsDate(key)
+		if err != nil {
+			return nil, err
+		}
+
+		series = append(series, StatsPoint{
+			Period:    PeriodDay.Label(date),
+			Start:     date,
+			Downloads: daily.Downloads,
+			Views:     daily.Views,
+		})
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].Start.Before(series[j].Start)
+	})
+
+	return series, nil
+}
+
+// Series returns the project statistics as a daily series sorted by date.
+func (s ProjectStats) Series() (StatsSeries, error) {
+	return NewStatsSeries(s)
+}
+
+// Series returns the version statistics as a daily series sorted by date.
+func (s VersionStatsData) Series() (StatsSeries, error) {
+	return NewStatsSeries(s)
+}
+
+// Totals returns the sum of downloads and views across the series.
+func (s StatsSeries) Totals() DailyStats {
+	var total DailyStats
+	for _, point := range s {
+		total.Downloads += point.Downloads
+		total.Views += point.Views
+	}
+
+	return total
+}
+
+// Fill returns a copy of a daily series with zero-valued points inserted
+// for days missing between the first and the last point.
+func (s StatsSeries) Fill() StatsSeries {
+	if len(s) == 0 {
+		return StatsSeries{}
+	}
+
+	first := PeriodDay.Start(s[0].Start)
+	last := PeriodDay.Start(s[len(s)-1].Start)
+	days := int(last.Sub(first).Hours()/24) + 1
+
+	filled := make(StatsSeries, 0, days)
+	idx := 0
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		point := StatsPoint{Period: PeriodDay.Label(day), Start: day}
+		for idx < len(s) && PeriodDay.Start(s[idx].Start).Equal(day) {
+			point.Downloads += s[idx].Downloads
+			point.Views += s[idx].Views
+			idx++
+		}
+		filled = append(filled, point)
+	}
+
+	return filled
+}
+
+// GroupBy sums the series into calendar periods.
+// Weeks follow ISO 8601, so a week belongs to the year of its Thursday.
+func (s StatsSeries) GroupBy(period StatsPeriod) StatsSeries {
+	grouped := StatsSeries{}
+	index := make(map[string]int)
+
+	for _, point := range s {
+		label := period.Label(point.Start)
+
+		idx, ok := index[label]
+		if !ok {
+			idx = len(grouped)
+			index[label] = idx
+			grouped = append(grouped, StatsPoint{
+				Period: label,
+				Start:  period.Start(point.Start),
+			})
+		}
+
+		grouped[idx].Downloads += point.Downloads
+		grouped[idx].Views += point.Views
+	}
+
+	sort.SliceStable(grouped, func(i, j int) bool {
+		return grouped[i].Start.Before(grouped[j].Start)
+	})
+
+	return grouped
+}
+
+// Cumulative returns a series of running totals.
+func (s StatsSeries) Cumulative() StatsSeries {
+	cumulative := make(StatsSeries, len(s))
+
+	var downloads, views int64
+	for i, point := range s {
+		downloads += point.Downloads
+		views += point.Views
+		cumulative[i] = StatsPoint{
+			Period:    point.Period,
+			Start:     point.Start,
+			Downloads: downloads,
+			Views:     views,
+		}
+	}
+
+	return cumulative
+}
+
+// RollingPoint contains window sums and averages ending at a period.
+type RollingPoint struct {
+	// Period is the label of the last period in the window.
+	Period string `json:"period"`
+	// Window is the number of periods actually covered (less than the requested
+	// window size at the start of the series).
+	Window int `json:"window"`
+	// Downloads is the sum of downloads in the window.
+	Downloads int64 `json:"downloads"`
+	// Views is the sum of views in the window.
+	Views int64 `json:"views"`
+	// AvgDownloads is the average downloads per period in the window.
+	AvgDownloads float64 `json:"avgDownloads"`
+	// AvgViews is the average views per period in the window.
+	AvgViews float64 `json:"avgViews"`
+}
+
+// Rolling computes sums and averages over a sliding window of size periods.
+// For a daily series with gaps, call Fill first so that the window spans calendar days.
+func (s StatsSeries) Rolling(size int) ([]RollingPoint, error) {
+	if size < 1 {
+		return nil, errors.Newf("rolling window must be positive, got %d", size)
+	}
+
+	rolling := make([]RollingPoint, len(s))
+
+	var downloads, views int64
+	for i, point := range s {
+		downloads += point.Downloads
+		views += point.Views
+
+		if i >= size {
+			downloads -= s[i-size].Downloads
+			views -= s[i-size].Views
+		}
+
+		window := min(i+1, size)
+		rolling[i] = RollingPoint{
+			Period:       point.Period,
+			Window:       window,
+			Downloads:    downloads,
+			Views:        views,
+			AvgDownloads: float64(downloads) / float64(window),
+			AvgViews:     float64(views) / float64(window),
+		}
+	}
+
+	return rolling, nil
+}
+
+// GrowthPoint contains the change of a period relative to the previous one.
+type GrowthPoint struct {
+	// Period is the period label.
+	Period string `json:"period"`
+	// Downloads is the downloads change in percent (nil if there is no previous value to compare with).
+	Downloads *float64 `json:"downloads"`
+	// Views is the views change in percent (nil if there is no previous value to compare with).
+	Views *float64 `json:"views"`
+}
+
+// Growth computes period-over-period growth in percent.
+// The first period and periods following a zero value have no growth value.
+func (s StatsSeries) Growth() []GrowthPoint {
+	growth := make([]GrowthPoint, len(s))
+
+	for i, point := range s {
+		growth[i].Period = point.Period
+		if i == 0 {
+			continue
+		}
+
+		prev := s[i-1]
+		growth[i].Downloads = percentChange(prev.Downloads, point.Downloads)
+		growth[i].Views = percentChange(prev.Views, point.Views)
+	}
+
+	return growth
+}
+
+// Trend compares the second half of the series with the first half and returns the change in percent.
+// For an odd number of points the middle point is left out. Series with fewer than two points have no trend.
+func (s StatsSeries) Trend() GrowthPoint {
+	trend := GrowthPoint{}
+	if len(s) < 2 {
+		return trend
+	}
+
+	trend.Period = s[len(s)-1].Period
+	half := len(s) / 2
+	first := s[:half].Totals()
+	second := s[len(s)-half:].Totals()
+
+	trend.Downloads = percentChange(first.Downloads, second.Downloads)
+	trend.Views = percentChange(first.Views, second.Views)
+
+	return trend
+}
+
+// percentChange returns the relative change from prev to cur in percent, or nil if prev is zero.
+func percentChange(prev, cur int64) *float64 {
+	if prev == 0 {
+		return nil
+	}
+
+	change := float64(cur-prev) / float64(prev) * 100
+
+	return &change
+}
+
+// parseStatsDate parses a stats map key.
+func parseStatsDate(key string) (time.Time, error) {
+	if date, err := time.Parse(statsDateLayout, key); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, key)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid stats date %q", key)
+	}
+
+	return PeriodDay.Start(date.UTC()), nil
+}
@@ -0,0 +1,229 @@
+package hangar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStatsSeries(t *testing.T) hangar.StatsSeries {
+	t.Helper()
+
+	stats := hangar.ProjectStats{
+		"2024-01-29": {Downloads: 10, Views: 100},
+		"2024-01-31": {Downloads: 20, Views: 200},
+		"2024-02-01": {Downloads: 30, Views: 300},
+		"2024-02-05": {Downloads: 40, Views: 400},
+	}
+
+	series, err := stats.Series()
+	require.NoError(t, err)
+
+	return series
+}
+
+func TestNewStatsSeries_SortsByDate(t *testing.T) {
+	t.Parallel()
+
+	series := testStatsSeries(t)
+
+	require.Len(t, series, 4)
+	assert.Equal(t, "2024-01-29", series[0].Period)
+	assert.Equal(t, "2024-02-05", series[3].Period)
+	assert.Equal(t, hangar.DailyStats{Downloads: 100, Views: 1000}, series.Totals())
+}
+
+func TestNewStatsSeries_RFC3339Keys(t *testing.T) {
+	t.Parallel()
+
+	series, err := hangar.NewStatsSeries(map[string]hangar.DailyStats{
+		"2024-03-02T00:00:00Z": {Downloads: 5},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, "2024-03-02", series[0].Period)
+}
+
+func TestNewStatsSeries_InvalidDate(t *testing.T) {
+	t.Parallel()
+
+	_, err := hangar.NewStatsSeries(map[string]hangar.DailyStats{"yesterday": {}})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid stats date")
+}
+
+func TestParseStatsPeriod(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    hangar.StatsPeriod
+		wantErr bool
+	}{
+		{input: "", want: hangar.PeriodDay},
+		{input: "day", want: hangar.PeriodDay},
+		{input: "week", want: hangar.PeriodWeek},
+		{input: "month", want: hangar.PeriodMonth},
+		{input: "year", want: hangar.PeriodYear},
+		{input: "fortnight", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := hangar.ParseStatsPeriod(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStatsSeries_GroupBy(t *testing.T) {
+	t.Parallel()
+
+	series := testStatsSeries(t)
+
+	tests := []struct {
+		name      string
+		period    hangar.StatsPeriod
+		periods   []string
+		downloads []int64
+	}{
+		{
+			name:      "week",
+			period:    hangar.PeriodWeek,
+			periods:   []string{"2024-W05", "2024-W06"},
+			downloads: []int64{60, 40},
+		},
+		{
+			name:      "month",
+			period:    hangar.PeriodMonth,
+			periods:   []string{"2024-01", "2024-02"},
+			downloads: []int64{30, 70},
+		},
+		{
+			name:      "year",
+			period:    hangar.PeriodYear,
+			periods:   []string{"2024"},
+			downloads: []int64{100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			grouped := series.GroupBy(tt.period)
+
+			require.Len(t, grouped, len(tt.periods))
+			for i, point := range grouped {
+				assert.Equal(t, tt.periods[i], point.Period)
+				assert.Equal(t, tt.downloads[i], point.Downloads)
+			}
+		})
+	}
+}
+
+func TestStatsPeriod_WeekCrossesYear(t *testing.T) {
+	t.Parallel()
+
+	// 2024-12-30 is a Monday belonging to ISO week 1 of 2025
+	date := time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "2025-W01", hangar.PeriodWeek.Label(date))
+	assert.Equal(t, time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC), hangar.PeriodWeek.Start(date))
+}
+
+func TestStatsSeries_Fill(t *testing.T) {
+	t.Parallel()
+
+	filled := testStatsSeries(t).Fill()
+
+	require.Len(t, filled, 8)
+	assert.Equal(t, "2024-01-30", filled[1].Period)
+	assert.Equal(t, int64(0), filled[1].Downloads)
+	assert.Equal(t, int64(40), filled[7].Downloads)
+}
+
+func TestStatsSeries_Rolling(t *testing.T) {
+	t.Parallel()
+
+	rolling, err := testStatsSeries(t).Fill().Rolling(3)
+	require.NoError(t, err)
+
+	require.Len(t, rolling, 8)
+	assert.Equal(t, 1, rolling[0].Window)
+	assert.Equal(t, int64(10), rolling[0].Downloads)
+	assert.Equal(t, 3, rolling[2].Window)
+	assert.Equal(t, int64(30), rolling[2].Downloads)
+	assert.InDelta(t, 10.0, rolling[2].AvgDownloads, 0.001)
+	assert.Equal(t, int64(50), rolling[3].Downloads)
+	assert.Equal(t, int64(40), rolling[7].Downloads)
+}
+
+func TestStatsSeries_Rolling_InvalidWindow(t *testing.T) {
+	t.Parallel()
+
+	_, err := testStatsSeries(t).Rolling(0)
+
+	assert.Error(t, err)
+}
+
+func TestStatsSeries_Cumulative(t *testing.T) {
+	t.Parallel()
+
+	cumulative := testStatsSeries(t).Cumulative()
+
+	require.Len(t, cumulative, 4)
+	assert.Equal(t, int64(10), cumulative[0].Downloads)
+	assert.Equal(t, int64(60), cumulative[2].Downloads)
+	assert.Equal(t, int64(1000), cumulative[3].Views)
+}
+
+func TestStatsSeries_Growth(t *testing.T) {
+	t.Parallel()
+
+	series := hangar.StatsSeries{
+		{Period: "2024-01", Downloads: 0, Views: 100},
+		{Period: "2024-02", Downloads: 50, Views: 150},
+		{Period: "2024-03", Downloads: 25, Views: 150},
+	}
+
+	growth := series.Growth()
+
+	require.Len(t, growth, 3)
+	assert.Nil(t, growth[0].Downloads)
+	assert.Nil(t, growth[1].Downloads)
+	require.NotNil(t, growth[1].Views)
+	assert.InDelta(t, 50.0, *growth[1].Views, 0.001)
+	require.NotNil(t, growth[2].Downloads)
+	assert.InDelta(t, -50.0, *growth[2].Downloads, 0.001)
+	assert.InDelta(t, 0.0, *growth[2].Views, 0.001)
+}
+
+func TestStatsSeries_Trend(t *testing.T) {
+	t.Parallel()
+
+	trend := testStatsSeries(t).Trend()
+
+	require.NotNil(t, trend.Downloads)
+	assert.Equal(t, "2024-02-05", trend.Period)
+	assert.InDelta(t, 133.333, *trend.Downloads, 0.001)
+
+	odd := hangar.StatsSeries{{Downloads: 10}, {Downloads: 1000}, {Downloads: 20}}
+	require.NotNil(t, odd.Trend().Downloads)
+	assert.InDelta(t, 100.0, *odd.Trend().Downloads, 0.001)
+
+	assert.Nil(t, hangar.StatsSeries{{Downloads: 10}}.Trend().Downloads)
+}
@@ -0,0 +1,245 @@
+package hangar_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lexfrei/go-hangar/pkg/hangar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePEM writes PEM blocks to a file in dir and returns its path.
+func writePEM(t *testing.T, dir, name string, blocks ...*pem.Block) string {
+	t.Helper()
+
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, pem.EncodeToMemory(block)...)
+	}
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+// serverCAFile writes the certificate of a TLS test server as a CA bundle.
+func serverCAFile(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	return writePEM(t, t.TempDir(), "ca.pem", &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+// clientCertificate creates a self-signed client certificate and returns it with its PEM files.
+func clientCertificate(t *testing.T) (cert *x509.Certificate, certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "hangar-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = writePEM(t, dir, "client.pem", &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyFile = writePEM(t, dir, "client-key.pem", &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return cert, certFile, keyFile
+}
+
+func staffHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`[]`))
+}
+
+func TestTransport_CAFile(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(staffHandler))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	_, err := hangar.NewClient(hangar.Config{BaseURL: server.URL}).ListStaff(ctx)
+	require.Error(t, err, "the test server certificate is not trusted by default")
+
+	client := hangar.NewClient(hangar.Config{
+		BaseURL:   server.URL,
+		Transport: hangar.TransportConfig{CAFile: serverCAFile(t, server)},
+	})
+	_, err = client.ListStaff(ctx)
+	require.NoError(t, err)
+}
+
+func TestTransport_ClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	cert, certFile, keyFile := clientCertificate(t)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "hangar-client", r.TLS.PeerCertificates[0].Subject.CommonName)
+		staffHandler(w, r)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	ctx := context.Background()
+	caFile := serverCAFile(t, server)
+
+	_, err := hangar.NewClient(hangar.Config{
+		BaseURL:   server.URL,
+		Transport: hangar.TransportConfig{CAFile: caFile},
+	}).ListStaff(ctx)
+	require.Error(t, err, "the server requires a client certificate")
+
+	_, err = hangar.NewClient(hangar.Config{
+		BaseURL:   server.URL,
+		Transport: hangar.TransportConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+	}).ListStaff(ctx)
+	require.NoError(t, err)
+}
+
+func TestTransport_MinTLSVersion(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(staffHandler))
+	server.TLS = &tls.Config{MinVersion: tls.VersionTLS12, MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	ctx := context.Background()
+	caFile := serverCAFile(t, server)
+
+	_, err := hangar.NewClient(hangar.Config{
+		BaseURL:   server.URL,
+		Transport: hangar.TransportConfig{CAFile: caFile, MinTLSVersion: tls.VersionTLS12},
+	}).ListStaff(ctx)
+	require.NoError(t, err)
+
+	_, err = hangar.NewClient(hangar.Config{
+		BaseURL:   server.URL,
+		Transport: hangar.TransportConfig{CAFile: caFile, MinTLSVersion: tls.VersionTLS13},
+	}).ListStaff(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "protocol version")
+}
+
+func TestTransport_Proxy(t *testing.T) {
+	t.Parallel()
+
+	credentials := "Basic " + base64.StdEncoding.EncodeToString([]byte("build:secret"))
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") != credentials {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		assert.Equal(t, "hangar.invalid", r.URL.Host, "the proxy receives the absolute request URL")
+		staffHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	ctx := context.Background()
+	proxyURL := "http://build:secret@" + proxy.Listener.Addr().String()
+
+	client := hangar.NewClient(hangar.Config{
+		BaseURL:   "http://hangar.invalid/api/v1",
+		Transport: hangar.TransportConfig{ProxyURL: proxyURL},
+	})
+	_, err := client.ListStaff(ctx)
+	require.NoError(t, err)
+
+	client = hangar.NewClient(hangar.Config{
+		BaseURL:   "http://hangar.invalid/api/v1",
+		Transport: hangar.TransportConfig{ProxyURL: "http://" + proxy.Listener.Addr().String()},
+	})
+	_, err = client.ListStaff(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 407")
+}
+
+func TestTransportConfig_Build(t *testing.T) {
+	t.Parallel()
+
+	transport, err := hangar.TransportConfig{MaxIdleConns: 10, MaxIdleConnsPerHost: 5, MaxConnsPerHost: 20}.Build()
+	require.NoError(t, err)
+	assert.Equal(t, 10, transport.MaxIdleConns)
+	assert.Equal(t, 5, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 20, transport.MaxConnsPerHost)
+	assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	invalid := []struct {
+		name   string
+		config hangar.TransportConfig
+		want   string
+	}{
+		{name: "proxy without scheme", config: hangar.TransportConfig{ProxyURL: "proxy:3128"}, want: "invalid proxy URL"},
+		{name: "missing CA bundle", config: hangar.TransportConfig{CAFile: filepath.Join(dir, "missing.pem")}, want: "failed to read CA bundle"},
+		{name: "CA bundle without certificates", config: hangar.TransportConfig{CAFile: notPEM}, want: "no PEM certificates"},
+		{name: "certificate without key", config: hangar.TransportConfig{CertFile: notPEM}, want: "must be given together"},
+		{name: "invalid key pair", config: hangar.TransportConfig{CertFile: notPEM, KeyFile: notPEM}, want: "failed to load client certificate"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tt.config.Build()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+
+			// The client reports the error on every request
+			_, err = hangar.NewClient(hangar.Config{Transport: tt.config}).ListStaff(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid transport configuration")
+		})
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	t.Parallel()
+
+	version, err := hangar.ParseTLSVersion("1.3")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), version)
+
+	_, err = hangar.ParseTLSVersion("1.4")
+	require.Error(t, err)
+}
@@ -1,7 +1,22 @@
 // Package hangar provides a client for interacting with the PaperMC Hangar API.
 package hangar
 
-import "time"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ProjectCategories are the project categories accepted by the API, in display order.
+var ProjectCategories = []string{
+	"admin_tools", "chat", "dev_tools", "economy", "gameplay", "games",
+	"protection", "role_playing", "world_management", "misc",
+}
 
 // Project represents a plugin/mod project on Hangar.
 type Project struct {
@@ -147,6 +162,58 @@ type Version struct {
 	PinnedStatus string `json:"pinnedStatus"`
 }
 
+// DownloadURL returns the download URL of the version for a platform,
+// preferring the Hangar-hosted file over an external URL.
+func (v Version) DownloadURL(platform string) (string, bool) {
+	info, ok := v.Downloads[platform]
+	switch {
+	case !ok:
+		return "", false
+	case info.DownloadURL != "":
+		return info.DownloadURL, true
+	case info.ExternalURL != "":
+		return info.ExternalURL, true
+	default:
+		return "", false
+	}
+}
+
+// FileName returns a safe local file name for the download of v on platform: the
+// published file name, the last element of downloadURL if it names a jar, or
+// <slug>-<version>.jar. The result never contains a path separator.
+func (v Version) FileName(slug, platform, downloadURL string) string {
+	if info := v.Downloads[platform].FileInfo; info != nil {
+		if name := baseName(info.Name); name != "" {
+			return name
+		}
+	}
+
+	if parsed, err := url.Parse(downloadURL); err == nil {
+		if name := baseName(parsed.Path); strings.EqualFold(path.Ext(name), ".jar") {
+			return name
+		}
+	}
+
+	replacer := strings.NewReplacer("/", "_", `\`, "_")
+
+	return replacer.Replace(slug) + "-" + replacer.Replace(v.Name) + ".jar"
+}
+
+// baseName returns the last element of name, treating both path separators alike
+// regardless of the local OS, or "" if name has no usable last element.
+func baseName(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	base := path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if base == "." || base == ".." || base == "/" {
+		return ""
+	}
+
+	return base
+}
+
 // VersionStats contains download statistics for a version.
 type VersionStats struct {
 	// TotalDownloads is the total download count.
@@ -334,3 +401,44 @@ type CompactProject struct {
 	// Category is the project category.
 	Category string `json:"category"`
 }
+
+// Session is a short-lived API session obtained by exchanging an API key.
+type Session struct {
+	// Token is the session JWT.
+	Token string `json:"token"`
+	// ExpiresIn is the session lifetime in milliseconds.
+	ExpiresIn int64 `json:"expiresIn"`
+}
+
+// SessionClaims are the claims of a session token used by clients.
+type SessionClaims struct {
+	// Subject is the name of the user the API key belongs to.
+	Subject string `json:"sub"`
+	// ExpiresAt is the Unix time the session expires.
+	ExpiresAt int64 `json:"exp"`
+}
+
+// Claims decodes the claims of the session token without verifying its signature.
+func (s Session) Claims() (SessionClaims, error) {
+	parts := strings.Split(s.Token, ".")
+	if len(parts) != 3 {
+		return SessionClaims{}, errors.New("session token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return SessionClaims{}, errors.Wrap(err, "failed to decode session token")
+	}
+
+	var claims SessionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return SessionClaims{}, errors.Wrap(err, "failed to decode session claims")
+	}
+
+	return claims, nil
+}
+
+// Lifetime returns the session lifetime.
+func (s Session) Lifetime() time.Duration {
+	return time.Duration(s.ExpiresIn) * time.Millisecond
+}
@@ -1,6 +1,7 @@
 package hangar_test
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -195,3 +196,108 @@ func TestProject_TimeFields(t *testing.T) {
 	assert.Equal(t, now, project.CreatedAt)
 	assert.Equal(t, now, project.LastUpdated)
 }
+
+func TestVersion_DownloadURL(t *testing.T) {
+	t.Parallel()
+
+	version := hangar.Version{
+		Downloads: map[string]hangar.DownloadInfo{
+			"PAPER":     {DownloadURL: "https://hangar.example/paper.jar", ExternalURL: "https://example.com/paper.jar"},
+			"VELOCITY":  {ExternalURL: "https://example.com/velocity.jar"},
+			"WATERFALL": {},
+		},
+	}
+
+	tests := []struct {
+		platform string
+		wantURL  string
+		wantOK   bool
+	}{
+		{platform: "PAPER", wantURL: "https://hangar.example/paper.jar", wantOK: true},
+		{platform: "VELOCITY", wantURL: "https://example.com/velocity.jar", wantOK: true},
+		{platform: "WATERFALL"},
+		{platform: "FOLIA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := version.DownloadURL(tt.platform)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantURL, got)
+		})
+	}
+}
+
+func TestVersion_FileName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		version     string
+		fileName    string
+		downloadURL string
+		want        string
+	}{
+		{name: "published name", fileName: "Plugin-1.0.jar", downloadURL: "https://hangar.example/files/x", want: "Plugin-1.0.jar"},
+		{name: "published path", fileName: "dir/Plugin-1.0.jar", want: "Plugin-1.0.jar"},
+		{name: "backslashes", fileName: `..\..\evil.jar`, want: "evil.jar"},
+		{name: "parent directory", fileName: "..", version: "1.0", downloadURL: "https://example.com/dl/Plugin.jar", want: "Plugin.jar"},
+		{name: "root", fileName: "/", version: "1.0", want: "plugin-1.0.jar"},
+		{name: "url without jar", downloadURL: "https://example.com/download", version: "1.0", want: "plugin-1.0.jar"},
+		{name: "url with backslashes", downloadURL: `https://example.com/a\..\Plugin.JAR`, want: "Plugin.JAR"},
+		{name: "hostile version", version: "../../1.0", want: "plugin-.._.._1.0.jar"},
+		{name: "backslash version", version: `..\1.0`, want: "plugin-.._1.0.jar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			version := hangar.Version{Name: tt.version, Downloads: map[string]hangar.DownloadInfo{"PAPER": {}}}
+			if tt.fileName != "" {
+				version.Downloads["PAPER"] = hangar.DownloadInfo{FileInfo: &hangar.FileInfo{Name: tt.fileName}}
+			}
+
+			got := version.FileName("plugin", "PAPER", tt.downloadURL)
+			assert.Equal(t, tt.want, got)
+			assert.NotContains(t, got, "/")
+			assert.NotContains(t, got, `\`)
+		})
+	}
+}
+
+func TestSession_Claims(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		token   string
+		want    hangar.SessionClaims
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			token: "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice","id":1,"exp":1700000000}`)) + ".sig",
+			want:  hangar.SessionClaims{Subject: "alice", ExpiresAt: 1700000000},
+		},
+		{name: "not a JWT", token: "opaque", wantErr: true},
+		{name: "invalid payload", token: "a.!!!.c", wantErr: true},
+		{name: "invalid claims", token: "a." + base64.RawURLEncoding.EncodeToString([]byte("[]")) + ".c", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			claims, err := hangar.Session{Token: tt.token}.Claims()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, claims)
+		})
+	}
+}